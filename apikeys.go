@@ -0,0 +1,179 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// runAPIKey crea, lista y revoca las claves de API de los scripts e integraciones.
+// La clave se muestra una sola vez al crearla: la base de datos guarda solo su hash.
+//
+// Uso:
+//
+//	go run . apikey create -name marcador-tv -role scorer
+//	go run . apikey list
+//	go run . apikey revoke -id 3
+func runAPIKey(args []string) {
+	fs := flag.NewFlagSet("apikey", flag.ExitOnError)
+	kind := fs.String("store", "", "Base de datos: sqlite o postgres (por defecto la de la configuración del servidor)")
+	dsn := fs.String("db", "", "Ruta de la base de datos SQLite o cadena de conexión de PostgreSQL (por defecto la de la configuración)")
+	cfgPath := fs.String("config", "", "Archivo de configuración YAML del servidor (LALIGA_CONFIG)")
+	name := fs.String("name", "", "Con create, nombre del script o integración que usará la clave")
+	role := fs.String("role", roleViewer, "Con create, rol de la clave: viewer, scorer o admin")
+	id := fs.Int("id", 0, "Con revoke, ID de la clave")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Uso: apikey create -name nombre [-role viewer|scorer|admin] | list | revoke -id N [-store sqlite|postgres] [-db ruta] [-config archivo]")
+		fs.PrintDefaults()
+	}
+	if len(args) == 0 {
+		fs.Usage()
+		os.Exit(2)
+	}
+	action := args[0]
+	fs.Parse(args[1:])
+	storeFromConfig(*cfgPath, kind, dsn)
+
+	// Las claves del almacenamiento en memoria se perderían al terminar el comando
+	if *kind != storeSQLite && *kind != storePostgres {
+		log.Fatalf("apikey solo admite %s o %s", storeSQLite, storePostgres)
+	}
+
+	switch action {
+	case "create":
+		*name = strings.TrimSpace(*name)
+		if *name == "" {
+			log.Fatal("falta el nombre de la clave (-name)")
+		}
+		if !validRole(*role) {
+			log.Fatalf("rol inválido: %s (usa %s, %s o %s)", *role, roleViewer, roleScorer, roleAdmin)
+		}
+	case "list":
+	case "revoke":
+		if *id <= 0 {
+			log.Fatal("falta el ID de la clave (-id)")
+		}
+	default:
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	// openStore aplica las migraciones pendientes, así que la tabla api_keys existe
+	s, err := openStore(*kind, *dsn)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer s.Close()
+
+	switch action {
+	case "create":
+		// El nombre identifica la clave en la auditoría, así que no se repite; tampoco el
+		// de una clave revocada, para que sus entradas sigan siendo de una sola clave
+		keys, err := s.APIKeys()
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, k := range keys {
+			if strings.EqualFold(k.Name, *name) {
+				log.Fatalf("ya existe una clave con el nombre %s (ID %d); elige otro nombre", k.Name, k.ID)
+			}
+		}
+
+		key, prefix, hash := newAPIKey()
+		k, err := s.CreateAPIKey(APIKey{Name: *name, Role: *role, Prefix: prefix, Hash: hash})
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("Clave %d creada para %s con el rol %s:\n\n  %s\n\n", k.ID, k.Name, k.Role, key)
+		fmt.Printf("Guárdala ahora: no se vuelve a mostrar. Se envía en el encabezado %s.\n", apiKeyHeader)
+
+	case "list":
+		keys, err := s.APIKeys()
+		if err != nil {
+			log.Fatal(err)
+		}
+		tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "ID\tNOMBRE\tROL\tPREFIJO\tESTADO\tCREADA")
+		for _, k := range keys {
+			state := "activa"
+			if !k.Active {
+				state = "revocada"
+			}
+			fmt.Fprintf(tw, "%d\t%s\t%s\t%s…\t%s\t%s\n", k.ID, k.Name, k.Role, k.Prefix, state, k.CreatedAt)
+		}
+		tw.Flush()
+
+	case "revoke":
+		if err := s.RevokeAPIKey(*id); err == errNotFound {
+			log.Fatalf("no hay una clave activa con ID %d", *id)
+		} else if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("Clave %d revocada\n", *id)
+	}
+}
+
+// storeFromConfig completa el almacenamiento y la base de datos de los subcomandos que no
+// los recibieron por flag con los de la configuración del servidor
+func storeFromConfig(path string, kind, dsn *string) {
+	if *kind != "" && *dsn != "" {
+		return
+	}
+	cfg, err := commandConfig(path)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *kind == "" {
+		*kind = cfg.Store
+	}
+	if *dsn == "" {
+		*dsn = cfg.DSN
+	}
+}
+
+// runToken firma un token JWT para la interfaz web u otros clientes que no deben guardar
+// una clave de API. El servidor lo acepta si tiene configurado el mismo auth.jwt_secret.
+//
+// Uso: LALIGA_JWT_SECRET=... go run . token -sub interfaz -role scorer [-ttl 12h]
+//
+// Sin -secret toma auth.jwt_secret de la configuración del servidor (LALIGA_JWT_SECRET o el archivo).
+func runToken(args []string) {
+	fs := flag.NewFlagSet("token", flag.ExitOnError)
+	subject := fs.String("sub", "", "Quién usará el token (claim sub), por ejemplo el nombre del usuario")
+	role := fs.String("role", roleViewer, "Rol del token: viewer, scorer o admin")
+	ttl := fs.Duration("ttl", 12*time.Hour, "Duración del token")
+	secret := fs.String("secret", "", "Secreto HS256; por defecto auth.jwt_secret de la configuración (LALIGA_JWT_SECRET)")
+	cfgPath := fs.String("config", "", "Archivo de configuración YAML del servidor (LALIGA_CONFIG)")
+	fs.Parse(args)
+
+	// El secreto se lee de la configuración después de los flags para que -h no lo muestre
+	if *secret == "" {
+		cfg, err := commandConfig(*cfgPath)
+		if err != nil {
+			log.Fatal(err)
+		}
+		*secret = cfg.Auth.JWTSecret
+	}
+	if len(*secret) < minJWTSecret {
+		log.Fatalf("el secreto debe tener al menos %d caracteres (-secret, LALIGA_JWT_SECRET o auth.jwt_secret)", minJWTSecret)
+	}
+	if strings.TrimSpace(*subject) == "" {
+		log.Fatal("falta el titular del token (-sub)")
+	}
+	if !validRole(*role) {
+		log.Fatalf("rol inválido: %s (usa %s, %s o %s)", *role, roleViewer, roleScorer, roleAdmin)
+	}
+	if *ttl <= 0 {
+		log.Fatal("-ttl debe ser mayor que 0")
+	}
+
+	token, err := signToken(*secret, strings.TrimSpace(*subject), *role, *ttl)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(token)
+}
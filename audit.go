@@ -0,0 +1,150 @@
+package main
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// AuditEntry es un cambio registrado en el historial de auditoría: quién lo hizo,
+// por qué ruta y cómo quedó el partido o evento antes y después
+// @description Modelo que contiene una modificación de un partido o de sus eventos
+// @property id, actor, actorRole, action, method, route, path, matchId, before, after, requestId, remoteAddr, createdAt
+// @example { "id": 12, "actor": "marcador-tv", "actorRole": "scorer", "action": "goal.create", "method": "PATCH", "route": "/api/matches/{id}/goals", "path": "/api/matches/1/goals", "matchId": 1, "before": null, "after": { "id": 3, "teamId": 1, "team": "Real Madrid", "player": "Vinicius Jr.", "minute": "12:34" }, "requestId": "1fe55764dfdc350c23155ad4fd7673b4", "remoteAddr": "10.0.0.7:51234", "createdAt": "2026-10-16 09:00:11" }
+type AuditEntry struct {
+	ID         int             `json:"id"`
+	Actor      string          `json:"actor" example:"marcador-tv"`
+	ActorRole  string          `json:"actorRole,omitempty" example:"scorer"`
+	Action     string          `json:"action" example:"goal.create"`
+	Method     string          `json:"method" example:"PATCH"`
+	Route      string          `json:"route" example:"/api/matches/{id}/goals"`
+	Path       string          `json:"path" example:"/api/matches/1/goals"`
+	MatchID    int             `json:"matchId,omitempty" example:"1"`
+	Before     json.RawMessage `json:"before" swaggertype:"object"`
+	After      json.RawMessage `json:"after" swaggertype:"object"`
+	RequestID  string          `json:"requestId" example:"1fe55764dfdc350c23155ad4fd7673b4"`
+	RemoteAddr string          `json:"remoteAddr" example:"10.0.0.7:51234"`
+	CreatedAt  string          `json:"createdAt" example:"2026-10-16 09:00:11"`
+}
+
+// Acciones del historial de auditoría. Los goles y tarjetas usan el tipo de evento en vivo
+// como prefijo: goal.create, yellow_card.update, red_card.delete.
+const (
+	auditMatchCreate    = "match.create"
+	auditMatchUpdate    = "match.update"
+	auditMatchDelete    = "match.delete"
+	auditMatchExtraTime = "match.extra_time"
+	auditMatchStatus    = "match.status"
+	auditEventCreate    = "create"
+	auditEventUpdate    = "update"
+	auditEventDelete    = "delete"
+)
+
+// auditAnonymous es el actor de los cambios hechos con la autenticación desactivada
+const auditAnonymous = "anonymous"
+
+// auditFilter son los filtros de GET /api/audit
+type auditFilter struct {
+	MatchID int    // Solo las entradas del partido (0 para todos)
+	Actor   string // Solo las entradas del actor ("" para todos)
+	Before  int    // Solo las entradas con ID menor, para pedir la página siguiente (0 desde la última)
+	Limit   int
+}
+
+// audit registra un cambio hecho por la solicitud r. before y after son el estado anterior y
+// posterior de lo modificado (nil si no existía o ya no existe) y se guardan como JSON.
+// El cambio ya está hecho, así que un fallo al registrarlo se informa en los logs y no en la respuesta.
+func audit(r *http.Request, action string, matchID int, before, after any) {
+	e := AuditEntry{
+		Actor:      auditAnonymous,
+		Action:     action,
+		Method:     r.Method,
+		Route:      routeTemplate(r),
+		Path:       r.URL.Path,
+		MatchID:    matchID,
+		Before:     auditJSON(before),
+		After:      auditJSON(after),
+		RequestID:  requestID(r.Context()),
+		RemoteAddr: r.RemoteAddr,
+	}
+	if p, ok := principalFrom(r.Context()); ok {
+		e.Actor, e.ActorRole = p.Subject, p.Role
+	}
+	if err := store.RecordAudit(e); err != nil {
+		slog.ErrorContext(r.Context(), "no se pudo registrar la auditoría", "action", action, "match_id", matchID, "error", err)
+	}
+}
+
+// auditJSON convierte un estado en JSON; nil queda como null
+func auditJSON(v any) json.RawMessage {
+	if v == nil {
+		return nil
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	return b
+}
+
+// @Summary Historial de auditoría
+// @Description Retorna los cambios de partidos, goles, tarjetas, tiempo extra y estado, del más reciente al más antiguo,
+// @Description con quién los hizo, la ruta, el estado anterior y posterior y el ID de la solicitud.
+// @Description Para la página siguiente se pasa en before el ID de la última entrada recibida.
+// @Tags audit
+// @Accept json
+// @Produce json
+// @Param matchId query int false "Filtrar por partido"
+// @Param actor query string false "Filtrar por actor (nombre de la clave de API, sub del token o anonymous)"
+// @Param before query int false "Solo entradas con ID menor a este"
+// @Param limit query int false "Cantidad máxima de entradas (1-500, por defecto 100)"
+// @Success 200 {array} AuditEntry
+// @Failure 400 {object} Problem
+// @Failure 401 {object} Problem
+// @Failure 403 {object} Problem
+// @Failure 429 {object} Problem
+// @Failure 500 {object} Problem
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/audit [get]
+func getAudit(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	f := auditFilter{Actor: strings.TrimSpace(q.Get("actor")), Limit: 100}
+	var errs []FieldError
+
+	if v := q.Get("matchId"); v != "" {
+		if n, err := strconv.Atoi(v); err != nil || n < 1 {
+			errs = append(errs, FieldError{"matchId", fieldInvalid, "El parámetro matchId debe ser un ID de partido"})
+		} else {
+			f.MatchID = n
+		}
+	}
+	if v := q.Get("before"); v != "" {
+		if n, err := strconv.Atoi(v); err != nil || n < 1 {
+			errs = append(errs, FieldError{"before", fieldInvalid, "El parámetro before debe ser un ID de entrada"})
+		} else {
+			f.Before = n
+		}
+	}
+	if v := q.Get("limit"); v != "" {
+		if n, err := strconv.Atoi(v); err != nil || n < 1 || n > 500 {
+			errs = append(errs, FieldError{"limit", fieldOutOfRange, "El límite debe estar entre 1 y 500"})
+		} else {
+			f.Limit = n
+		}
+	}
+	if errs != nil {
+		writeValidation(w, r, errs...)
+		return
+	}
+
+	entries, err := store.AuditEntries(f)
+	if err != nil {
+		dbError(w, r, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(entries)
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+)
+
+func TestAuditEntries(t *testing.T) {
+	s := useMemoryStore(t)
+	useAuth(t, Auth{Enabled: true})
+	scorerKey, _ := createTestAPIKey(t, s, "mesa", roleScorer)
+	adminKey, _ := createTestAPIKey(t, s, "panel", roleAdmin)
+	teams := createTestTeams(t, s, "Athletic", "Betis")
+	matchID := createTestMatch(t, s, teams[0], teams[1], "2025-01-10", 0)
+	otherID := createTestMatch(t, s, teams[1], teams[0], "2025-01-17", 0)
+	id := strconv.Itoa(matchID)
+
+	// Las mismas rutas y middleware que el servidor para tener el ID de la solicitud y la plantilla de la ruta
+	r := mux.NewRouter()
+	r.Use(logRequests)
+	r.HandleFunc("/api/matches/{id}", require(roleAdmin, deleteMatch)).Methods("DELETE")
+	r.HandleFunc("/api/matches/{id}/goals", require(roleScorer, registerGoal)).Methods("PATCH")
+	r.HandleFunc("/api/matches/{id}/status", require(roleScorer, setMatchStatus)).Methods("PATCH")
+	r.HandleFunc("/api/audit", require(roleAdmin, getAudit)).Methods("GET")
+	send := func(method, target, key, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		req.Header.Set(apiKeyHeader, key)
+		req.Header.Set(requestIDHeader, "solicitud-"+strconv.Itoa(len(s.audit)+1))
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		return rec
+	}
+
+	for _, step := range []struct {
+		method, target, key, body string
+		status                    int
+	}{
+		{"PATCH", "/api/matches/" + id + "/status", scorerKey, `{"status":"live"}`, http.StatusOK},
+		{"PATCH", "/api/matches/" + id + "/goals", scorerKey, `{"teamId":` + strconv.Itoa(teams[0]) + `,"player":"Williams","minute":"10:00"}`, http.StatusOK},
+		// Las solicitudes rechazadas no dejan entradas
+		{"PATCH", "/api/matches/" + id + "/goals", scorerKey, `{"player":"Williams"}`, http.StatusBadRequest},
+		{"DELETE", "/api/matches/" + id, scorerKey, "", http.StatusForbidden},
+		{"DELETE", "/api/matches/" + strconv.Itoa(otherID), adminKey, "", http.StatusNoContent},
+	} {
+		if rec := send(step.method, step.target, step.key, step.body); rec.Code != step.status {
+			t.Fatalf("%s %s: código %d, se esperaba %d: %s", step.method, step.target, rec.Code, step.status, rec.Body.String())
+		}
+	}
+
+	var entries []AuditEntry
+	decodeResponse(t, send("GET", "/api/audit", adminKey, ""), http.StatusOK, &entries)
+	if len(entries) != 3 {
+		t.Fatalf("%d entradas, se esperaban 3: %+v", len(entries), entries)
+	}
+
+	// Del más reciente al más antiguo
+	status, goal, deleted := entries[2], entries[1], entries[0]
+	want := AuditEntry{
+		ID: status.ID, Actor: "mesa", ActorRole: roleScorer, Action: auditMatchStatus, Method: "PATCH",
+		Route: "/api/matches/{id}/status", Path: "/api/matches/" + id + "/status", MatchID: matchID,
+		Before: json.RawMessage(`{"status":"scheduled"}`), After: json.RawMessage(`{"status":"live"}`),
+		RequestID: "solicitud-1", RemoteAddr: status.RemoteAddr, CreatedAt: status.CreatedAt,
+	}
+	if !reflect.DeepEqual(status, want) {
+		t.Errorf("cambio de estado =\n%+v\nse esperaba\n%+v", status, want)
+	}
+
+	var after MatchEvent
+	json.Unmarshal(goal.After, &after)
+	if goal.Action != "goal.create" || goal.Route != "/api/matches/{id}/goals" || string(goal.Before) != "null" || after.Player != "Williams" || after.TeamID != teams[0] {
+		t.Errorf("gol = %+v con after %s", goal, goal.After)
+	}
+
+	var before FullMatchData
+	json.Unmarshal(deleted.Before, &before)
+	if deleted.Actor != "panel" || deleted.ActorRole != roleAdmin || deleted.Action != auditMatchDelete || deleted.MatchID != otherID || before.ID != otherID || string(deleted.After) != "null" {
+		t.Errorf("eliminación = %+v con before %s", deleted, deleted.Before)
+	}
+
+	// Filtros y paginación
+	for _, tt := range []struct {
+		query string
+		want  []int
+	}{
+		{"?actor=mesa", []int{goal.ID, status.ID}},
+		{"?matchId=" + strconv.Itoa(otherID), []int{deleted.ID}},
+		{"?limit=2", []int{deleted.ID, goal.ID}},
+		{"?before=" + strconv.Itoa(goal.ID), []int{status.ID}},
+	} {
+		var page []AuditEntry
+		decodeResponse(t, send("GET", "/api/audit"+tt.query, adminKey, ""), http.StatusOK, &page)
+		ids := []int{}
+		for _, e := range page {
+			ids = append(ids, e.ID)
+		}
+		if !reflect.DeepEqual(ids, tt.want) {
+			t.Errorf("%s: entradas %v, se esperaban %v", tt.query, ids, tt.want)
+		}
+	}
+	decodeProblem(t, send("GET", "/api/audit?limit=0&before=x", adminKey, ""), http.StatusBadRequest, codeValidationFailed)
+
+	// Solo los administradores consultan el historial
+	decodeProblem(t, send("GET", "/api/audit", scorerKey, ""), http.StatusForbidden, codeInsufficientRole)
+}
+
+func TestAuditAnonymous(t *testing.T) {
+	s := useMemoryStore(t)
+	useAuth(t, Auth{})
+	teams := createTestTeams(t, s, "Athletic", "Betis")
+	matchID := createTestMatch(t, s, teams[0], teams[1], "2025-01-10", 0)
+
+	if rec := patchStatus(matchID, `{"status":"postponed"}`); rec.Code != http.StatusOK {
+		t.Fatalf("código %d: %s", rec.Code, rec.Body.String())
+	}
+	entries, err := s.AuditEntries(auditFilter{Limit: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Actor != auditAnonymous || entries[0].ActorRole != "" {
+		t.Errorf("entradas = %+v, se esperaba una anónima y sin rol", entries)
+	}
+}
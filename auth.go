@@ -0,0 +1,243 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Roles de la API, de menor a mayor permiso. Cada rol puede hacer todo lo que hace el anterior.
+const (
+	roleViewer = "viewer" // Consultas (GET), streams en vivo incluidos
+	roleScorer = "scorer" // Además registra goles y tarjetas, el tiempo extra y el estado del partido
+	roleAdmin  = "admin"  // Además crea, corrige y elimina partidos, equipos, jugadores, eventos y webhooks
+)
+
+// roleLevels ordena los roles para comparar permisos
+var roleLevels = map[string]int{roleViewer: 1, roleScorer: 2, roleAdmin: 3}
+
+// validRole indica si role es uno de los roles de la API
+func validRole(role string) bool {
+	return roleLevels[role] > 0
+}
+
+// Formas de autenticarse
+const (
+	authAPIKey = "api_key" // Encabezado X-API-Key, para scripts e integraciones
+	authJWT    = "jwt"     // Authorization: Bearer, para la interfaz web
+)
+
+// apiKeyHeader es el encabezado con el que se envía una clave de API
+const apiKeyHeader = "X-API-Key"
+
+// accessTokenParam permite enviar el token en la URL a EventSource y WebSocket,
+// que no pueden agregar encabezados desde el navegador. Solo se acepta en los GET.
+const accessTokenParam = "access_token"
+
+// Principal es quien hace una solicitud autenticada
+type Principal struct {
+	Subject string // Nombre de la clave de API o claim sub del token
+	Role    string // viewer, scorer o admin
+	Method  string // api_key o jwt
+	KeyID   int    // ID de la clave de API; 0 con un token
+}
+
+// rateLimitKey identifica el presupuesto de solicitudes de la credencial: el ID de la
+// clave de API, que no cambia aunque se cree otra con el mismo nombre, o el sub del token
+func (p Principal) rateLimitKey() string {
+	if p.Method == authAPIKey {
+		return authAPIKey + ":" + strconv.Itoa(p.KeyID)
+	}
+	return p.Method + ":" + p.Subject
+}
+
+// principalKey es la clave del Principal en el contexto
+type principalKey struct{}
+
+// principalFrom devuelve quién hizo la solicitud. No hay Principal si la autenticación
+// está desactivada o si la ruta es de lectura pública y no se enviaron credenciales.
+func principalFrom(ctx context.Context) (Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(Principal)
+	return p, ok
+}
+
+// Errores de autenticación. Los handlers los traducen a 401.
+var (
+	errNoCredentials      = errors.New("la solicitud no tiene credenciales")
+	errInvalidCredentials = errors.New("credenciales inválidas")
+)
+
+// ================================================================
+// Claves de API
+// ================================================================
+
+// APIKey es una clave de API para scripts e integraciones. La base de datos guarda
+// solo el hash SHA-256 de la clave; Prefix sirve para reconocerla en los listados.
+type APIKey struct {
+	ID        int
+	Name      string
+	Role      string
+	Prefix    string
+	Hash      string
+	Active    bool
+	CreatedAt string
+}
+
+// apiKeyPrefix identifica las claves de esta API, por ejemplo en un escáner de secretos
+const apiKeyPrefix = "llt_"
+
+// newAPIKey genera una clave aleatoria de 256 bits y devuelve la clave, su prefijo visible y su hash
+func newAPIKey() (key, prefix, hash string) {
+	b := make([]byte, 32)
+	rand.Read(b)
+	key = apiKeyPrefix + base64.RawURLEncoding.EncodeToString(b)
+	return key, key[:len(apiKeyPrefix)+8], hashAPIKey(key)
+}
+
+// hashAPIKey devuelve el hash con el que se busca una clave. Las claves son aleatorias
+// y largas, así que alcanza con SHA-256 sin sal ni iteraciones.
+func hashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// ================================================================
+// Tokens JWT
+// ================================================================
+
+// jwtIssuer es el emisor de los tokens firmados con el comando token
+const jwtIssuer = "laligatracker"
+
+// minJWTSecret es el largo mínimo del secreto HS256 (256 bits)
+const minJWTSecret = 32
+
+// jwtLeeway tolera diferencias de reloj al validar exp y nbf
+const jwtLeeway = 30 * time.Second
+
+// tokenClaims son los claims de los tokens de la API: los registrados más el rol
+type tokenClaims struct {
+	Role string `json:"role"`
+	jwt.RegisteredClaims
+}
+
+// signToken firma un token HS256 para subject con el rol y la duración indicados
+func signToken(secret, subject, role string, ttl time.Duration) (string, error) {
+	now := time.Now()
+	claims := tokenClaims{
+		Role: role,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    jwtIssuer,
+			Subject:   subject,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+		},
+	}
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+}
+
+// parseToken valida la firma, el vencimiento y el rol de un token. Solo se acepta HS256
+// para que un token con alg "none" o RS256 no pase la verificación, y exp es obligatorio.
+func parseToken(secret, raw string) (Principal, error) {
+	var claims tokenClaims
+	_, err := jwt.ParseWithClaims(raw, &claims, func(*jwt.Token) (any, error) {
+		return []byte(secret), nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithExpirationRequired(), jwt.WithLeeway(jwtLeeway))
+	if err != nil || claims.Subject == "" || !validRole(claims.Role) {
+		return Principal{}, errInvalidCredentials
+	}
+	return Principal{Subject: claims.Subject, Role: claims.Role, Method: authJWT}, nil
+}
+
+// ================================================================
+// Autenticación de las solicitudes
+// ================================================================
+
+// authenticate identifica a quien hace la solicitud por su clave de API o su token.
+// Devuelve errNoCredentials si no envió ninguno y errInvalidCredentials si no son válidos;
+// cualquier otro error es de la base de datos.
+func authenticate(r *http.Request) (Principal, error) {
+	if key := r.Header.Get(apiKeyHeader); key != "" {
+		k, err := store.APIKeyByHash(hashAPIKey(key))
+		if errors.Is(err, errNotFound) {
+			return Principal{}, errInvalidCredentials
+		}
+		if err != nil {
+			return Principal{}, err
+		}
+		return Principal{Subject: k.Name, Role: k.Role, Method: authAPIKey, KeyID: k.ID}, nil
+	}
+
+	var raw string
+	if h := r.Header.Get("Authorization"); h != "" {
+		scheme, token, ok := strings.Cut(h, " ")
+		if !ok || !strings.EqualFold(scheme, "Bearer") {
+			return Principal{}, errInvalidCredentials
+		}
+		raw = strings.TrimSpace(token)
+	} else if r.Method == http.MethodGet {
+		raw = r.URL.Query().Get(accessTokenParam)
+	}
+	if raw == "" {
+		return Principal{}, errNoCredentials
+	}
+	// Sin secreto configurado los tokens están desactivados
+	if config.Auth.JWTSecret == "" {
+		return Principal{}, errInvalidCredentials
+	}
+	return parseToken(config.Auth.JWTSecret, raw)
+}
+
+// require protege un handler con el rol mínimo indicado. Con la autenticación desactivada,
+// o si es una ruta de lectura y auth.public_reads está activo, no se exigen credenciales;
+// si se envían igual tienen que ser válidas. Quien pasa la verificación queda en el
+// contexto de la solicitud (ver principalFrom).
+//
+// Antes de verificar el rol se aplica el límite de solicitudes: por clave de API o token si
+// las credenciales son válidas y si no por IP, para que quien prueba credenciales falsas
+// tampoco pase del límite.
+func require(role string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		public := !config.Auth.Enabled || (role == roleViewer && config.Auth.PublicReads)
+
+		p, err := Principal{}, errNoCredentials
+		if config.Auth.Enabled {
+			p, err = authenticate(r)
+		}
+
+		key := "ip:" + clientIP(r)
+		if err == nil {
+			key = p.rateLimitKey()
+		}
+		if !limitRequest(w, r, key) {
+			return
+		}
+
+		switch {
+		case public && errors.Is(err, errNoCredentials):
+			next(w, r)
+		case errors.Is(err, errNoCredentials):
+			w.Header().Set("WWW-Authenticate", `Bearer realm="laligatracker"`)
+			writeError(w, r, http.StatusUnauthorized, codeAuthRequired, "Se requiere una clave de API (X-API-Key) o un token (Authorization: Bearer)")
+		case errors.Is(err, errInvalidCredentials):
+			w.Header().Set("WWW-Authenticate", `Bearer realm="laligatracker", error="invalid_token"`)
+			writeError(w, r, http.StatusUnauthorized, codeInvalidCredentials, "La clave de API o el token no es válido o está vencido")
+		case err != nil:
+			dbError(w, r, err)
+		case roleLevels[p.Role] < roleLevels[role]:
+			writeError(w, r, http.StatusForbidden, codeInsufficientRole,
+				fmt.Sprintf("El rol %s no tiene permiso para esta operación; se requiere %s", p.Role, role))
+		default:
+			next(w, r.WithContext(context.WithValue(r.Context(), principalKey{}, p)))
+		}
+	}
+}
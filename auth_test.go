@@ -0,0 +1,202 @@
+package main
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// testJWTSecret es el secreto HS256 de los tokens de las pruebas
+const testJWTSecret = "secreto-de-prueba-de-32-caracteres!"
+
+// useAuth deja auth como configuración de autenticación y restaura la anterior al terminar la prueba
+func useAuth(t *testing.T, auth Auth) {
+	t.Helper()
+	prev := config.Auth
+	config.Auth = auth
+	t.Cleanup(func() { config.Auth = prev })
+}
+
+// testToken firma claims con el método y la clave indicados
+func testToken(t *testing.T, method jwt.SigningMethod, key any, claims jwt.Claims) string {
+	t.Helper()
+	raw, err := jwt.NewWithClaims(method, claims).SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return raw
+}
+
+// testClaims devuelve claims válidos de un anotador que vencen en ttl
+func testClaims(ttl time.Duration) tokenClaims {
+	return tokenClaims{
+		Role: roleScorer,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   "mesa",
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(ttl)),
+		},
+	}
+}
+
+func TestParseToken(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hs256 := jwt.SigningMethodHS256
+	secret := []byte(testJWTSecret)
+
+	withoutExp := testClaims(time.Hour)
+	withoutExp.ExpiresAt = nil
+	withoutSubject := testClaims(time.Hour)
+	withoutSubject.Subject = ""
+	unknownRole := testClaims(time.Hour)
+	unknownRole.Role = "owner"
+
+	signed, err := signToken(testJWTSecret, "panel", roleAdmin, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		token string
+		valid bool
+	}{
+		{"firmado por signToken", signed, true},
+		{"HS256 válido", testToken(t, hs256, secret, testClaims(time.Hour)), true},
+		{"vencido dentro del margen de reloj", testToken(t, hs256, secret, testClaims(-10*time.Second)), true},
+		{"vencido", testToken(t, hs256, secret, testClaims(-time.Minute)), false},
+		{"sin exp", testToken(t, hs256, secret, withoutExp), false},
+		{"sin sub", testToken(t, hs256, secret, withoutSubject), false},
+		{"rol desconocido", testToken(t, hs256, secret, unknownRole), false},
+		{"otro secreto", testToken(t, hs256, []byte("otro-secreto-de-prueba-de-32-caracteres"), testClaims(time.Hour)), false},
+		{"alg none", testToken(t, jwt.SigningMethodNone, jwt.UnsafeAllowNoneSignatureType, testClaims(time.Hour)), false},
+		{"HS512", testToken(t, jwt.SigningMethodHS512, secret, testClaims(time.Hour)), false},
+		{"RS256", testToken(t, jwt.SigningMethodRS256, rsaKey, testClaims(time.Hour)), false},
+		{"mal formado", "no.es.un-token", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := parseToken(testJWTSecret, tt.token)
+			if !tt.valid {
+				if err != errInvalidCredentials {
+					t.Errorf("parseToken = %+v, %v; se esperaba errInvalidCredentials", p, err)
+				}
+				return
+			}
+			if err != nil || p.Subject == "" || !validRole(p.Role) || p.Method != authJWT {
+				t.Errorf("parseToken = %+v, %v; se esperaba un token válido", p, err)
+			}
+		})
+	}
+}
+
+// createTestAPIKey registra una clave de API con el rol indicado y devuelve la clave en claro
+func createTestAPIKey(t *testing.T, s Store, name, role string) (string, APIKey) {
+	t.Helper()
+	key, prefix, hash := newAPIKey()
+	k, err := s.CreateAPIKey(APIKey{Name: name, Role: role, Prefix: prefix, Hash: hash})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key, k
+}
+
+// whoami responde con quién hizo la solicitud, o anonymous si no hay Principal
+func whoami(w http.ResponseWriter, r *http.Request) {
+	p, ok := principalFrom(r.Context())
+	if !ok {
+		p.Subject = auditAnonymous
+	}
+	json.NewEncoder(w).Encode(p)
+}
+
+func TestRequire(t *testing.T) {
+	s := useMemoryStore(t)
+	viewerKey, _ := createTestAPIKey(t, s, "tablero", roleViewer)
+	scorerKey, scorer := createTestAPIKey(t, s, "mesa", roleScorer)
+	adminKey, _ := createTestAPIKey(t, s, "panel", roleAdmin)
+	revokedKey, revoked := createTestAPIKey(t, s, "vieja", roleAdmin)
+	if err := s.RevokeAPIKey(revoked.ID); err != nil {
+		t.Fatal(err)
+	}
+	scorerToken, _ := signToken(testJWTSecret, "app", roleScorer, time.Hour)
+	expiredToken := testToken(t, jwt.SigningMethodHS256, []byte(testJWTSecret), testClaims(-time.Hour))
+
+	type request struct {
+		method string
+		role   string // Rol mínimo de la ruta
+		header string
+		value  string
+		query  string
+	}
+	tests := []struct {
+		name        string
+		publicReads bool
+		req         request
+		status      int
+		code        string // Código del problema si no es 200
+		subject     string // Principal esperado si es 200
+	}{
+		{"sin credenciales", false, request{http.MethodPatch, roleScorer, "", "", ""}, http.StatusUnauthorized, codeAuthRequired, ""},
+		{"clave desconocida", false, request{http.MethodPatch, roleScorer, apiKeyHeader, "llt_desconocida", ""}, http.StatusUnauthorized, codeInvalidCredentials, ""},
+		{"clave revocada", false, request{http.MethodPatch, roleScorer, apiKeyHeader, revokedKey, ""}, http.StatusUnauthorized, codeInvalidCredentials, ""},
+		{"rol insuficiente", false, request{http.MethodPatch, roleScorer, apiKeyHeader, viewerKey, ""}, http.StatusForbidden, codeInsufficientRole, ""},
+		{"rol exacto", false, request{http.MethodPatch, roleScorer, apiKeyHeader, scorerKey, ""}, http.StatusOK, "", "mesa"},
+		{"rol superior", false, request{http.MethodPatch, roleScorer, apiKeyHeader, adminKey, ""}, http.StatusOK, "", "panel"},
+		{"anotador en una ruta de administración", false, request{http.MethodDelete, roleAdmin, apiKeyHeader, scorerKey, ""}, http.StatusForbidden, codeInsufficientRole, ""},
+		{"token", false, request{http.MethodPatch, roleScorer, "Authorization", "Bearer " + scorerToken, ""}, http.StatusOK, "", "app"},
+		{"token vencido", false, request{http.MethodPatch, roleScorer, "Authorization", "Bearer " + expiredToken, ""}, http.StatusUnauthorized, codeInvalidCredentials, ""},
+		{"esquema distinto de Bearer", false, request{http.MethodPatch, roleScorer, "Authorization", "Basic " + scorerToken, ""}, http.StatusUnauthorized, codeInvalidCredentials, ""},
+		{"token en la URL de un GET", false, request{http.MethodGet, roleViewer, "", "", "?access_token=" + scorerToken}, http.StatusOK, "", "app"},
+		{"token en la URL de un PATCH", false, request{http.MethodPatch, roleScorer, "", "", "?access_token=" + scorerToken}, http.StatusUnauthorized, codeAuthRequired, ""},
+		{"lectura pública sin credenciales", true, request{http.MethodGet, roleViewer, "", "", ""}, http.StatusOK, "", auditAnonymous},
+		{"lectura pública con clave inválida", true, request{http.MethodGet, roleViewer, apiKeyHeader, "llt_desconocida", ""}, http.StatusUnauthorized, codeInvalidCredentials, ""},
+		{"lectura pública con clave", true, request{http.MethodGet, roleViewer, apiKeyHeader, viewerKey, ""}, http.StatusOK, "", "tablero"},
+		{"lectura pública no abre las escrituras", true, request{http.MethodPatch, roleScorer, "", "", ""}, http.StatusUnauthorized, codeAuthRequired, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useAuth(t, Auth{Enabled: true, PublicReads: tt.publicReads, JWTSecret: testJWTSecret})
+			req := httptest.NewRequest(tt.req.method, "/api/recurso"+tt.req.query, nil)
+			if tt.req.header != "" {
+				req.Header.Set(tt.req.header, tt.req.value)
+			}
+			rec := serveRoute("/api/recurso", require(tt.req.role, whoami), req)
+
+			if tt.status != http.StatusOK {
+				decodeProblem(t, rec, tt.status, tt.code)
+				if tt.status == http.StatusUnauthorized && rec.Header().Get("WWW-Authenticate") == "" {
+					t.Error("un 401 sin WWW-Authenticate")
+				}
+				return
+			}
+			var p Principal
+			decodeResponse(t, rec, http.StatusOK, &p)
+			if p.Subject != tt.subject {
+				t.Errorf("principal %+v, se esperaba %s", p, tt.subject)
+			}
+		})
+	}
+
+	// La clave de API se identifica por su ID para el límite de solicitudes
+	if got := (Principal{Subject: "mesa", Method: authAPIKey, KeyID: scorer.ID}).rateLimitKey(); got != "api_key:"+strconv.Itoa(scorer.ID) {
+		t.Errorf("rateLimitKey = %s, se esperaba api_key:%d", got, scorer.ID)
+	}
+
+	// Con la autenticación desactivada no se piden credenciales ni en las rutas de administración
+	useAuth(t, Auth{})
+	var p Principal
+	decodeResponse(t, serveRoute("/api/recurso", require(roleAdmin, whoami), httptest.NewRequest(http.MethodDelete, "/api/recurso", nil)), http.StatusOK, &p)
+	if p.Subject != auditAnonymous {
+		t.Errorf("sin autenticación: principal %+v, se esperaba anónimo", p)
+	}
+}
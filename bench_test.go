@@ -0,0 +1,253 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// benchMatches es la cantidad de partidos de la base de datos de los benchmarks
+const benchMatches = 10000
+
+// Benchmarks del listado y el detalle de partidos contra la versión anterior, que contaba
+// los goles y tarjetas con una consulta por tabla y por partido:
+//
+//	go test -run '^$' -bench . -benchmem
+
+// BenchmarkGetMatches compara el listado de la página más grande
+func BenchmarkGetMatches(b *testing.B) {
+	db := newBenchStore(b)
+	benchHandlers(b, "/api/matches?limit="+strconv.Itoa(maxMatchesLimit), "", legacyGetMatches(db), getMatches)
+}
+
+// BenchmarkGetMatch compara el detalle de un partido del medio
+func BenchmarkGetMatch(b *testing.B) {
+	db := newBenchStore(b)
+	id := strconv.Itoa(benchMatches / 2)
+	benchHandlers(b, "/api/matches/"+id, id, legacyGetMatch(db), getMatch)
+}
+
+// benchHandlers verifica que ambas versiones devuelvan exactamente lo mismo y luego mide cada una
+func benchHandlers(b *testing.B, url, id string, legacy, current http.HandlerFunc) {
+	if l, c := benchResponse(b, legacy, url, id), benchResponse(b, current, url, id); l != c {
+		b.Fatalf("la versión anterior y la agregada devuelven resultados distintos:\n%s\n%s", l, c)
+	}
+
+	for _, c := range []struct {
+		name    string
+		handler http.HandlerFunc
+	}{{"legacy", legacy}, {"aggregated", current}} {
+		b.Run(c.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for n := 0; n < b.N; n++ {
+				benchResponse(b, c.handler, url, id)
+			}
+		})
+	}
+}
+
+// benchResponse ejecuta un handler y devuelve el cuerpo de la respuesta
+func benchResponse(b *testing.B, handler http.HandlerFunc, url, id string) string {
+	req := httptest.NewRequest(http.MethodGet, url, nil)
+	if id != "" {
+		req = mux.SetURLVars(req, map[string]string{"id": id})
+	}
+	rec := httptest.NewRecorder()
+	handler(rec, req)
+	if rec.Code != http.StatusOK {
+		b.Fatalf("%s respondió %d: %s", url, rec.Code, rec.Body.String())
+	}
+	return rec.Body.String()
+}
+
+// newBenchStore crea una base de datos SQLite temporal con las migraciones, los datos de ejemplo
+// y benchMatches partidos aleatorios, y la deja como almacenamiento de los handlers.
+// Devuelve la conexión que usan las versiones anteriores, que consultan la base directamente.
+func newBenchStore(b *testing.B) *sql.DB {
+	s, err := newSQLiteStore(filepath.Join(b.TempDir(), "bench.db"))
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.Cleanup(func() { s.Close() })
+
+	if err := s.migrateUp(); err != nil {
+		b.Fatal(err)
+	}
+	if _, err := s.seedSampleData(); err != nil {
+		b.Fatal(err)
+	}
+
+	start := time.Now()
+	events, err := seedBenchMatches(s.db, benchMatches, rand.New(rand.NewSource(1)))
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.Logf("base de datos con %d partidos y %d eventos generada en %s", benchMatches, events, time.Since(start).Round(time.Millisecond))
+
+	prev := store
+	store = s
+	b.Cleanup(func() { store = prev })
+	return s.db
+}
+
+// seedBenchMatches inserta n partidos terminados entre los equipos existentes,
+// con una cantidad aleatoria de goles y tarjetas, y devuelve el total de eventos
+func seedBenchMatches(db *sql.DB, n int, rng *rand.Rand) (int, error) {
+	var teamIDs []int
+	rows, err := db.Query("SELECT id FROM teams")
+	if err != nil {
+		return 0, err
+	}
+	for rows.Next() {
+		var id int
+		rows.Scan(&id)
+		teamIDs = append(teamIDs, id)
+	}
+	rows.Close()
+	if len(teamIDs) < 2 {
+		return 0, fmt.Errorf("se necesitan al menos dos equipos en el esquema")
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	insertMatch, err := tx.Prepare(`INSERT INTO matches (home_team_id, away_team_id, match_date, extra_time, status, status_updated_at)
+		VALUES (?, ?, ?, '00:00', 'finished', ?)`)
+	if err != nil {
+		return 0, err
+	}
+	insertEvent := map[string]*sql.Stmt{}
+	for _, table := range []string{"goals", "yellow_cards", "red_cards"} {
+		insertEvent[table], err = tx.Prepare("INSERT INTO " + table + " (match_id, team_id, player, minute) VALUES (?, ?, ?, ?)")
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	// Cantidad máxima de eventos por partido de cada tipo
+	maxEvents := map[string]int{"goals": 6, "yellow_cards": 7, "red_cards": 2}
+
+	events := 0
+	day := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < n; i++ {
+		home := teamIDs[rng.Intn(len(teamIDs))]
+		away := teamIDs[rng.Intn(len(teamIDs))]
+		for away == home {
+			away = teamIDs[rng.Intn(len(teamIDs))]
+		}
+
+		date := day.AddDate(0, 0, i/4)
+		res, err := insertMatch.Exec(home, away, date.Format(time.DateOnly), date.Add(22*time.Hour).Format(time.DateTime))
+		if err != nil {
+			return 0, err
+		}
+		matchID, _ := res.LastInsertId()
+
+		for table, max := range maxEvents {
+			for k := rng.Intn(max); k > 0; k-- {
+				team := home
+				if rng.Intn(2) == 1 {
+					team = away
+				}
+				minute := fmt.Sprintf("%02d:%02d", rng.Intn(90), rng.Intn(60))
+				if _, err := insertEvent[table].Exec(matchID, team, "Jugador "+strconv.Itoa(rng.Intn(30)+1), minute); err != nil {
+					return 0, err
+				}
+				events++
+			}
+		}
+	}
+
+	return events, tx.Commit()
+}
+
+// legacyGetMatches reproduce el listado anterior: seis consultas COUNT por partido.
+// Solo respeta el parámetro limit, para comparar páginas del mismo tamaño.
+func legacyGetMatches(db *sql.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		lq, _, _ := parseMatchListQuery(r)
+		rows, err := db.Query(matchSelect+" ORDER BY m.id LIMIT ?", lq.Limit)
+		if err != nil {
+			http.Error(w, err.Error(), 500)
+			return
+		}
+		defer rows.Close()
+
+		matches := []FullMatchData{}
+		for rows.Next() {
+			var m FullMatchData
+			if err := rows.Scan(&m.ID, &m.HomeTeamID, &m.HomeTeam, &m.AwayTeamID, &m.AwayTeam, &m.MatchDate, &m.ExtraTime, &m.Status, &m.SeasonID); err != nil {
+				http.Error(w, err.Error(), 500)
+				return
+			}
+			legacyCountEvents(db, &m)
+			matches = append(matches, m)
+		}
+
+		json.NewEncoder(w).Encode(matches)
+	}
+}
+
+// legacyGetMatch reproduce el detalle anterior: seis consultas COUNT y una por cada tabla de eventos
+func legacyGetMatch(db *sql.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := mux.Vars(r)["id"]
+
+		var m FullMatchData
+		err := db.QueryRow(matchSelect+" WHERE m.id = ?", id).
+			Scan(&m.ID, &m.HomeTeamID, &m.HomeTeam, &m.AwayTeamID, &m.AwayTeam, &m.MatchDate, &m.ExtraTime, &m.Status, &m.SeasonID)
+		if err != nil {
+			http.Error(w, "Partido no encontrado", 404)
+			return
+		}
+		legacyCountEvents(db, &m)
+
+		for _, table := range []string{"goals", "yellow_cards", "red_cards"} {
+			var events []MatchEvent
+			rows, err := db.Query("SELECT e.id, e.team_id, t.name, COALESCE(e.player_id, 0), e.player, e.minute FROM "+table+" e JOIN teams t ON t.id = e.team_id WHERE e.match_id = ?", id)
+			if err != nil {
+				continue
+			}
+			for rows.Next() {
+				var e MatchEvent
+				if err := rows.Scan(&e.ID, &e.TeamID, &e.Team, &e.PlayerID, &e.Player, &e.Minute); err == nil {
+					events = append(events, e)
+				}
+			}
+			rows.Close()
+
+			switch table {
+			case "goals":
+				m.Goals = events
+			case "yellow_cards":
+				m.YellowCards = events
+			case "red_cards":
+				m.RedCards = events
+			}
+		}
+
+		json.NewEncoder(w).Encode(m)
+	}
+}
+
+// legacyCountEvents cuenta goles y tarjetas con una consulta por tabla y por equipo
+func legacyCountEvents(db *sql.DB, m *FullMatchData) {
+	db.QueryRow("SELECT COUNT(*) FROM goals WHERE match_id = ? AND team_id = ?", m.ID, m.HomeTeamID).Scan(&m.HomeGoals)
+	db.QueryRow("SELECT COUNT(*) FROM goals WHERE match_id = ? AND team_id = ?", m.ID, m.AwayTeamID).Scan(&m.AwayGoals)
+	db.QueryRow("SELECT COUNT(*) FROM yellow_cards WHERE match_id = ? AND team_id = ?", m.ID, m.HomeTeamID).Scan(&m.HomeYellowCardsCount)
+	db.QueryRow("SELECT COUNT(*) FROM yellow_cards WHERE match_id = ? AND team_id = ?", m.ID, m.AwayTeamID).Scan(&m.AwayYellowCardsCount)
+	db.QueryRow("SELECT COUNT(*) FROM red_cards WHERE match_id = ? AND team_id = ?", m.ID, m.HomeTeamID).Scan(&m.HomeRedCardsCount)
+	db.QueryRow("SELECT COUNT(*) FROM red_cards WHERE match_id = ? AND team_id = ?", m.ID, m.AwayTeamID).Scan(&m.AwayRedCardsCount)
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"sync"
+	"time"
+)
+
+// Tipos de eventos en vivo que se publican cuando cambia un partido
+const (
+	LiveGoal          = "goal"
+	LiveYellowCard    = "yellow_card"
+	LiveRedCard       = "red_card"
+	LiveExtraTime     = "extra_time"
+	LiveMatchUpdated  = "match_updated"
+	LiveStatusChanged = "status_changed"
+	LiveMatchDeleted  = "match_deleted"
+	LiveEventUpdated  = "event_updated"
+	LiveEventDeleted  = "event_deleted"
+)
+
+// liveEventTypes mapea cada tabla de eventos al tipo de evento en vivo que genera
+var liveEventTypes = map[string]string{
+	"goals":        LiveGoal,
+	"yellow_cards": LiveYellowCard,
+	"red_cards":    LiveRedCard,
+}
+
+// LiveEvent representa un cambio en un partido que se envía a los clientes conectados
+// @description Modelo que contiene un evento en vivo de un partido
+// @property id, type, matchId, time, data
+type LiveEvent struct {
+	ID      uint64    `json:"id"`
+	Type    string    `json:"type"`
+	MatchID int       `json:"matchId"`
+	Time    time.Time `json:"time"`
+	Data    any       `json:"data"`
+}
+
+// subscriber es un cliente suscrito a los eventos de un partido (o de toda la liga si matchID es 0)
+type subscriber struct {
+	matchID int
+	ch      chan LiveEvent
+}
+
+// Broker distribuye los eventos en vivo a los suscriptores y guarda un historial
+// reciente para que los clientes puedan reanudar desde el último evento recibido.
+//
+// Publish nunca bloquea: si un suscriptor no consume sus eventos a tiempo y su
+// buffer se llena, se le cierra el canal y debe reconectarse usando el último ID.
+type Broker struct {
+	mu      sync.Mutex
+	lastID  uint64
+	history []LiveEvent
+	size    int
+	subs    map[*subscriber]struct{}
+	closed  bool
+}
+
+// subscriberBuffer es la cantidad de eventos pendientes que puede acumular un suscriptor
+const subscriberBuffer = 64
+
+// broker es la instancia global que usan los handlers para publicar eventos
+var broker = newBroker(1024)
+
+// newBroker crea un broker que conserva los últimos size eventos
+func newBroker(size int) *Broker {
+	return &Broker{size: size, subs: make(map[*subscriber]struct{})}
+}
+
+// Publish asigna un ID al evento, lo guarda en el historial y lo envía a los suscriptores
+func (b *Broker) Publish(matchID int, eventType string, data any) LiveEvent {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.lastID++
+	ev := LiveEvent{ID: b.lastID, Type: eventType, MatchID: matchID, Time: time.Now().UTC(), Data: data}
+
+	// Mantener solo los últimos size eventos
+	b.history = append(b.history, ev)
+	if len(b.history) > b.size {
+		b.history = b.history[len(b.history)-b.size:]
+	}
+
+	for sub := range b.subs {
+		if sub.matchID != 0 && sub.matchID != matchID {
+			continue
+		}
+		select {
+		case sub.ch <- ev:
+		default:
+			// El suscriptor está atrasado; se desconecta para no bloquear al resto
+			delete(b.subs, sub)
+			close(sub.ch)
+		}
+	}
+	return ev
+}
+
+// Subscribe registra un suscriptor para matchID (0 para todos los partidos).
+// Devuelve los eventos posteriores a lastID que siguen en el historial y un indicador
+// de si el historial alcanza para reanudar sin perder eventos. Si no alcanza, el
+// cliente debe volver a consultar el estado completo del partido.
+func (b *Broker) Subscribe(matchID int, lastID uint64) (*subscriber, []LiveEvent, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	sub := &subscriber{matchID: matchID, ch: make(chan LiveEvent, subscriberBuffer)}
+	if b.closed {
+		close(sub.ch)
+		return sub, nil, true
+	}
+	b.subs[sub] = struct{}{}
+
+	if lastID == 0 {
+		return sub, nil, true
+	}
+
+	// Un ID mayor al último publicado significa que el servidor se reinició
+	complete := lastID <= b.lastID
+	if len(b.history) > 0 && b.history[0].ID > lastID+1 {
+		complete = false
+	}
+
+	var backlog []LiveEvent
+	for _, ev := range b.history {
+		if ev.ID > lastID && (matchID == 0 || ev.MatchID == matchID) {
+			backlog = append(backlog, ev)
+		}
+	}
+	return sub, backlog, complete
+}
+
+// Unsubscribe elimina al suscriptor y cierra su canal si sigue abierto
+func (b *Broker) Unsubscribe(sub *subscriber) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, ok := b.subs[sub]; ok {
+		delete(b.subs, sub)
+		close(sub.ch)
+	}
+}
+
+// Close desconecta a todos los suscriptores y rechaza suscripciones nuevas
+func (b *Broker) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+	for sub := range b.subs {
+		delete(b.subs, sub)
+		close(sub.ch)
+	}
+}
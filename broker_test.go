@@ -0,0 +1,96 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+// eventIDs devuelve los IDs de los eventos en orden
+func eventIDs(events []LiveEvent) []uint64 {
+	ids := []uint64{}
+	for _, ev := range events {
+		ids = append(ids, ev.ID)
+	}
+	return ids
+}
+
+func TestBrokerSubscribeResume(t *testing.T) {
+	b := newBroker(3)
+	b.Publish(1, LiveGoal, nil)       // 1
+	b.Publish(2, LiveGoal, nil)       // 2
+	b.Publish(1, LiveYellowCard, nil) // 3
+
+	tests := []struct {
+		name     string
+		matchID  int
+		lastID   uint64
+		backlog  []uint64
+		complete bool
+	}{
+		{"conexión nueva", 1, 0, []uint64{}, true},
+		{"reanudar un partido", 1, 1, []uint64{3}, true},
+		{"reanudar la liga", 0, 1, []uint64{2, 3}, true},
+		{"al día", 1, 3, []uint64{}, true},
+		{"servidor reiniciado", 1, 7, []uint64{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sub, backlog, complete := b.Subscribe(tt.matchID, tt.lastID)
+			defer b.Unsubscribe(sub)
+			if got := eventIDs(backlog); !reflect.DeepEqual(got, tt.backlog) || complete != tt.complete {
+				t.Errorf("backlog %v completo %v, se esperaba %v completo %v", got, complete, tt.backlog, tt.complete)
+			}
+		})
+	}
+
+	// Con el historial recortado el evento 2 ya no está: se reanuda con lo que queda pero sin garantía
+	b.Publish(2, LiveRedCard, nil) // 4, el historial queda en 2..4
+	b.Publish(1, LiveGoal, nil)    // 5, el historial queda en 3..5
+	sub, backlog, complete := b.Subscribe(0, 1)
+	defer b.Unsubscribe(sub)
+	if got := eventIDs(backlog); !reflect.DeepEqual(got, []uint64{3, 4, 5}) || complete {
+		t.Errorf("historial recortado: backlog %v completo %v, se esperaba [3 4 5] incompleto", got, complete)
+	}
+}
+
+func TestBrokerPublish(t *testing.T) {
+	b := newBroker(10)
+	match, _, _ := b.Subscribe(1, 0)
+	league, _, _ := b.Subscribe(0, 0)
+
+	// Cada suscriptor recibe solo los eventos de su partido; el de la liga, todos
+	b.Publish(2, LiveGoal, nil)
+	b.Publish(1, LiveGoal, nil)
+	if got := len(match.ch); got != 1 {
+		t.Errorf("el suscriptor del partido 1 recibió %d eventos, se esperaba 1", got)
+	} else if ev := <-match.ch; ev.MatchID != 1 {
+		t.Errorf("el suscriptor del partido 1 recibió un evento del partido %d", ev.MatchID)
+	}
+	if got := len(league.ch); got != 2 {
+		t.Errorf("el suscriptor de la liga recibió %d eventos, se esperaban 2", got)
+	}
+	b.Unsubscribe(league)
+
+	// Un suscriptor que no consume se desconecta al llenarse su buffer, sin bloquear al resto
+	slow, _, _ := b.Subscribe(2, 0)
+	for i := 0; i <= subscriberBuffer; i++ {
+		b.Publish(2, LiveGoal, nil)
+	}
+	received := 0
+	for range slow.ch {
+		received++
+	}
+	if received != subscriberBuffer {
+		t.Errorf("el suscriptor atrasado recibió %d eventos antes de desconectarse, se esperaban %d", received, subscriberBuffer)
+	}
+
+	// Al cerrar se desconecta a todos y los nuevos reciben el canal cerrado
+	b.Close()
+	if _, ok := <-match.ch; ok {
+		t.Error("el canal del partido 1 sigue abierto después de Close")
+	}
+	late, _, _ := b.Subscribe(1, 0)
+	if _, ok := <-late.ch; ok {
+		t.Error("una suscripción después de Close recibió un canal abierto")
+	}
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"database/sql"
+	"flag"
+	"fmt"
+	"log"
+)
+
+// orphanCleanup es un paso de la limpieza: la tabla afectada, qué se corrige y la sentencia que lo hace
+type orphanCleanup struct {
+	table       string
+	description string
+	query       string
+}
+
+// orphanCleanups son los pasos de la limpieza, en el orden en que se aplican.
+// Los jugadores huérfanos se eliminan antes de desenlazar los eventos para que
+// sus eventos queden como texto libre en el paso siguiente.
+var orphanCleanups = []orphanCleanup{
+	{"goals", "goles de partidos eliminados", "DELETE FROM goals WHERE match_id NOT IN (SELECT id FROM matches)"},
+	{"yellow_cards", "tarjetas amarillas de partidos eliminados", "DELETE FROM yellow_cards WHERE match_id NOT IN (SELECT id FROM matches)"},
+	{"red_cards", "tarjetas rojas de partidos eliminados", "DELETE FROM red_cards WHERE match_id NOT IN (SELECT id FROM matches)"},
+	{"players", "jugadores de equipos eliminados", "DELETE FROM players WHERE team_id NOT IN (SELECT id FROM teams)"},
+	{"goals", "goles de jugadores eliminados (quedan como texto libre)", "UPDATE goals SET player_id = NULL WHERE player_id IS NOT NULL AND player_id NOT IN (SELECT id FROM players)"},
+	{"yellow_cards", "tarjetas amarillas de jugadores eliminados (quedan como texto libre)", "UPDATE yellow_cards SET player_id = NULL WHERE player_id IS NOT NULL AND player_id NOT IN (SELECT id FROM players)"},
+	{"red_cards", "tarjetas rojas de jugadores eliminados (quedan como texto libre)", "UPDATE red_cards SET player_id = NULL WHERE player_id IS NOT NULL AND player_id NOT IN (SELECT id FROM players)"},
+	{"webhooks", "webhooks filtrados por equipos eliminados", "DELETE FROM webhooks WHERE team_id IS NOT NULL AND team_id NOT IN (SELECT id FROM teams)"},
+	{"webhook_deliveries", "entregas de webhooks eliminados", "DELETE FROM webhook_deliveries WHERE webhook_id NOT IN (SELECT id FROM webhooks)"},
+}
+
+// runCleanupOrphans elimina las filas huérfanas que quedaron en bases de datos creadas
+// antes de activar las claves foráneas, por ejemplo los goles y tarjetas de partidos eliminados.
+// Con -dry-run solo informa cuántas filas se corregirían.
+//
+// Uso: go run . cleanup-orphans [-db ./database/matches.db] [-dry-run]
+func runCleanupOrphans(args []string) {
+	fs := flag.NewFlagSet("cleanup-orphans", flag.ExitOnError)
+	path := fs.String("db", dbPath, "Ruta de la base de datos SQLite")
+	dryRun := fs.Bool("dry-run", false, "Solo mostrar cuántas filas se corregirían")
+	fs.Parse(args)
+
+	// Se abre sin claves foráneas: justamente hay filas que no las cumplen
+	conn, err := sql.Open("sqlite3", *path)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer conn.Close()
+
+	tx, err := conn.Begin()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer tx.Rollback()
+
+	total := int64(0)
+	for _, c := range orphanCleanups {
+		// Las bases anteriores a algunas migraciones no tienen todas las tablas
+		var exists bool
+		tx.QueryRow("SELECT EXISTS(SELECT 1 FROM sqlite_master WHERE type = 'table' AND name = ?)", c.table).Scan(&exists)
+		if !exists {
+			continue
+		}
+
+		res, err := tx.Exec(c.query)
+		if err != nil {
+			log.Fatalf("error al limpiar %s: %v", c.description, err)
+		}
+		n, _ := res.RowsAffected()
+		if n > 0 {
+			fmt.Printf("%6d %s\n", n, c.description)
+		}
+		total += n
+	}
+
+	// Informar las referencias inválidas que no se pueden corregir automáticamente
+	rows, err := tx.Query("PRAGMA foreign_key_check")
+	if err != nil {
+		log.Fatal(err)
+	}
+	remaining := map[string]int{}
+	for rows.Next() {
+		var table, parent string
+		var rowid, fkid sql.NullInt64
+		if err := rows.Scan(&table, &rowid, &parent, &fkid); err != nil {
+			log.Fatal(err)
+		}
+		remaining[table+" → "+parent]++
+	}
+	rows.Close()
+	for ref, n := range remaining {
+		fmt.Printf("%6d referencias inválidas en %s (revisar a mano)\n", n, ref)
+	}
+
+	if *dryRun {
+		fmt.Printf("Se corregirían %d filas (sin cambios por -dry-run)\n", total)
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("Se corrigieron %d filas\n", total)
+}
@@ -0,0 +1,74 @@
+// Receptor de prueba para los webhooks de La Liga Tracker.
+// Muestra cada entrega recibida y verifica su firma HMAC-SHA256.
+//
+// Uso:
+//
+//	go run ./cmd/webhook-receiver -addr :9090 -secret <secreto> -fail 2
+//
+// Con -fail N responde 500 a los primeros N intentos de cada entrega,
+// para probar los reintentos del servidor.
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"flag"
+	"io"
+	"log"
+	"net/http"
+	"sync"
+)
+
+func main() {
+	addr := flag.String("addr", ":9090", "Dirección en la que escucha el receptor")
+	secret := flag.String("secret", "", "Secreto compartido del webhook para verificar la firma")
+	fail := flag.Int("fail", 0, "Cantidad de intentos de cada entrega que se responden con error")
+	flag.Parse()
+
+	var mu sync.Mutex
+	attempts := map[string]int{}
+
+	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, "No se pudo leer el cuerpo", http.StatusBadRequest)
+			return
+		}
+
+		// Verificar la firma si se indicó el secreto
+		signature := r.Header.Get("X-LaLiga-Signature")
+		valid := "sin verificar"
+		if *secret != "" {
+			mac := hmac.New(sha256.New, []byte(*secret))
+			mac.Write(body)
+			expected := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+			if !hmac.Equal([]byte(signature), []byte(expected)) {
+				log.Printf("firma inválida en la entrega %s", r.Header.Get("X-LaLiga-Delivery"))
+				http.Error(w, "Firma inválida", http.StatusUnauthorized)
+				return
+			}
+			valid = "válida"
+		}
+
+		delivery := r.Header.Get("X-LaLiga-Delivery")
+		mu.Lock()
+		attempts[delivery]++
+		n := attempts[delivery]
+		mu.Unlock()
+
+		log.Printf("entrega %s intento %s evento %s (firma %s): %s",
+			delivery, r.Header.Get("X-LaLiga-Attempt"), r.Header.Get("X-LaLiga-Event"), valid, body)
+
+		// Simular fallos para probar los reintentos
+		if n <= *fail {
+			http.Error(w, "Fallo simulado", http.StatusInternalServerError)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	log.Printf("Receptor de webhooks escuchando en %s", *addr)
+	log.Fatal(http.ListenAndServe(*addr, nil))
+}
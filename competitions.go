@@ -0,0 +1,747 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// Competition es un torneo que se juega por temporadas: una liga o una copa
+// @description Modelo que contiene la información de una competición
+// @property id, name, country, type
+// @example { "id": 1, "name": "La Liga", "country": "España", "type": "league" }
+type Competition struct {
+	ID      int    `json:"id"`
+	Name    string `json:"name" example:"La Liga"`
+	Country string `json:"country" example:"España"`
+	Type    string `json:"type" example:"league" enums:"league,cup"`
+}
+
+// Season es una edición de una competición, con las fechas entre las que se juegan sus partidos
+// @description Modelo que contiene la información de una temporada de una competición
+// @property id, competitionId, competition, name, startDate, endDate
+// @example { "id": 1, "competitionId": 1, "competition": "La Liga", "name": "2024/25", "startDate": "2024-07-01", "endDate": "2025-06-30" }
+type Season struct {
+	ID            int    `json:"id"`
+	CompetitionID int    `json:"competitionId"`
+	Competition   string `json:"competition" example:"La Liga"`
+	Name          string `json:"name" example:"2024/25"`
+	StartDate     string `json:"startDate" example:"2024-07-01"`
+	EndDate       string `json:"endDate" example:"2025-06-30"`
+}
+
+// Formatos de competición
+const (
+	competitionLeague = "league" // Todos contra todos, con tabla de clasificación
+	competitionCup    = "cup"    // Eliminatorias
+)
+
+// Errores de referencias a competiciones y temporadas que no existen
+var (
+	errCompetitionNotFound = errors.New("competición no encontrada")
+	errSeasonNotFound      = errors.New("temporada no encontrada")
+)
+
+// matchScope limita los partidos a una competición o a una temporada. Lo usan el listado
+// de partidos y de equipos, la clasificación y las estadísticas.
+type matchScope struct {
+	CompetitionID int // 0 para todas las competiciones
+	SeasonID      int // 0 para todas las temporadas
+}
+
+// parseMatchScope lee la temporada de la URL en las rutas /api/seasons/{id}/... o, en las demás,
+// los parámetros competition (ID o nombre) y seasonId. Devuelve errSeasonNotFound si la
+// temporada de la URL no existe y el detalle de los parámetros que no son válidos.
+func parseMatchScope(r *http.Request) (matchScope, []FieldError, error) {
+	var sc matchScope
+	if _, ok := mux.Vars(r)["id"]; ok {
+		season, err := store.Season(pathID(r, "id"))
+		if err == errNotFound {
+			return sc, nil, errSeasonNotFound
+		} else if err != nil {
+			return sc, nil, err
+		}
+		return matchScope{CompetitionID: season.CompetitionID, SeasonID: season.ID}, nil, nil
+	}
+
+	q := r.URL.Query()
+	var errs []FieldError
+
+	// La competición se puede indicar por ID o por nombre, igual que los equipos
+	if competition := q.Get("competition"); competition != "" {
+		id, _ := strconv.Atoi(competition)
+		c, err := resolveCompetition(id, competition)
+		if err == errCompetitionNotFound {
+			errs = append(errs, FieldError{"competition", fieldNotFound, "Competición no encontrada"})
+		} else if err != nil {
+			return sc, nil, err
+		}
+		sc.CompetitionID = c.ID
+	}
+
+	if v := q.Get("seasonId"); v != "" {
+		id, _ := strconv.Atoi(v)
+		season, err := store.Season(id)
+		switch {
+		case err == errNotFound:
+			errs = append(errs, FieldError{"seasonId", fieldNotFound, "Temporada no encontrada"})
+		case err != nil:
+			return sc, nil, err
+		case sc.CompetitionID != 0 && season.CompetitionID != sc.CompetitionID:
+			errs = append(errs, FieldError{"seasonId", fieldInvalid, "La temporada no pertenece a la competición"})
+		default:
+			sc.SeasonID = season.ID
+		}
+	}
+
+	return sc, errs, nil
+}
+
+// writeSeasonNotFound responde 404 cuando la temporada de la URL no existe
+func writeSeasonNotFound(w http.ResponseWriter, r *http.Request) {
+	writeError(w, r, http.StatusNotFound, codeSeasonNotFound, "Temporada no encontrada")
+}
+
+// resolveCompetition obtiene una competición por ID o, si id es cero, por nombre sin distinguir mayúsculas
+func resolveCompetition(id int, name string) (Competition, error) {
+	var c Competition
+	var err error
+	if id != 0 {
+		c, err = store.Competition(id)
+	} else {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			return Competition{}, errCompetitionNotFound
+		}
+		c, err = store.CompetitionByName(name)
+	}
+
+	if err == errNotFound {
+		return Competition{}, errCompetitionNotFound
+	}
+	return c, err
+}
+
+// validateCompetition verifica los campos de una competición y devuelve el detalle
+// de los que no son válidos. Sin tipo se asume una liga.
+func validateCompetition(c *Competition) []FieldError {
+	var errs []FieldError
+	c.Name = strings.TrimSpace(c.Name)
+	c.Country = strings.TrimSpace(c.Country)
+	if c.Name == "" {
+		errs = append(errs, FieldError{"name", fieldRequired, "El nombre de la competición es obligatorio"})
+	}
+	if c.Type == "" {
+		c.Type = competitionLeague
+	}
+	if c.Type != competitionLeague && c.Type != competitionCup {
+		errs = append(errs, FieldError{"type", fieldInvalid, "Tipo de competición inválido. Usa league o cup"})
+	}
+	return errs
+}
+
+// validateSeason verifica el nombre y las fechas de una temporada y devuelve el detalle
+// de los campos que no son válidos
+func validateSeason(s *Season) []FieldError {
+	var errs []FieldError
+	s.Name = strings.TrimSpace(s.Name)
+	if s.Name == "" {
+		errs = append(errs, FieldError{"name", fieldRequired, "El nombre de la temporada es obligatorio"})
+	}
+
+	var start, end time.Time
+	var err error
+	if s.StartDate == "" {
+		errs = append(errs, FieldError{"startDate", fieldRequired, "La fecha de inicio es obligatoria"})
+	} else if start, err = time.Parse(time.DateOnly, s.StartDate); err != nil {
+		errs = append(errs, FieldError{"startDate", fieldInvalidFormat, "Formato de fecha inválido. Usa YYYY-MM-DD"})
+	}
+	if s.EndDate == "" {
+		errs = append(errs, FieldError{"endDate", fieldRequired, "La fecha de fin es obligatoria"})
+	} else if end, err = time.Parse(time.DateOnly, s.EndDate); err != nil {
+		errs = append(errs, FieldError{"endDate", fieldInvalidFormat, "Formato de fecha inválido. Usa YYYY-MM-DD"})
+	}
+	if !start.IsZero() && !end.IsZero() && end.Before(start) {
+		errs = append(errs, FieldError{"endDate", fieldOutOfRange, "La fecha de fin debe ser posterior a la de inicio"})
+	}
+	return errs
+}
+
+// seasonNameTaken indica si otra temporada de la competición ya usa el nombre
+func seasonNameTaken(s Season) (bool, error) {
+	seasons, err := store.Seasons(s.CompetitionID)
+	if err != nil {
+		return false, err
+	}
+	for _, other := range seasons {
+		if other.ID != s.ID && strings.EqualFold(other.Name, s.Name) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// seasonHasMatchesOutside indica si la temporada tiene partidos fuera de sus nuevas fechas
+func seasonHasMatchesOutside(s Season) (bool, error) {
+	start, _ := time.Parse(time.DateOnly, s.StartDate)
+	end, _ := time.Parse(time.DateOnly, s.EndDate)
+	scope := matchScope{CompetitionID: s.CompetitionID, SeasonID: s.ID}
+	for _, lq := range []matchListQuery{
+		{matchScope: scope, To: start.AddDate(0, 0, -1).Format(time.DateOnly)},
+		{matchScope: scope, From: end.AddDate(0, 0, 1).Format(time.DateOnly)},
+	} {
+		lq.Sort, lq.Page, lq.Limit = "id", 1, 1
+		_, total, err := store.Matches(lq)
+		if err != nil || total > 0 {
+			return total > 0, err
+		}
+	}
+	return false, nil
+}
+
+// resolveMatchSeason verifica que la temporada de un partido exista y que la fecha del
+// partido esté dentro de ella. Un partido sin temporada (seasonId 0) es válido.
+func resolveMatchSeason(m Match) ([]FieldError, error) {
+	if m.SeasonID == 0 {
+		return nil, nil
+	}
+	s, err := store.Season(m.SeasonID)
+	if err == errNotFound {
+		return []FieldError{{"seasonId", fieldNotFound, "Temporada no encontrada"}}, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if date := datePart(m.MatchDate); date < s.StartDate || date > s.EndDate {
+		return []FieldError{{"matchDate", fieldOutOfRange, "La fecha del partido está fuera de la temporada " + s.Name +
+			" (" + s.StartDate + " a " + s.EndDate + ")"}}, nil
+	}
+	return nil, nil
+}
+
+// ================================================================
+// Competiciones
+// ================================================================
+
+// @Summary Obtener todas las competiciones
+// @Description Retorna las competiciones ordenadas por nombre
+// @Tags competitions
+// @Accept json
+// @Produce json
+// @Success 200 {array} Competition
+// @Failure 401 {object} Problem
+// @Failure 429 {object} Problem
+// @Failure 500 {object} Problem
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/competitions [get]
+func getCompetitions(w http.ResponseWriter, r *http.Request) {
+	competitions, err := store.Competitions()
+	if err != nil {
+		dbError(w, r, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(competitions)
+}
+
+// @Summary Obtener competición por ID
+// @Description Retorna los datos de una competición
+// @Tags competitions
+// @Accept json
+// @Produce json
+// @Param id path int true "ID de la competición"
+// @Success 200 {object} Competition
+// @Failure 401 {object} Problem
+// @Failure 404 {object} Problem
+// @Failure 429 {object} Problem
+// @Failure 500 {object} Problem
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/competitions/{id} [get]
+func getCompetition(w http.ResponseWriter, r *http.Request) {
+	c, err := store.Competition(pathID(r, "id"))
+	if err == errNotFound {
+		writeError(w, r, http.StatusNotFound, codeCompetitionNotFound, "Competición no encontrada")
+		return
+	} else if err != nil {
+		dbError(w, r, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(c)
+}
+
+// @Summary Crear una competición
+// @Description Crea una competición. El nombre debe ser único; el tipo es league (por defecto) o cup
+// @Tags competitions
+// @Accept json
+// @Produce json
+// @Param competition body Competition true "Datos de la competición"
+// @Success 200 {object} Competition
+// @Failure 400 {object} Problem
+// @Failure 401 {object} Problem
+// @Failure 403 {object} Problem
+// @Failure 409 {object} Problem
+// @Failure 429 {object} Problem
+// @Failure 500 {object} Problem
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/competitions [post]
+func createCompetition(w http.ResponseWriter, r *http.Request) {
+	var c Competition
+	if err := json.NewDecoder(r.Body).Decode(&c); err != nil {
+		writeInvalidJSON(w, r)
+		return
+	}
+
+	if errs := validateCompetition(&c); errs != nil {
+		writeValidation(w, r, errs...)
+		return
+	}
+
+	// Evitar duplicados que solo difieran en mayúsculas
+	if _, err := resolveCompetition(0, c.Name); err == nil {
+		writeError(w, r, http.StatusConflict, codeCompetitionNameTaken, "Ya existe una competición con ese nombre")
+		return
+	} else if err != errCompetitionNotFound {
+		dbError(w, r, err)
+		return
+	}
+
+	id, err := store.CreateCompetition(c)
+	if err != nil {
+		dbError(w, r, err)
+		return
+	}
+
+	c.ID = id
+	json.NewEncoder(w).Encode(c)
+}
+
+// @Summary Actualizar competición
+// @Description Modifica el nombre, el país o el tipo de una competición
+// @Tags competitions
+// @Accept json
+// @Produce json
+// @Param id path int true "ID de la competición"
+// @Param competition body Competition true "Datos actualizados"
+// @Success 200 {object} Competition
+// @Failure 400 {object} Problem
+// @Failure 401 {object} Problem
+// @Failure 403 {object} Problem
+// @Failure 404 {object} Problem
+// @Failure 409 {object} Problem
+// @Failure 429 {object} Problem
+// @Failure 500 {object} Problem
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/competitions/{id} [put]
+func updateCompetition(w http.ResponseWriter, r *http.Request) {
+	id := pathID(r, "id")
+
+	var c Competition
+	if err := json.NewDecoder(r.Body).Decode(&c); err != nil {
+		writeInvalidJSON(w, r)
+		return
+	}
+
+	if errs := validateCompetition(&c); errs != nil {
+		writeValidation(w, r, errs...)
+		return
+	}
+
+	// El nuevo nombre no puede pertenecer a otra competición
+	if other, err := resolveCompetition(0, c.Name); err == nil && other.ID != id {
+		writeError(w, r, http.StatusConflict, codeCompetitionNameTaken, "Ya existe una competición con ese nombre")
+		return
+	} else if err != nil && err != errCompetitionNotFound {
+		dbError(w, r, err)
+		return
+	}
+
+	c.ID = id
+	err := store.UpdateCompetition(c)
+	if err == errNotFound {
+		writeError(w, r, http.StatusNotFound, codeCompetitionNotFound, "Competición no encontrada")
+		return
+	} else if err != nil {
+		dbError(w, r, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(c)
+}
+
+// @Summary Eliminar competición
+// @Description Elimina una competición que no tenga temporadas
+// @Tags competitions
+// @Accept json
+// @Produce json
+// @Param id path int true "ID de la competición"
+// @Success 204 {string} string "Sin contenido"
+// @Failure 401 {object} Problem
+// @Failure 403 {object} Problem
+// @Failure 404 {object} Problem
+// @Failure 409 {object} Problem
+// @Failure 429 {object} Problem
+// @Failure 500 {object} Problem
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/competitions/{id} [delete]
+func deleteCompetition(w http.ResponseWriter, r *http.Request) {
+	err := store.DeleteCompetition(pathID(r, "id"))
+	if err == errCompetitionHasSeasons {
+		writeError(w, r, http.StatusConflict, codeCompetitionHasSeasons, "La competición tiene temporadas; elimínalas primero")
+		return
+	} else if err == errNotFound {
+		writeError(w, r, http.StatusNotFound, codeCompetitionNotFound, "Competición no encontrada")
+		return
+	} else if err != nil {
+		dbError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ================================================================
+// Temporadas
+// ================================================================
+
+// @Summary Obtener las temporadas de una competición
+// @Description Retorna las temporadas de la competición ordenadas por fecha de inicio
+// @Tags competitions
+// @Accept json
+// @Produce json
+// @Param id path int true "ID de la competición"
+// @Success 200 {array} Season
+// @Failure 401 {object} Problem
+// @Failure 404 {object} Problem
+// @Failure 429 {object} Problem
+// @Failure 500 {object} Problem
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/competitions/{id}/seasons [get]
+func getSeasons(w http.ResponseWriter, r *http.Request) {
+	id := pathID(r, "id")
+	if _, err := store.Competition(id); err == errNotFound {
+		writeError(w, r, http.StatusNotFound, codeCompetitionNotFound, "Competición no encontrada")
+		return
+	} else if err != nil {
+		dbError(w, r, err)
+		return
+	}
+
+	seasons, err := store.Seasons(id)
+	if err != nil {
+		dbError(w, r, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(seasons)
+}
+
+// @Summary Crear una temporada
+// @Description Crea una temporada de la competición. El nombre no se puede repetir en la competición
+// @Tags competitions
+// @Accept json
+// @Produce json
+// @Param id path int true "ID de la competición"
+// @Param season body Season true "Nombre y fechas de la temporada"
+// @Success 200 {object} Season
+// @Failure 400 {object} Problem
+// @Failure 401 {object} Problem
+// @Failure 403 {object} Problem
+// @Failure 404 {object} Problem
+// @Failure 409 {object} Problem
+// @Failure 429 {object} Problem
+// @Failure 500 {object} Problem
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/competitions/{id}/seasons [post]
+func createSeason(w http.ResponseWriter, r *http.Request) {
+	c, err := store.Competition(pathID(r, "id"))
+	if err == errNotFound {
+		writeError(w, r, http.StatusNotFound, codeCompetitionNotFound, "Competición no encontrada")
+		return
+	} else if err != nil {
+		dbError(w, r, err)
+		return
+	}
+
+	var s Season
+	if err := json.NewDecoder(r.Body).Decode(&s); err != nil {
+		writeInvalidJSON(w, r)
+		return
+	}
+
+	if errs := validateSeason(&s); errs != nil {
+		writeValidation(w, r, errs...)
+		return
+	}
+
+	// La competición es la de la URL aunque el cuerpo indique otra
+	s.ID, s.CompetitionID, s.Competition = 0, c.ID, c.Name
+	if taken, err := seasonNameTaken(s); err != nil {
+		dbError(w, r, err)
+		return
+	} else if taken {
+		writeError(w, r, http.StatusConflict, codeSeasonNameTaken, "La competición ya tiene una temporada con ese nombre")
+		return
+	}
+
+	s.ID, err = store.CreateSeason(s)
+	if err != nil {
+		dbError(w, r, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(s)
+}
+
+// @Summary Obtener temporada por ID
+// @Description Retorna los datos de una temporada con el nombre de su competición
+// @Tags competitions
+// @Accept json
+// @Produce json
+// @Param id path int true "ID de la temporada"
+// @Success 200 {object} Season
+// @Failure 401 {object} Problem
+// @Failure 404 {object} Problem
+// @Failure 429 {object} Problem
+// @Failure 500 {object} Problem
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/seasons/{id} [get]
+func getSeason(w http.ResponseWriter, r *http.Request) {
+	s, err := store.Season(pathID(r, "id"))
+	if err == errNotFound {
+		writeSeasonNotFound(w, r)
+		return
+	} else if err != nil {
+		dbError(w, r, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(s)
+}
+
+// @Summary Actualizar temporada
+// @Description Modifica el nombre y las fechas de una temporada. La competición no cambia,
+// @Description y las fechas nuevas deben seguir incluyendo a todos sus partidos.
+// @Tags competitions
+// @Accept json
+// @Produce json
+// @Param id path int true "ID de la temporada"
+// @Param season body Season true "Nombre y fechas de la temporada"
+// @Success 200 {object} Season
+// @Failure 400 {object} Problem
+// @Failure 401 {object} Problem
+// @Failure 403 {object} Problem
+// @Failure 404 {object} Problem
+// @Failure 409 {object} Problem
+// @Failure 429 {object} Problem
+// @Failure 500 {object} Problem
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/seasons/{id} [put]
+func updateSeason(w http.ResponseWriter, r *http.Request) {
+	current, err := store.Season(pathID(r, "id"))
+	if err == errNotFound {
+		writeSeasonNotFound(w, r)
+		return
+	} else if err != nil {
+		dbError(w, r, err)
+		return
+	}
+
+	var s Season
+	if err := json.NewDecoder(r.Body).Decode(&s); err != nil {
+		writeInvalidJSON(w, r)
+		return
+	}
+
+	if errs := validateSeason(&s); errs != nil {
+		writeValidation(w, r, errs...)
+		return
+	}
+
+	s.ID, s.CompetitionID, s.Competition = current.ID, current.CompetitionID, current.Competition
+	if taken, err := seasonNameTaken(s); err != nil {
+		dbError(w, r, err)
+		return
+	} else if taken {
+		writeError(w, r, http.StatusConflict, codeSeasonNameTaken, "La competición ya tiene una temporada con ese nombre")
+		return
+	}
+
+	// Los partidos de la temporada tienen que seguir dentro de sus fechas
+	if outside, err := seasonHasMatchesOutside(s); err != nil {
+		dbError(w, r, err)
+		return
+	} else if outside {
+		writeValidation(w, r, FieldError{"startDate", fieldOutOfRange, "La temporada tiene partidos fuera de las fechas indicadas"})
+		return
+	}
+
+	if err := store.UpdateSeason(s); err == errNotFound {
+		writeSeasonNotFound(w, r)
+		return
+	} else if err != nil {
+		dbError(w, r, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(s)
+}
+
+// @Summary Eliminar temporada
+// @Description Elimina una temporada que no tenga partidos
+// @Tags competitions
+// @Accept json
+// @Produce json
+// @Param id path int true "ID de la temporada"
+// @Success 204 {string} string "Sin contenido"
+// @Failure 401 {object} Problem
+// @Failure 403 {object} Problem
+// @Failure 404 {object} Problem
+// @Failure 409 {object} Problem
+// @Failure 429 {object} Problem
+// @Failure 500 {object} Problem
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/seasons/{id} [delete]
+func deleteSeason(w http.ResponseWriter, r *http.Request) {
+	err := store.DeleteSeason(pathID(r, "id"))
+	if err == errSeasonHasMatches {
+		writeError(w, r, http.StatusConflict, codeSeasonHasMatches, "La temporada tiene partidos asociados")
+		return
+	} else if err == errNotFound {
+		writeSeasonNotFound(w, r)
+		return
+	} else if err != nil {
+		dbError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ================================================================
+// Partidos, equipos, clasificación y estadísticas de una temporada
+// ================================================================
+
+// @Summary Obtener los partidos de una temporada
+// @Description Igual que GET /api/matches con seasonId, con la temporada en la URL
+// @Tags competitions
+// @Accept json
+// @Produce json
+// @Param id path int true "ID de la temporada"
+// @Param page query int false "Número de página (desde 1)"
+// @Param limit query int false "Partidos por página (1-500, por defecto 100)"
+// @Param team query string false "ID o nombre del equipo (local o visitante)"
+// @Param from query string false "Fecha inicial (YYYY-MM-DD)"
+// @Param to query string false "Fecha final (YYYY-MM-DD)"
+// @Param status query string false "Estados separados por comas (por ejemplo live,half_time)"
+// @Param sort query string false "Orden: id, -id, date o -date" default(id)
+// @Success 200 {array} FullMatchData
+// @Header 200 {integer} X-Total-Count "Total de partidos que cumplen los filtros"
+// @Header 200 {string} Link "Enlaces de paginación (RFC 8288)"
+// @Failure 400 {object} Problem
+// @Failure 401 {object} Problem
+// @Failure 404 {object} Problem
+// @Failure 429 {object} Problem
+// @Failure 500 {object} Problem
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/seasons/{id}/matches [get]
+func getSeasonMatches(w http.ResponseWriter, r *http.Request) {
+	getMatches(w, r)
+}
+
+// @Summary Obtener los equipos de una temporada
+// @Description Retorna los equipos que tienen partidos en la temporada
+// @Tags competitions
+// @Accept json
+// @Produce json
+// @Param id path int true "ID de la temporada"
+// @Success 200 {array} Team
+// @Failure 401 {object} Problem
+// @Failure 404 {object} Problem
+// @Failure 429 {object} Problem
+// @Failure 500 {object} Problem
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/seasons/{id}/teams [get]
+func getSeasonTeams(w http.ResponseWriter, r *http.Request) {
+	getTeams(w, r)
+}
+
+// @Summary Obtener la clasificación de una temporada
+// @Description Calcula la clasificación con los partidos terminados de la temporada
+// @Tags competitions
+// @Accept json
+// @Produce json
+// @Param id path int true "ID de la temporada"
+// @Success 200 {array} Standing
+// @Failure 401 {object} Problem
+// @Failure 404 {object} Problem
+// @Failure 429 {object} Problem
+// @Failure 500 {object} Problem
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/seasons/{id}/standings [get]
+func getSeasonStandings(w http.ResponseWriter, r *http.Request) {
+	getStandings(w, r)
+}
+
+// @Summary Tabla de goleadores de una temporada
+// @Description Igual que GET /api/stats/scorers con seasonId, con la temporada en la URL
+// @Tags competitions
+// @Accept json
+// @Produce json
+// @Param id path int true "ID de la temporada"
+// @Param from query string false "Fecha inicial (YYYY-MM-DD)"
+// @Param to query string false "Fecha final (YYYY-MM-DD)"
+// @Param team query string false "ID o nombre del equipo"
+// @Param limit query int false "Cantidad máxima de filas (1-100, por defecto 20)"
+// @Success 200 {array} ScorerStat
+// @Failure 400 {object} Problem
+// @Failure 401 {object} Problem
+// @Failure 404 {object} Problem
+// @Failure 429 {object} Problem
+// @Failure 500 {object} Problem
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/seasons/{id}/stats/scorers [get]
+func getSeasonTopScorers(w http.ResponseWriter, r *http.Request) {
+	getTopScorers(w, r)
+}
+
+// @Summary Tabla de disciplina de una temporada
+// @Description Igual que GET /api/stats/discipline con seasonId, con la temporada en la URL
+// @Tags competitions
+// @Accept json
+// @Produce json
+// @Param id path int true "ID de la temporada"
+// @Param from query string false "Fecha inicial (YYYY-MM-DD)"
+// @Param to query string false "Fecha final (YYYY-MM-DD)"
+// @Param team query string false "ID o nombre del equipo"
+// @Param limit query int false "Cantidad máxima de filas (1-100, por defecto 20)"
+// @Success 200 {array} DisciplineStat
+// @Failure 400 {object} Problem
+// @Failure 401 {object} Problem
+// @Failure 404 {object} Problem
+// @Failure 429 {object} Problem
+// @Failure 500 {object} Problem
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/seasons/{id}/stats/discipline [get]
+func getSeasonDiscipline(w http.ResponseWriter, r *http.Request) {
+	getDiscipline(w, r)
+}
@@ -0,0 +1,388 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/url"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config reúne la configuración del servidor.
+//
+// Cada opción se toma de la primera fuente que la defina, en este orden de precedencia:
+//
+//  1. Flags de línea de comandos (-addr, -dsn, ...)
+//  2. Variables de entorno (LALIGA_ADDR, LALIGA_DSN, ...)
+//  3. Archivo YAML indicado con -config o LALIGA_CONFIG
+//  4. Valores por defecto de defaultConfig
+type Config struct {
+	Addr              string        `yaml:"addr"`                // Dirección en la que escucha el servidor
+	Store             string        `yaml:"store"`               // Almacenamiento: sqlite, postgres o memory
+	DSN               string        `yaml:"dsn"`                 // Ruta de la base SQLite o cadena de conexión de PostgreSQL
+	CORSOrigins       []string      `yaml:"cors_origins"`        // Orígenes permitidos por CORS; "*" permite cualquiera
+	LogLevel          string        `yaml:"log_level"`           // Nivel mínimo de los logs: debug, info, warn o error
+	LogFormat         string        `yaml:"log_format"`          // Formato de los logs: json o text
+	ReadHeaderTimeout time.Duration `yaml:"read_header_timeout"` // Tiempo máximo para leer los encabezados de una solicitud
+	ReadTimeout       time.Duration `yaml:"read_timeout"`        // Tiempo máximo para leer una solicitud completa
+	WriteTimeout      time.Duration `yaml:"write_timeout"`       // Tiempo máximo para escribir una respuesta
+	IdleTimeout       time.Duration `yaml:"idle_timeout"`        // Tiempo máximo de una conexión keep-alive inactiva
+	ShutdownDelay     time.Duration `yaml:"shutdown_delay"`      // Espera entre que /readyz responde 503 y el cierre del puerto al apagar
+	ShutdownTimeout   time.Duration `yaml:"shutdown_timeout"`    // Tiempo máximo para terminar las solicitudes en curso al apagar
+	Features          Features      `yaml:"features"`            // Funcionalidades opcionales
+	Auth              Auth          `yaml:"auth"`                // Autenticación y permisos de la API
+	RateLimit         RateLimit     `yaml:"rate_limit"`          // Límite de solicitudes por cliente
+}
+
+// Features activa o desactiva las funcionalidades opcionales.
+// Las desactivadas no registran sus rutas, que responden 404.
+type Features struct {
+	Swagger  bool `yaml:"swagger"`  // Documentación en /swagger/
+	Webhooks bool `yaml:"webhooks"` // Registro y entrega de webhooks
+	Live     bool `yaml:"live"`     // Streams SSE y WebSocket
+	Metrics  bool `yaml:"metrics"`  // Métricas de Prometheus en /metrics
+}
+
+// Auth configura la autenticación de la API con claves de API y tokens JWT.
+// Los permisos de cada ruta por rol se definen al registrarla en main con require.
+type Auth struct {
+	Enabled     bool   `yaml:"enabled"`      // Exigir credenciales en las rutas de /api y /ws
+	PublicReads bool   `yaml:"public_reads"` // Permitir las consultas (GET) sin credenciales
+	JWTSecret   string `yaml:"jwt_secret"`   // Secreto HS256 de los tokens JWT; vacío solo acepta claves de API
+}
+
+// RateLimit configura el límite de solicitudes de cada cliente con un balde de tokens.
+// Las consultas y las escrituras tienen presupuestos separados; cada cliente se identifica
+// por su clave de API o su token y, si no envía credenciales, por su IP.
+type RateLimit struct {
+	Enabled    bool    `yaml:"enabled"`     // Limitar las solicitudes a /api y /ws
+	ReadRate   float64 `yaml:"read_rate"`   // Consultas (GET) por segundo que se recuperan
+	ReadBurst  int     `yaml:"read_burst"`  // Consultas seguidas que se admiten
+	WriteRate  float64 `yaml:"write_rate"`  // Escrituras (POST, PUT, PATCH, DELETE) por segundo que se recuperan
+	WriteBurst int     `yaml:"write_burst"` // Escrituras seguidas que se admiten
+	TrustProxy bool    `yaml:"trust_proxy"` // Tomar la IP del cliente de X-Forwarded-For; solo detrás de un proxy
+}
+
+// config es la configuración con la que arrancó el servidor
+var config = defaultConfig()
+
+// defaultConfig devuelve la configuración que se usa si ninguna fuente define una opción
+func defaultConfig() Config {
+	return Config{
+		Addr:              ":8080",
+		Store:             storeSQLite,
+		DSN:               dbPath,
+		CORSOrigins:       []string{"*"},
+		LogLevel:          "info",
+		LogFormat:         logFormatJSON,
+		ReadHeaderTimeout: 5 * time.Second,
+		ReadTimeout:       15 * time.Second,
+		WriteTimeout:      30 * time.Second,
+		IdleTimeout:       60 * time.Second,
+		ShutdownDelay:     5 * time.Second,
+		ShutdownTimeout:   15 * time.Second,
+		Features:          Features{Swagger: true, Webhooks: true, Live: true, Metrics: true},
+		Auth:              Auth{Enabled: false, PublicReads: true},
+		RateLimit:         RateLimit{Enabled: true, ReadRate: 10, ReadBurst: 50, WriteRate: 5, WriteBurst: 50},
+	}
+}
+
+// configOption describe una opción que se puede definir por flag y por variable de entorno.
+// value devuelve un flag.Value que escribe en el campo correspondiente de c.
+type configOption struct {
+	flag  string
+	env   []string // Si hay más de una, gana la primera definida
+	usage string
+	value func(c *Config) flag.Value
+}
+
+// configOptions son las opciones configurables por flag y variable de entorno
+var configOptions = []configOption{
+	{"addr", []string{"LALIGA_ADDR"}, "Dirección en la que escucha el servidor",
+		func(c *Config) flag.Value { return (*stringValue)(&c.Addr) }},
+	{"store", []string{"LALIGA_STORE"}, "Almacenamiento: sqlite, postgres o memory",
+		func(c *Config) flag.Value { return (*stringValue)(&c.Store) }},
+	{"dsn", []string{"LALIGA_DSN", "LALIGA_DB_PATH"}, "Ruta de la base de datos SQLite o cadena de conexión de PostgreSQL",
+		func(c *Config) flag.Value { return (*stringValue)(&c.DSN) }},
+	{"cors-origins", []string{"LALIGA_CORS_ORIGINS"}, "Orígenes permitidos por CORS separados por comas; * permite cualquiera",
+		func(c *Config) flag.Value { return (*listValue)(&c.CORSOrigins) }},
+	{"log-level", []string{"LALIGA_LOG_LEVEL"}, "Nivel mínimo de los logs: debug, info, warn o error",
+		func(c *Config) flag.Value { return (*stringValue)(&c.LogLevel) }},
+	{"log-format", []string{"LALIGA_LOG_FORMAT"}, "Formato de los logs: json o text",
+		func(c *Config) flag.Value { return (*stringValue)(&c.LogFormat) }},
+	{"read-header-timeout", []string{"LALIGA_READ_HEADER_TIMEOUT"}, "Tiempo máximo para leer los encabezados de una solicitud (0 usa read-timeout)",
+		func(c *Config) flag.Value { return (*durationValue)(&c.ReadHeaderTimeout) }},
+	{"read-timeout", []string{"LALIGA_READ_TIMEOUT"}, "Tiempo máximo para leer una solicitud (0 sin límite)",
+		func(c *Config) flag.Value { return (*durationValue)(&c.ReadTimeout) }},
+	{"write-timeout", []string{"LALIGA_WRITE_TIMEOUT"}, "Tiempo máximo para escribir una respuesta (0 sin límite)",
+		func(c *Config) flag.Value { return (*durationValue)(&c.WriteTimeout) }},
+	{"idle-timeout", []string{"LALIGA_IDLE_TIMEOUT"}, "Tiempo máximo de una conexión inactiva (0 sin límite)",
+		func(c *Config) flag.Value { return (*durationValue)(&c.IdleTimeout) }},
+	{"shutdown-delay", []string{"LALIGA_SHUTDOWN_DELAY"}, "Espera al apagar entre que /readyz responde 503 y el cierre del puerto (0 sin espera)",
+		func(c *Config) flag.Value { return (*durationValue)(&c.ShutdownDelay) }},
+	{"shutdown-timeout", []string{"LALIGA_SHUTDOWN_TIMEOUT"}, "Tiempo máximo para terminar las solicitudes en curso al apagar",
+		func(c *Config) flag.Value { return (*durationValue)(&c.ShutdownTimeout) }},
+	{"swagger", []string{"LALIGA_SWAGGER"}, "Servir la documentación Swagger",
+		func(c *Config) flag.Value { return (*boolValue)(&c.Features.Swagger) }},
+	{"webhooks", []string{"LALIGA_WEBHOOKS"}, "Activar los webhooks",
+		func(c *Config) flag.Value { return (*boolValue)(&c.Features.Webhooks) }},
+	{"live", []string{"LALIGA_LIVE"}, "Activar los streams SSE y WebSocket",
+		func(c *Config) flag.Value { return (*boolValue)(&c.Features.Live) }},
+	{"metrics", []string{"LALIGA_METRICS"}, "Exponer las métricas de Prometheus en /metrics",
+		func(c *Config) flag.Value { return (*boolValue)(&c.Features.Metrics) }},
+	{"auth", []string{"LALIGA_AUTH"}, "Exigir una clave de API o un token JWT en la API",
+		func(c *Config) flag.Value { return (*boolValue)(&c.Auth.Enabled) }},
+	{"public-reads", []string{"LALIGA_PUBLIC_READS"}, "Con -auth, permitir las consultas GET sin credenciales",
+		func(c *Config) flag.Value { return (*boolValue)(&c.Auth.PublicReads) }},
+	{"jwt-secret", []string{"LALIGA_JWT_SECRET"}, "Secreto HS256 para validar los tokens JWT (al menos 32 caracteres)",
+		func(c *Config) flag.Value { return (*stringValue)(&c.Auth.JWTSecret) }},
+	{"rate-limit", []string{"LALIGA_RATE_LIMIT"}, "Limitar las solicitudes de cada cliente",
+		func(c *Config) flag.Value { return (*boolValue)(&c.RateLimit.Enabled) }},
+	{"rate-read", []string{"LALIGA_RATE_READ"}, "Consultas por segundo que recupera cada cliente",
+		func(c *Config) flag.Value { return (*floatValue)(&c.RateLimit.ReadRate) }},
+	{"rate-read-burst", []string{"LALIGA_RATE_READ_BURST"}, "Consultas seguidas que se admiten de cada cliente",
+		func(c *Config) flag.Value { return (*intValue)(&c.RateLimit.ReadBurst) }},
+	{"rate-write", []string{"LALIGA_RATE_WRITE"}, "Escrituras por segundo que recupera cada cliente",
+		func(c *Config) flag.Value { return (*floatValue)(&c.RateLimit.WriteRate) }},
+	{"rate-write-burst", []string{"LALIGA_RATE_WRITE_BURST"}, "Escrituras seguidas que se admiten de cada cliente",
+		func(c *Config) flag.Value { return (*intValue)(&c.RateLimit.WriteBurst) }},
+	{"trust-proxy", []string{"LALIGA_TRUST_PROXY"}, "Tomar la IP del cliente de X-Forwarded-For (solo detrás de un proxy)",
+		func(c *Config) flag.Value { return (*boolValue)(&c.RateLimit.TrustProxy) }},
+}
+
+// loadConfig arma la configuración a partir de los argumentos, el entorno y el archivo YAML.
+// Devuelve también si se pidió --print-config.
+func loadConfig(args []string) (Config, bool, error) {
+	// Los flags se leen en una configuración aparte para aplicar solo los que se pasaron
+	fs := flag.NewFlagSet("laligatracker", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Uso: go run . [flags]")
+		fmt.Fprintln(fs.Output(), "Precedencia: flags > variables de entorno > archivo -config > valores por defecto")
+		fs.PrintDefaults()
+	}
+	flagged := defaultConfig()
+	for _, opt := range configOptions {
+		fs.Var(opt.value(&flagged), opt.flag, opt.usage+" ("+strings.Join(opt.env, ", ")+")")
+	}
+	path := fs.String("config", "", "Archivo de configuración YAML (LALIGA_CONFIG)")
+	printConfig := fs.Bool("print-config", false, "Mostrar la configuración efectiva en YAML y salir")
+	fs.Parse(args)
+
+	// Valores por defecto < archivo
+	cfg := defaultConfig()
+	if *path == "" {
+		*path = os.Getenv("LALIGA_CONFIG")
+	}
+	if *path != "" {
+		if err := cfg.loadFile(*path); err != nil {
+			return Config{}, false, err
+		}
+	}
+
+	// < variables de entorno
+	for _, opt := range configOptions {
+		for _, name := range opt.env {
+			v, ok := os.LookupEnv(name)
+			if !ok {
+				continue
+			}
+			if err := opt.value(&cfg).Set(v); err != nil {
+				return Config{}, false, fmt.Errorf("%s: %w", name, err)
+			}
+			break
+		}
+	}
+
+	// < flags
+	fs.Visit(func(f *flag.Flag) {
+		for _, opt := range configOptions {
+			if opt.flag == f.Name {
+				opt.value(&cfg).Set(f.Value.String())
+			}
+		}
+	})
+
+	return cfg, *printConfig, cfg.validate()
+}
+
+// commandConfig devuelve la configuración para los subcomandos (migrate, apikey, token): el archivo
+// indicado con -config o LALIGA_CONFIG, las variables de entorno y los valores por defecto, igual
+// que al arrancar el servidor. Así trabajan sobre la misma base de datos que el servidor.
+func commandConfig(path string) (Config, error) {
+	var args []string
+	if path != "" {
+		args = []string{"-config", path}
+	}
+	cfg, _, err := loadConfig(args)
+	return cfg, err
+}
+
+// loadFile lee un archivo YAML sobre la configuración actual.
+// Las opciones que el archivo no menciona conservan su valor.
+func (c *Config) loadFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	// Rechazar opciones desconocidas para detectar errores de escritura
+	dec := yaml.NewDecoder(f)
+	dec.KnownFields(true)
+	if err := dec.Decode(c); err != nil && err != io.EOF {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
+
+// validate verifica que la configuración sea utilizable
+func (c Config) validate() error {
+	if c.Addr == "" {
+		return errors.New("addr no puede estar vacío")
+	}
+	switch c.Store {
+	case storeSQLite, storePostgres, storeMemory:
+	default:
+		return fmt.Errorf("almacenamiento desconocido: %s (usa %s, %s o %s)", c.Store, storeSQLite, storePostgres, storeMemory)
+	}
+	if len(c.CORSOrigins) == 0 {
+		return errors.New("cors_origins debe tener al menos un origen")
+	}
+	if _, err := c.slogLevel(); err != nil {
+		return err
+	}
+	if c.LogFormat != logFormatJSON && c.LogFormat != logFormatText {
+		return fmt.Errorf("log_format inválido: %s (usa %s o %s)", c.LogFormat, logFormatJSON, logFormatText)
+	}
+	if c.ReadHeaderTimeout < 0 || c.ReadTimeout < 0 || c.WriteTimeout < 0 || c.IdleTimeout < 0 {
+		return errors.New("los timeouts no pueden ser negativos")
+	}
+	if c.ShutdownDelay < 0 {
+		return errors.New("shutdown_delay no puede ser negativo")
+	}
+	if c.ShutdownTimeout <= 0 {
+		return errors.New("shutdown_timeout debe ser mayor que 0")
+	}
+	if c.Auth.JWTSecret != "" && len(c.Auth.JWTSecret) < minJWTSecret {
+		return fmt.Errorf("auth.jwt_secret debe tener al menos %d caracteres", minJWTSecret)
+	}
+	// Las claves de API se crean con el comando apikey en la base de datos, que el
+	// almacenamiento en memoria no comparte: sin secreto nadie podría autenticarse
+	if c.Auth.Enabled && c.Store == storeMemory && c.Auth.JWTSecret == "" {
+		return errors.New("con store memory la autenticación requiere auth.jwt_secret")
+	}
+	if c.RateLimit.Enabled && (c.RateLimit.ReadRate <= 0 || c.RateLimit.WriteRate <= 0) {
+		return errors.New("rate_limit.read_rate y rate_limit.write_rate deben ser mayores que 0")
+	}
+	if c.RateLimit.Enabled && (c.RateLimit.ReadBurst < 1 || c.RateLimit.WriteBurst < 1) {
+		return errors.New("rate_limit.read_burst y rate_limit.write_burst deben ser al menos 1")
+	}
+	return nil
+}
+
+// slogLevel convierte LogLevel en un nivel de log/slog
+func (c Config) slogLevel() (slog.Level, error) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(c.LogLevel)); err != nil {
+		return 0, fmt.Errorf("log_level inválido: %s (usa debug, info, warn o error)", c.LogLevel)
+	}
+	return level, nil
+}
+
+// dsnPassword reconoce la contraseña en las cadenas de conexión del tipo "host=... password=..."
+var dsnPassword = regexp.MustCompile(`password=\S+`)
+
+// print escribe la configuración en YAML, ocultando la contraseña de la cadena de conexión
+// y el secreto de los tokens
+func (c Config) print(w io.Writer) error {
+	if c.Auth.JWTSecret != "" {
+		c.Auth.JWTSecret = "xxxxx"
+	}
+	if u, err := url.Parse(c.DSN); err == nil && u.User != nil {
+		c.DSN = u.Redacted()
+	}
+	c.DSN = dsnPassword.ReplaceAllString(c.DSN, "password=xxxxx")
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(c); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+// stringValue, listValue, durationValue, boolValue, intValue y floatValue adaptan los campos
+// de Config a flag.Value para usar el mismo código con los flags y con las variables de entorno.
+type (
+	stringValue   string
+	listValue     []string
+	durationValue time.Duration
+	boolValue     bool
+	intValue      int
+	floatValue    float64
+)
+
+func (v *stringValue) Set(s string) error { *v = stringValue(s); return nil }
+func (v *stringValue) String() string     { return string(*v) }
+
+// Set separa la lista por comas e ignora los elementos vacíos
+func (v *listValue) Set(s string) error {
+	*v = nil
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			*v = append(*v, item)
+		}
+	}
+	return nil
+}
+func (v *listValue) String() string { return strings.Join(*v, ",") }
+
+func (v *durationValue) Set(s string) error {
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return fmt.Errorf("duración inválida: %s (ejemplo: 30s)", s)
+	}
+	*v = durationValue(d)
+	return nil
+}
+func (v *durationValue) String() string { return time.Duration(*v).String() }
+
+func (v *boolValue) Set(s string) error {
+	b, err := strconv.ParseBool(s)
+	if err != nil {
+		return fmt.Errorf("valor inválido: %s (usa true o false)", s)
+	}
+	*v = boolValue(b)
+	return nil
+}
+func (v *boolValue) String() string { return strconv.FormatBool(bool(*v)) }
+
+// IsBoolFlag permite escribir -swagger en lugar de -swagger=true
+func (v *boolValue) IsBoolFlag() bool { return true }
+
+func (v *intValue) Set(s string) error {
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return fmt.Errorf("número inválido: %s", s)
+	}
+	*v = intValue(n)
+	return nil
+}
+func (v *intValue) String() string { return strconv.Itoa(int(*v)) }
+
+func (v *floatValue) Set(s string) error {
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return fmt.Errorf("número inválido: %s (ejemplo: 2.5)", s)
+	}
+	*v = floatValue(f)
+	return nil
+}
+func (v *floatValue) String() string { return strconv.FormatFloat(float64(*v), 'g', -1, 64) }
@@ -0,0 +1,65 @@
+-- ================================================================
+-- Datos de ejemplo de La Liga Tracker
+-- El esquema lo crean las migraciones de migrations/sqlite o migrations/postgres al arrancar el servidor.
+-- Se usa tal cual en ambas bases de datos, por eso solo contiene SQL estándar.
+-- Se cargan con: go run . migrate up -seed (solo si la base de datos no tiene equipos ni partidos)
+-- ================================================================
+
+-- Equipos
+INSERT INTO teams (name, short_name, city, stadium, founded_year) VALUES
+  ('Real Madrid', 'RMA', 'Madrid', 'Santiago Bernabéu', 1902),
+  ('Barcelona', 'FCB', 'Barcelona', 'Spotify Camp Nou', 1899),
+  ('Atletico Madrid', 'ATM', 'Madrid', 'Riyadh Air Metropolitano', 1903),
+  ('Valencia', 'VAL', 'Valencia', 'Mestalla', 1919),
+  ('Sevilla', 'SEV', 'Sevilla', 'Ramón Sánchez-Pizjuán', 1890),
+  ('Villarreal', 'VIL', 'Villarreal', 'Estadio de la Cerámica', 1923),
+  ('Boca Juniors', 'BOC', 'Buenos Aires', 'La Bombonera', 1905),
+  ('River Plate', 'RIV', 'Buenos Aires', 'Estadio Monumental', 1901);
+
+-- Jugadores
+INSERT INTO players (team_id, name, shirt_number, position, nationality) VALUES
+  (1, 'Vinicius Jr.', 7, 'Delantero', 'Brasil'),
+  (1, 'Carvajal', 2, 'Defensa', 'España'),
+  (2, 'Lewandowski', 9, 'Delantero', 'Polonia'),
+  (2, 'Gavi', 6, 'Centrocampista', 'España'),
+  (3, 'Griezmann', 7, 'Delantero', 'Francia'),
+  (4, 'Paulista', 5, 'Defensa', 'Brasil'),
+  (7, 'Rojo', 6, 'Defensa', 'Argentina'),
+  (8, 'Borja', 9, 'Delantero', 'Colombia');
+
+-- Competiciones
+INSERT INTO competitions (name, country, type) VALUES
+  ('La Liga', 'España', 'league'),
+  ('Copa del Rey', 'España', 'cup'),
+  ('Liga Profesional Argentina', 'Argentina', 'league');
+
+-- Temporadas
+INSERT INTO seasons (competition_id, name, start_date, end_date) VALUES
+  (1, '2024/25', '2024-07-01', '2025-06-30'),
+  (2, '2024/25', '2024-07-01', '2025-06-30'),
+  (3, '2025', '2025-01-01', '2025-12-31');
+
+-- Partidos
+INSERT INTO matches (home_team_id, away_team_id, match_date, extra_time, status, status_updated_at, season_id) VALUES
+  (1, 2, '2025-05-10', '05:00', 'finished', '2025-05-10 22:00:00', 1),
+  (3, 4, '2025-06-01', '02:30', 'finished', '2025-06-01 22:00:00', 1),
+  (5, 6, '2025-06-15', '00:00', 'finished', '2025-06-15 22:00:00', 1),
+  (7, 8, '2025-07-20', '07:45', 'finished', '2025-07-20 22:00:00', 3);
+
+-- Goles
+INSERT INTO goals (match_id, team_id, player_id, player, minute) VALUES
+  (1, 1, 1, 'Vinicius Jr.', '12:34'),
+  (1, 2, 3, 'Lewandowski', '21:12'),
+  (2, 3, 5, 'Griezmann', '44:00'),
+  (4, 8, 8, 'Borja', '05:55');
+
+-- Tarjetas Amarillas
+INSERT INTO yellow_cards (match_id, team_id, player_id, player, minute) VALUES
+  (1, 1, 2, 'Carvajal', '35:00'),
+  (1, 2, 4, 'Gavi', '36:20'),
+  (2, 4, 6, 'Paulista', '60:00');
+
+-- Tarjetas Rojas
+INSERT INTO red_cards (match_id, team_id, player_id, player, minute) VALUES
+  (2, 4, 6, 'Paulista', '88:00'),
+  (4, 7, 7, 'Rojo', '70:00');
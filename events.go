@@ -0,0 +1,386 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+)
+
+// resolvedEvent contiene los datos de un evento ya validados, con su equipo y jugador resueltos
+type resolvedEvent struct {
+	TeamID   int
+	Team     string
+	PlayerID sql.NullInt64
+	Player   string
+	Minute   string
+}
+
+// event construye el MatchEvent que se devuelve a los clientes a partir del evento validado
+func (ev resolvedEvent) event(id int) MatchEvent {
+	return MatchEvent{
+		ID:       id,
+		TeamID:   ev.TeamID,
+		Team:     ev.Team,
+		PlayerID: int(ev.PlayerID.Int64),
+		Player:   ev.Player,
+		Minute:   ev.Minute,
+	}
+}
+
+// LiveEventChange es la carga de los eventos en vivo que corrigen o anulan un gol o tarjeta
+// @description Modelo que contiene el tipo y los datos del evento corregido o eliminado
+// @property kind, event
+type LiveEventChange struct {
+	Kind  string     `json:"kind"`
+	Event MatchEvent `json:"event"`
+}
+
+// validateEvent valida la carga útil de un evento del partido matchID: campos requeridos,
+// minuto en formato MM:SS, equipo que juegue el partido y jugador de su plantilla.
+// Si el evento no es válido devuelve el error para el cliente; err solo indica fallos de la base de datos.
+func validateEvent(matchID int, payload EventPayload) (resolvedEvent, *Problem, error) {
+	var ev resolvedEvent
+
+	// Validar campos vacíos
+	// El jugador puede indicarse por ID o por nombre; si se indica por ID, el equipo se puede omitir
+	var errs []FieldError
+	if payload.Team == "" && payload.TeamID == 0 && payload.PlayerID == 0 {
+		errs = append(errs, FieldError{"team", fieldRequired, "El equipo es obligatorio"})
+	}
+	if payload.Player == "" && payload.PlayerID == 0 {
+		errs = append(errs, FieldError{"player", fieldRequired, "El jugador es obligatorio"})
+	}
+	if payload.Minute == "" {
+		errs = append(errs, FieldError{"minute", fieldRequired, "El minuto es obligatorio"})
+	} else if !isValidTimeFormat(payload.Minute) {
+		errs = append(errs, FieldError{"minute", fieldInvalidFormat, "Formato de tiempo inválido. Usa MM:SS"})
+	}
+	if errs != nil {
+		return ev, validationProblem(errs), nil
+	}
+
+	// Verificar si el partido existe y obtener los IDs de los equipos
+	match, err := store.Match(matchID)
+	if err == errNotFound {
+		return ev, newProblem(http.StatusNotFound, codeMatchNotFound, "Partido no encontrado"), nil
+	} else if err != nil {
+		return ev, nil, err
+	}
+	home, away := match.HomeTeamID, match.AwayTeamID
+
+	// Si solo se indicó el jugador, el equipo es el de su plantilla
+	if payload.Team == "" && payload.TeamID == 0 {
+		payload.TeamID, err = playerTeam(payload.PlayerID)
+		if err == errPlayerNotInSquad {
+			return ev, validationProblem([]FieldError{{"playerId", fieldNotFound, "Jugador no encontrado"}}), nil
+		} else if err != nil {
+			return ev, nil, err
+		}
+	}
+
+	// Resolver el equipo del evento (por ID o por nombre)
+	ev.TeamID, ev.Team, err = resolveTeam(payload.TeamID, payload.Team)
+	if err != nil && err != errTeamNotFound {
+		return ev, nil, err
+	}
+
+	// Validar que el equipo exista en este partido
+	if err == errTeamNotFound || (ev.TeamID != home && ev.TeamID != away) {
+		return ev, validationProblem([]FieldError{{"team", fieldInvalid, "El equipo no corresponde al partido"}}), nil
+	}
+
+	// Validar que el jugador pertenezca a la plantilla del equipo
+	ev.PlayerID, ev.Player, err = resolveEventPlayer(ev.TeamID, payload.PlayerID, payload.Player)
+	if err == errPlayerNotInSquad {
+		return ev, validationProblem([]FieldError{{"player", fieldInvalid, "El jugador no pertenece a la plantilla del equipo"}}), nil
+	} else if err != nil {
+		return ev, nil, err
+	}
+
+	ev.Minute = payload.Minute
+	return ev, nil, nil
+}
+
+// eventNotFoundMessages mapea cada tabla de eventos al mensaje de error cuando el evento no existe
+var eventNotFoundMessages = map[string]string{
+	"goals":        "Gol no encontrado",
+	"yellow_cards": "Tarjeta amarilla no encontrada",
+	"red_cards":    "Tarjeta roja no encontrada",
+}
+
+// getEvent devuelve un evento (gol, tarjeta amarilla o roja) de un partido
+func getEvent(w http.ResponseWriter, r *http.Request, table string) {
+	e, err := store.Event(table, pathID(r, "id"), pathID(r, "eventId"))
+	if err == errNotFound {
+		writeError(w, r, http.StatusNotFound, codeEventNotFound, eventNotFoundMessages[table])
+		return
+	} else if err != nil {
+		dbError(w, r, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(e)
+}
+
+// updateEvent corrige un evento (gol, tarjeta amarilla o roja) de un partido.
+// Aplica las mismas validaciones que registerEvent, pero se permite en cualquier
+// estado del partido para poder corregir errores después del pitido final.
+func updateEvent(w http.ResponseWriter, r *http.Request, table string) {
+	matchID, eventID := pathID(r, "id"), pathID(r, "eventId")
+
+	var payload EventPayload
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		writeInvalidJSON(w, r)
+		return
+	}
+
+	// Verificar que el evento exista en este partido
+	before, err := store.Event(table, matchID, eventID)
+	if err == errNotFound {
+		writeError(w, r, http.StatusNotFound, codeEventNotFound, eventNotFoundMessages[table])
+		return
+	} else if err != nil {
+		dbError(w, r, err)
+		return
+	}
+
+	// Validar el evento y resolver su equipo y jugador
+	ev, problem, err := validateEvent(matchID, payload)
+	if err != nil {
+		dbError(w, r, err)
+		return
+	} else if problem != nil {
+		writeProblem(w, r, problem)
+		return
+	}
+
+	// El evento pudo eliminarse después de leerlo
+	if err := store.UpdateEvent(table, matchID, eventID, ev); err == errNotFound {
+		writeError(w, r, http.StatusNotFound, codeEventNotFound, eventNotFoundMessages[table])
+		return
+	} else if err != nil {
+		dbError(w, r, err)
+		return
+	}
+
+	// Notificar la corrección a los clientes conectados al stream
+	updated := ev.event(eventID)
+	broker.Publish(matchID, LiveEventUpdated, LiveEventChange{Kind: liveEventTypes[table], Event: updated})
+	audit(r, liveEventTypes[table]+"."+auditEventUpdate, matchID, before, updated)
+
+	json.NewEncoder(w).Encode(updated)
+}
+
+// deleteEvent elimina un evento (gol, tarjeta amarilla o roja) de un partido,
+// por ejemplo un gol anulado por el VAR. El marcador se recalcula en la siguiente consulta.
+func deleteEvent(w http.ResponseWriter, r *http.Request, table string) {
+	matchID, eventID := pathID(r, "id"), pathID(r, "eventId")
+
+	// Obtener el evento antes de eliminarlo para notificarlo
+	e, err := store.Event(table, matchID, eventID)
+	if err == errNotFound {
+		writeError(w, r, http.StatusNotFound, codeEventNotFound, eventNotFoundMessages[table])
+		return
+	} else if err != nil {
+		dbError(w, r, err)
+		return
+	}
+
+	err = store.DeleteEvent(table, matchID, eventID)
+	if err == errNotFound {
+		writeError(w, r, http.StatusNotFound, codeEventNotFound, eventNotFoundMessages[table])
+		return
+	} else if err != nil {
+		dbError(w, r, err)
+		return
+	}
+
+	// Notificar la anulación a los clientes conectados al stream
+	broker.Publish(matchID, LiveEventDeleted, LiveEventChange{Kind: liveEventTypes[table], Event: e})
+	audit(r, liveEventTypes[table]+"."+auditEventDelete, matchID, e, nil)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary Obtener gol
+// @Description Retorna un gol de un partido
+// @Tags events
+// @Accept json
+// @Produce json
+// @Param id path int true "ID del partido"
+// @Param eventId path int true "ID del gol"
+// @Success 200 {object} MatchEvent
+// @Failure 401 {object} Problem
+// @Failure 404 {object} Problem
+// @Failure 429 {object} Problem
+// @Failure 500 {object} Problem
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/matches/{id}/goals/{eventId} [get]
+func getGoal(w http.ResponseWriter, r *http.Request) {
+	getEvent(w, r, "goals")
+}
+
+// @Summary Corregir gol
+// @Description Corrige el equipo, jugador o minuto de un gol con las mismas validaciones que al registrarlo
+// @Tags events
+// @Accept json
+// @Produce json
+// @Param id path int true "ID del partido"
+// @Param eventId path int true "ID del gol"
+// @Param goal body EventPayload true "Datos corregidos del gol"
+// @Success 200 {object} MatchEvent
+// @Failure 400 {object} Problem
+// @Failure 401 {object} Problem
+// @Failure 403 {object} Problem
+// @Failure 404 {object} Problem
+// @Failure 429 {object} Problem
+// @Failure 500 {object} Problem
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/matches/{id}/goals/{eventId} [put]
+func updateGoal(w http.ResponseWriter, r *http.Request) {
+	updateEvent(w, r, "goals")
+}
+
+// @Summary Eliminar gol
+// @Description Elimina un gol de un partido (por ejemplo, anulado por el VAR)
+// @Tags events
+// @Accept json
+// @Produce json
+// @Param id path int true "ID del partido"
+// @Param eventId path int true "ID del gol"
+// @Success 204 {string} string "Sin contenido"
+// @Failure 401 {object} Problem
+// @Failure 403 {object} Problem
+// @Failure 404 {object} Problem
+// @Failure 429 {object} Problem
+// @Failure 500 {object} Problem
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/matches/{id}/goals/{eventId} [delete]
+func deleteGoal(w http.ResponseWriter, r *http.Request) {
+	deleteEvent(w, r, "goals")
+}
+
+// @Summary Obtener tarjeta amarilla
+// @Description Retorna una tarjeta amarilla de un partido
+// @Tags events
+// @Accept json
+// @Produce json
+// @Param id path int true "ID del partido"
+// @Param eventId path int true "ID de la tarjeta amarilla"
+// @Success 200 {object} MatchEvent
+// @Failure 401 {object} Problem
+// @Failure 404 {object} Problem
+// @Failure 429 {object} Problem
+// @Failure 500 {object} Problem
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/matches/{id}/yellow_cards/{eventId} [get]
+func getYellowCard(w http.ResponseWriter, r *http.Request) {
+	getEvent(w, r, "yellow_cards")
+}
+
+// @Summary Corregir tarjeta amarilla
+// @Description Corrige el equipo, jugador o minuto de una tarjeta amarilla con las mismas validaciones que al registrarla
+// @Tags events
+// @Accept json
+// @Produce json
+// @Param id path int true "ID del partido"
+// @Param eventId path int true "ID de la tarjeta amarilla"
+// @Param yellow_card body EventPayload true "Datos corregidos de la tarjeta amarilla"
+// @Success 200 {object} MatchEvent
+// @Failure 400 {object} Problem
+// @Failure 401 {object} Problem
+// @Failure 403 {object} Problem
+// @Failure 404 {object} Problem
+// @Failure 429 {object} Problem
+// @Failure 500 {object} Problem
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/matches/{id}/yellow_cards/{eventId} [put]
+func updateYellowCard(w http.ResponseWriter, r *http.Request) {
+	updateEvent(w, r, "yellow_cards")
+}
+
+// @Summary Eliminar tarjeta amarilla
+// @Description Elimina una tarjeta amarilla de un partido
+// @Tags events
+// @Accept json
+// @Produce json
+// @Param id path int true "ID del partido"
+// @Param eventId path int true "ID de la tarjeta amarilla"
+// @Success 204 {string} string "Sin contenido"
+// @Failure 401 {object} Problem
+// @Failure 403 {object} Problem
+// @Failure 404 {object} Problem
+// @Failure 429 {object} Problem
+// @Failure 500 {object} Problem
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/matches/{id}/yellow_cards/{eventId} [delete]
+func deleteYellowCard(w http.ResponseWriter, r *http.Request) {
+	deleteEvent(w, r, "yellow_cards")
+}
+
+// @Summary Obtener tarjeta roja
+// @Description Retorna una tarjeta roja de un partido
+// @Tags events
+// @Accept json
+// @Produce json
+// @Param id path int true "ID del partido"
+// @Param eventId path int true "ID de la tarjeta roja"
+// @Success 200 {object} MatchEvent
+// @Failure 401 {object} Problem
+// @Failure 404 {object} Problem
+// @Failure 429 {object} Problem
+// @Failure 500 {object} Problem
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/matches/{id}/red_cards/{eventId} [get]
+func getRedCard(w http.ResponseWriter, r *http.Request) {
+	getEvent(w, r, "red_cards")
+}
+
+// @Summary Corregir tarjeta roja
+// @Description Corrige el equipo, jugador o minuto de una tarjeta roja con las mismas validaciones que al registrarla
+// @Tags events
+// @Accept json
+// @Produce json
+// @Param id path int true "ID del partido"
+// @Param eventId path int true "ID de la tarjeta roja"
+// @Param red_card body EventPayload true "Datos corregidos de la tarjeta roja"
+// @Success 200 {object} MatchEvent
+// @Failure 400 {object} Problem
+// @Failure 401 {object} Problem
+// @Failure 403 {object} Problem
+// @Failure 404 {object} Problem
+// @Failure 429 {object} Problem
+// @Failure 500 {object} Problem
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/matches/{id}/red_cards/{eventId} [put]
+func updateRedCard(w http.ResponseWriter, r *http.Request) {
+	updateEvent(w, r, "red_cards")
+}
+
+// @Summary Eliminar tarjeta roja
+// @Description Elimina una tarjeta roja de un partido
+// @Tags events
+// @Accept json
+// @Produce json
+// @Param id path int true "ID del partido"
+// @Param eventId path int true "ID de la tarjeta roja"
+// @Success 204 {string} string "Sin contenido"
+// @Failure 401 {object} Problem
+// @Failure 403 {object} Problem
+// @Failure 404 {object} Problem
+// @Failure 429 {object} Problem
+// @Failure 500 {object} Problem
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/matches/{id}/red_cards/{eventId} [delete]
+func deleteRedCard(w http.ResponseWriter, r *http.Request) {
+	deleteEvent(w, r, "red_cards")
+}
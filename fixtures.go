@@ -0,0 +1,285 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Límites del generador de calendarios
+const (
+	maxFixtureTeams        = 40 // 1560 partidos a doble vuelta
+	defaultFixtureInterval = 7  // Días entre jornadas: una por semana
+	maxFixtureInterval     = 60
+)
+
+// fixtureTeam es un equipo de la lista del calendario, indicado por ID (número) o por nombre (texto)
+type fixtureTeam struct {
+	ID   int
+	Name string
+}
+
+// UnmarshalJSON acepta tanto 3 como "Barcelona" o "3"
+func (t *fixtureTeam) UnmarshalJSON(b []byte) error {
+	if err := json.Unmarshal(b, &t.ID); err == nil {
+		return nil
+	}
+	if err := json.Unmarshal(b, &t.Name); err != nil {
+		return err
+	}
+	t.ID, _ = strconv.Atoi(t.Name)
+	return nil
+}
+
+// FixtureRequest son los parámetros del generador de calendarios
+// @description Equipos y fechas para generar un calendario todos contra todos a doble vuelta
+// @property teams, startDate, intervalDays, dryRun
+// @example { "teams": ["Real Madrid", "Barcelona", 3, 4], "startDate": "2024-08-18", "intervalDays": 7, "dryRun": true }
+type FixtureRequest struct {
+	Teams        []fixtureTeam `json:"teams" swaggertype:"array,string" example:"Real Madrid,Barcelona,3,4"`
+	StartDate    string        `json:"startDate" example:"2024-08-18"`
+	IntervalDays int           `json:"intervalDays" example:"7"`
+	DryRun       bool          `json:"dryRun" example:"true"`
+}
+
+// Matchday es una jornada del calendario con la fecha de todos sus partidos
+// @description Jornada del calendario generado
+// @property matchday, date, matches
+type Matchday struct {
+	Number  int     `json:"matchday" example:"1"`
+	Date    string  `json:"date" example:"2024-08-18"`
+	Matches []Match `json:"matches"`
+}
+
+// FixtureSchedule es el calendario generado. En un ensayo (dryRun) los partidos tienen ID 0.
+// @description Calendario todos contra todos a doble vuelta de una temporada
+// @property seasonId, dryRun, teams, matches, matchdays
+type FixtureSchedule struct {
+	SeasonID  int        `json:"seasonId" example:"1"`
+	DryRun    bool       `json:"dryRun" example:"true"`
+	Teams     int        `json:"teams" example:"20"`
+	Matches   int        `json:"matches" example:"380"`
+	Matchdays []Matchday `json:"matchdays"`
+}
+
+// roundRobin arma un todos contra todos a doble vuelta entre n equipos y devuelve cada jornada
+// como pares de índices (local, visitante). Con n impar, un equipo descansa en cada jornada.
+//
+// La primera vuelta usa el método del círculo: el último equipo queda fijo y los demás rotan.
+// Alternando la localía por jornada y por distancia en el círculo, los equipos alternan local y
+// visitante salvo n-2 veces en la primera vuelta con n par (ninguna con n impar), el mínimo posible.
+// La segunda vuelta invierte la localía de la primera empezando por su segunda jornada, para que
+// ningún cruce se repita en jornadas seguidas (salvo con dos equipos). Con n par eso deja n
+// repeticiones en la segunda vuelta y dos en el cambio de vuelta; con n impar, solo una en el
+// cambio de vuelta; con dos equipos, ninguna. Cada equipo juega la mitad de sus partidos de local.
+func roundRobin(n int) [][][2]int {
+	size := n
+	if size%2 == 1 {
+		size++ // Equipo ficticio: quien juega contra él descansa
+	}
+	fixed, rounds := size-1, size-1
+
+	first := make([][][2]int, rounds)
+	for r := 0; r < rounds; r++ {
+		pairs := [][2]int{{r, fixed}}
+		if r%2 == 1 {
+			pairs[0] = [2]int{fixed, r}
+		}
+		for i := 1; i < size/2; i++ {
+			a, b := (r+i)%rounds, (r-i+rounds)%rounds
+			if i%2 == 0 {
+				a, b = b, a
+			}
+			pairs = append(pairs, [2]int{a, b})
+		}
+		for _, p := range pairs {
+			if p[0] < n && p[1] < n {
+				first[r] = append(first[r], p)
+			}
+		}
+	}
+
+	schedule := append([][][2]int{}, first...)
+	for r := 0; r < rounds; r++ {
+		var second [][2]int
+		for _, p := range first[(r+1)%rounds] {
+			second = append(second, [2]int{p[1], p[0]})
+		}
+		schedule = append(schedule, second)
+	}
+	return schedule
+}
+
+// validateFixtureRequest verifica los parámetros del calendario contra la temporada y devuelve
+// los equipos resueltos y el detalle de los campos que no son válidos
+func validateFixtureRequest(req *FixtureRequest, season Season) ([]Team, []FieldError, error) {
+	var errs []FieldError
+
+	switch {
+	case len(req.Teams) < 2:
+		errs = append(errs, FieldError{"teams", fieldOutOfRange, "El calendario necesita al menos 2 equipos"})
+	case len(req.Teams) > maxFixtureTeams:
+		errs = append(errs, FieldError{"teams", fieldOutOfRange, "El calendario admite hasta " + strconv.Itoa(maxFixtureTeams) + " equipos"})
+	}
+
+	// Resolver cada equipo por ID o nombre; un equipo no puede aparecer dos veces
+	var teams []Team
+	seen := map[int]bool{}
+	for i, ref := range req.Teams {
+		field := fmt.Sprintf("teams[%d]", i)
+		id, name, err := resolveTeam(ref.ID, ref.Name)
+		if err == errTeamNotFound {
+			errs = append(errs, FieldError{field, fieldNotFound, "Equipo no encontrado"})
+			continue
+		} else if err != nil {
+			return nil, nil, err
+		}
+		if seen[id] {
+			errs = append(errs, FieldError{field, fieldDuplicate, "El equipo " + name + " está repetido"})
+			continue
+		}
+		seen[id] = true
+		teams = append(teams, Team{ID: id, Name: name})
+	}
+
+	if req.IntervalDays == 0 {
+		req.IntervalDays = defaultFixtureInterval
+	}
+	if req.IntervalDays < 1 || req.IntervalDays > maxFixtureInterval {
+		errs = append(errs, FieldError{"intervalDays", fieldOutOfRange, "Los días entre jornadas deben estar entre 1 y " + strconv.Itoa(maxFixtureInterval)})
+	}
+
+	// Todas las jornadas tienen que caer dentro de la temporada
+	start, err := time.Parse(time.DateOnly, req.StartDate)
+	switch {
+	case req.StartDate == "":
+		errs = append(errs, FieldError{"startDate", fieldRequired, "La fecha de la primera jornada es obligatoria"})
+	case err != nil:
+		errs = append(errs, FieldError{"startDate", fieldInvalidFormat, "Formato de fecha inválido. Usa YYYY-MM-DD"})
+	case req.StartDate < season.StartDate || req.StartDate > season.EndDate:
+		errs = append(errs, FieldError{"startDate", fieldOutOfRange, "La primera jornada está fuera de la temporada " + season.Name +
+			" (" + season.StartDate + " a " + season.EndDate + ")"})
+	case errs == nil:
+		rounds := len(teams) - 1 + len(teams)%2
+		last := start.AddDate(0, 0, req.IntervalDays*(2*rounds-1)).Format(time.DateOnly)
+		if last > season.EndDate {
+			errs = append(errs, FieldError{"intervalDays", fieldOutOfRange, "La última jornada sería el " + last +
+				", después del fin de la temporada (" + season.EndDate + ")"})
+		}
+	}
+
+	return teams, errs, nil
+}
+
+// @Summary Generar el calendario de una temporada
+// @Description Genera un todos contra todos a doble vuelta entre los equipos indicados (por ID o nombre),
+// @Description con una jornada cada intervalDays días (7 por defecto) desde startDate. Cada equipo juega
+// @Description la mitad de sus partidos de local y se evita, en lo posible, que juegue dos jornadas seguidas
+// @Description en la misma condición. Con dryRun devuelve el calendario sin crear los partidos.
+// @Description La temporada no debe tener partidos.
+// @Tags competitions
+// @Accept json
+// @Produce json
+// @Param id path int true "ID de la temporada"
+// @Param fixtures body FixtureRequest true "Equipos, primera jornada y días entre jornadas"
+// @Success 200 {object} FixtureSchedule
+// @Failure 400 {object} Problem
+// @Failure 401 {object} Problem
+// @Failure 403 {object} Problem
+// @Failure 404 {object} Problem
+// @Failure 409 {object} Problem
+// @Failure 429 {object} Problem
+// @Failure 500 {object} Problem
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/seasons/{id}/fixtures/generate [post]
+func generateFixtures(w http.ResponseWriter, r *http.Request) {
+	season, err := store.Season(pathID(r, "id"))
+	if err == errNotFound {
+		writeSeasonNotFound(w, r)
+		return
+	} else if err != nil {
+		dbError(w, r, err)
+		return
+	}
+
+	var req FixtureRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeInvalidJSON(w, r)
+		return
+	}
+
+	teams, errs, err := validateFixtureRequest(&req, season)
+	if err != nil {
+		dbError(w, r, err)
+		return
+	} else if errs != nil {
+		writeValidation(w, r, errs...)
+		return
+	}
+
+	// El calendario completo se genera sobre una temporada vacía, también en el ensayo,
+	// para que este anticipe la respuesta real
+	_, total, err := store.Matches(matchListQuery{matchScope: matchScope{SeasonID: season.ID}, Sort: "id", Page: 1, Limit: 1})
+	if err != nil {
+		dbError(w, r, err)
+		return
+	} else if total > 0 {
+		writeError(w, r, http.StatusConflict, codeSeasonHasMatches,
+			fmt.Sprintf("La temporada ya tiene %d partidos; el calendario solo se genera para una temporada vacía", total))
+		return
+	}
+
+	// Pasar los índices de roundRobin a partidos con su fecha
+	start, _ := time.Parse(time.DateOnly, req.StartDate)
+	schedule := FixtureSchedule{SeasonID: season.ID, DryRun: req.DryRun, Teams: len(teams)}
+	var matches []Match
+	rounds := roundRobin(len(teams))
+	for i, round := range rounds {
+		day := Matchday{Number: i + 1, Date: start.AddDate(0, 0, i*req.IntervalDays).Format(time.DateOnly)}
+		for _, p := range round {
+			home, away := teams[p[0]], teams[p[1]]
+			matches = append(matches, Match{
+				HomeTeamID: home.ID, HomeTeam: home.Name,
+				AwayTeamID: away.ID, AwayTeam: away.Name,
+				MatchDate: day.Date, ExtraTime: "00:00", Status: StatusScheduled, SeasonID: season.ID,
+			})
+		}
+		schedule.Matchdays = append(schedule.Matchdays, day)
+	}
+	schedule.Matches = len(matches)
+
+	// Crear todos los partidos en una sola transacción: o queda el calendario completo o nada.
+	// La transacción vuelve a verificar que la temporada siga vacía por si otra solicitud
+	// generó su calendario al mismo tiempo.
+	if !req.DryRun {
+		ids, err := store.CreateSeasonFixtures(season.ID, matches)
+		switch {
+		case err == errNotFound:
+			writeSeasonNotFound(w, r)
+			return
+		case err == errSeasonHasMatches:
+			writeError(w, r, http.StatusConflict, codeSeasonHasMatches, "Otra solicitud generó el calendario de la temporada al mismo tiempo")
+			return
+		case err != nil:
+			dbError(w, r, err)
+			return
+		}
+		for i := range matches {
+			matches[i].ID = ids[i]
+			audit(r, auditMatchCreate, ids[i], nil, matches[i])
+		}
+		matchesCreated.Add(float64(len(ids)))
+	}
+
+	// Repartir los partidos en sus jornadas, en el mismo orden en que se generaron
+	next := 0
+	for i, round := range rounds {
+		schedule.Matchdays[i].Matches = matches[next : next+len(round)]
+		next += len(round)
+	}
+
+	json.NewEncoder(w).Encode(schedule)
+}
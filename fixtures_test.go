@@ -0,0 +1,104 @@
+package main
+
+import "testing"
+
+// venueBreaks cuenta las veces que un equipo juega dos partidos seguidos en la misma condición
+// (local o visitante), separadas en la primera vuelta, la segunda y el cambio de vuelta.
+// Las jornadas en las que un equipo descansa no cortan la racha.
+func venueBreaks(schedule [][][2]int, n int) (first, second, change int) {
+	rounds := len(schedule) / 2
+	for team := 0; team < n; team++ {
+		last, lastRound := 0, -1 // 1 local, -1 visitante
+		for r, round := range schedule {
+			venue := 0
+			for _, p := range round {
+				switch team {
+				case p[0]:
+					venue = 1
+				case p[1]:
+					venue = -1
+				}
+			}
+			if venue == 0 {
+				continue
+			}
+			if venue == last {
+				switch {
+				case r < rounds:
+					first++
+				case lastRound >= rounds:
+					second++
+				default:
+					change++
+				}
+			}
+			last, lastRound = venue, r
+		}
+	}
+	return first, second, change
+}
+
+func TestRoundRobin(t *testing.T) {
+	for n := 2; n <= maxFixtureTeams; n++ {
+		schedule := roundRobin(n)
+
+		rounds := n - 1 + n%2
+		if len(schedule) != 2*rounds {
+			t.Errorf("n=%d: %d jornadas, se esperaban %d", n, len(schedule), 2*rounds)
+			continue
+		}
+
+		played := map[[2]int]int{}
+		home := make([]int, n)
+		for r, round := range schedule {
+			if len(round) != n/2 {
+				t.Errorf("n=%d: la jornada %d tiene %d partidos, se esperaban %d", n, r+1, len(round), n/2)
+			}
+			busy := map[int]bool{}
+			for _, p := range round {
+				if busy[p[0]] || busy[p[1]] || p[0] == p[1] {
+					t.Errorf("n=%d: un equipo juega dos veces en la jornada %d: %v", n, r+1, round)
+				}
+				busy[p[0]], busy[p[1]] = true, true
+				played[p]++
+				home[p[0]]++
+			}
+
+			// Ningún cruce se repite en jornadas seguidas, salvo con dos equipos
+			if r > 0 && n > 2 {
+				for _, p := range round {
+					for _, q := range schedule[r-1] {
+						if p == q || p == [2]int{q[1], q[0]} {
+							t.Errorf("n=%d: %v se repite en las jornadas %d y %d", n, p, r, r+1)
+						}
+					}
+				}
+			}
+		}
+
+		// Cada equipo recibe una vez a cada rival y lo visita una vez
+		for a := 0; a < n; a++ {
+			for b := 0; b < n; b++ {
+				if a != b && played[[2]int{a, b}] != 1 {
+					t.Errorf("n=%d: %d recibe a %d %d veces, se esperaba 1", n, a, b, played[[2]int{a, b}])
+				}
+			}
+			if home[a] != n-1 {
+				t.Errorf("n=%d: el equipo %d juega %d partidos de local, se esperaban %d", n, a, home[a], n-1)
+			}
+		}
+
+		// Repeticiones de condición indicadas en el comentario de roundRobin
+		wantFirst, wantSecond, wantChange := n-2, n, 2
+		switch {
+		case n == 2:
+			wantFirst, wantSecond, wantChange = 0, 0, 0
+		case n%2 == 1:
+			wantFirst, wantSecond, wantChange = 0, 0, 1
+		}
+		if first, second, change := venueBreaks(schedule, n); first != wantFirst || second != wantSecond || change != wantChange {
+			t.Errorf("n=%d: repeticiones de condición %d + %d + %d (primera vuelta, segunda y cambio), se esperaban %d + %d + %d",
+				n, first, second, change, wantFirst, wantSecond, wantChange)
+		}
+	}
+}
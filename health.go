@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+// readyCheckTimeout es el tiempo máximo que espera /readyz a la base de datos
+const readyCheckTimeout = 2 * time.Second
+
+// shuttingDown se activa al recibir la señal de apagado para que /readyz responda 503
+// durante shutdown_delay, antes de cerrar el puerto
+var shuttingDown atomic.Bool
+
+// HealthStatus representa la respuesta de las sondas de salud
+// @description Estado del servidor y resultado de cada verificación
+// @property status, checks
+// @example { "status": "ok", "checks": { "database": "ok", "migrations": "ok" } }
+type HealthStatus struct {
+	Status string            `json:"status" enums:"ok,unavailable,shutting_down"`
+	Checks map[string]string `json:"checks,omitempty"`
+}
+
+// writeHealth escribe el estado con el código HTTP correspondiente
+func writeHealth(w http.ResponseWriter, code int, status HealthStatus) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(status)
+}
+
+// @Summary Sonda de vida
+// @Description Responde 200 mientras el proceso atiende solicitudes. No consulta la base de datos.
+// @Tags health
+// @Produce json
+// @Success 200 {object} HealthStatus
+// @Router /healthz [get]
+func getHealthz(w http.ResponseWriter, r *http.Request) {
+	writeHealth(w, http.StatusOK, HealthStatus{Status: "ok"})
+}
+
+// @Summary Sonda de disponibilidad
+// @Description Responde 200 si la base de datos responde y todas las migraciones están aplicadas.
+// @Description Responde 503 si alguna verificación falla o si el servidor se está apagando.
+// @Tags health
+// @Produce json
+// @Success 200 {object} HealthStatus
+// @Failure 503 {object} HealthStatus
+// @Router /readyz [get]
+func getReadyz(w http.ResponseWriter, r *http.Request) {
+	if shuttingDown.Load() {
+		writeHealth(w, http.StatusServiceUnavailable, HealthStatus{Status: "shutting_down"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), readyCheckTimeout)
+	defer cancel()
+
+	status := HealthStatus{Status: "ok", Checks: map[string]string{"database": "ok", "migrations": "ok"}}
+	if err := store.Ping(ctx); err != nil {
+		status.Checks["database"] = err.Error()
+		status.Checks["migrations"] = "sin verificar"
+		status.Status = "unavailable"
+	} else if pending, err := store.PendingMigrations(ctx); err != nil {
+		status.Checks["migrations"] = err.Error()
+		status.Status = "unavailable"
+	} else if pending > 0 {
+		status.Checks["migrations"] = strconv.Itoa(pending) + " pendientes"
+		status.Status = "unavailable"
+	}
+
+	code := http.StatusOK
+	if status.Status != "ok" {
+		code = http.StatusServiceUnavailable
+	}
+	writeHealth(w, code, status)
+}
@@ -5,52 +5,63 @@ package main
 
 // Importa los paquetes necesarios para la implementación de la API REST
 import (
-	"database/sql"
 	"encoding/json"
-	"fmt"
 	"log"
+	"log/slog"
 	"net/http"
+	"os"
 	"regexp"
 	"strconv"
+	"strings"
 
 	_ "laligatracker/docs"
 
 	"github.com/gorilla/mux"
-	_ "github.com/mattn/go-sqlite3"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	httpSwagger "github.com/swaggo/http-swagger"
 )
 
 // Match representa un partido de fútbol
 // @description Modelo que contiene la información básica de un partido
-// @property id, homeTeam, awayTeam, matchDate, extraTime
-// @example { "id": 1, "homeTeam": "Real Madrid", "awayTeam": "Barcelona", "matchDate": "2025-05-10", "extraTime": "05:00" }
+// @property id, homeTeamId, homeTeam, awayTeamId, awayTeam, matchDate, extraTime, status, seasonId
+// @example { "id": 1, "homeTeamId": 1, "homeTeam": "Real Madrid", "awayTeamId": 2, "awayTeam": "Barcelona", "matchDate": "2025-05-10", "extraTime": "05:00", "status": "finished", "seasonId": 1 }
 type Match struct {
-	ID        int    `json:"id"`
-	HomeTeam  string `json:"homeTeam"`
-	AwayTeam  string `json:"awayTeam"`
-	MatchDate string `json:"matchDate"`
-	ExtraTime string `json:"extraTime"`
+	ID         int    `json:"id"`
+	HomeTeamID int    `json:"homeTeamId"`
+	HomeTeam   string `json:"homeTeam"`
+	AwayTeamID int    `json:"awayTeamId"`
+	AwayTeam   string `json:"awayTeam"`
+	MatchDate  string `json:"matchDate"`
+	ExtraTime  string `json:"extraTime"`
+	Status     string `json:"status"`
+	SeasonID   int    `json:"seasonId,omitempty"` // Temporada de una competición; 0 si el partido no tiene
 }
 
 // MatchEvent representa un evento de un partido (gol, tarjeta amarilla o roja)
 // @description Modelo que contiene la información de un evento en un partido
-// @property id, team, player, minute
+// @property id, teamId, team, playerId, player, minute
 type MatchEvent struct {
-	ID     int    `json:"id"`
-	Team   string `json:"team"`
-	Player string `json:"player"`
-	Minute string `json:"minute"`
+	ID       int    `json:"id"`
+	TeamID   int    `json:"teamId"`
+	Team     string `json:"team"`
+	PlayerID int    `json:"playerId,omitempty"`
+	Player   string `json:"player"`
+	Minute   string `json:"minute"`
 }
 
 // FullMatchData representa un partido completo con eventos
 // @description Modelo que contiene la información completa de un partido, incluyendo eventos
-// @property id, homeTeam, awayTeam, matchDate, extraTime, homeGoals, awayGoals, goals, yellowCards, redCards
+// @property id, homeTeamId, homeTeam, awayTeamId, awayTeam, matchDate, extraTime, status, seasonId, homeGoals, awayGoals, goals, yellowCards, redCards
 type FullMatchData struct {
 	ID                   int          `json:"id"`
+	HomeTeamID           int          `json:"homeTeamId"`
 	HomeTeam             string       `json:"homeTeam"`
+	AwayTeamID           int          `json:"awayTeamId"`
 	AwayTeam             string       `json:"awayTeam"`
 	MatchDate            string       `json:"matchDate"`
 	ExtraTime            string       `json:"extraTime"`
+	Status               string       `json:"status"`
+	SeasonID             int          `json:"seasonId,omitempty"`
 	HomeGoals            int          `json:"homeGoals"`
 	AwayGoals            int          `json:"awayGoals"`
 	Goals                []MatchEvent `json:"goals"`
@@ -64,11 +75,13 @@ type FullMatchData struct {
 
 // EventPayload representa la carga útil de un evento (gol, tarjeta amarilla o roja)
 // @description Modelo que contiene la información de un evento en un partido
-// @property Team, Player, Minute
+// @property TeamID, Team, PlayerID, Player, Minute
 type EventPayload struct {
-	Team   string `json:"team"`
-	Player string `json:"player"`
-	Minute string `json:"minute"`
+	TeamID   int    `json:"teamId"`
+	Team     string `json:"team"`
+	PlayerID int    `json:"playerId"`
+	Player   string `json:"player"`
+	Minute   string `json:"minute"`
 }
 
 // ExtraTimePayload representa la carga útil para establecer el tiempo extra
@@ -78,65 +91,67 @@ type ExtraTimePayload struct {
 	ExtraTime string `json:"extraTime"`
 }
 
-// db es la variable global que representa la conexión a la base de datos SQLite
-var db *sql.DB
+// dbPath es la ruta del archivo de la base de datos SQLite
+const dbPath = "./database/matches.db"
+
+// pathID lee un ID numérico de los parámetros de la URL.
+// Un valor que no es un número no corresponde a ningún registro, por eso se devuelve 0,
+// que ningún almacenamiento asigna como ID.
+func pathID(r *http.Request, name string) int {
+	id, _ := strconv.Atoi(mux.Vars(r)[name])
+	return id
+}
 
 // @Summary Obtener todos los partidos
-// @Description Retorna una lista con todos los partidos registrados
+// @Description Retorna una página de partidos, con filtros opcionales por equipo, fechas, estado, competición y temporada.
+// @Description El total de partidos se informa en X-Total-Count y los enlaces first, prev, next y last en el encabezado Link.
 // @Tags matches
 // @Accept json
 // @Produce json
-// @Success 200 {array} Match
+// @Param page query int false "Número de página (desde 1)"
+// @Param limit query int false "Partidos por página (1-500, por defecto 100)"
+// @Param team query string false "ID o nombre del equipo (local o visitante)"
+// @Param from query string false "Fecha inicial (YYYY-MM-DD)"
+// @Param to query string false "Fecha final (YYYY-MM-DD)"
+// @Param status query string false "Estados separados por comas (por ejemplo live,half_time)"
+// @Param season query string false "Temporada de julio a junio (por ejemplo 2024-25)"
+// @Param competition query string false "ID o nombre de la competición"
+// @Param seasonId query int false "ID de la temporada"
+// @Param sort query string false "Orden: id, -id, date o -date" default(id)
+// @Success 200 {array} FullMatchData
+// @Header 200 {integer} X-Total-Count "Total de partidos que cumplen los filtros"
+// @Header 200 {string} Link "Enlaces de paginación (RFC 8288)"
+// @Failure 400 {object} Problem
+// @Failure 401 {object} Problem
+// @Failure 429 {object} Problem
+// @Failure 500 {object} Problem
+// @Security ApiKeyAuth
+// @Security BearerAuth
 // @Router /api/matches [get]
 func getMatches(w http.ResponseWriter, r *http.Request) {
-	// Ejecutar la consulta para obtener todos los partidos
-	rows, err := db.Query("SELECT id, home_team, away_team, match_date, extra_time FROM matches")
-
-	// Verificar si hubo un error al ejecutar la consulta
-	// Si hubo un error, devolver un error 500
-	// y cerrar la conexión a la base de datos
-	if err != nil {
-		http.Error(w, err.Error(), 500)
+	// Leer los filtros, el orden y la página pedidos
+	lq, errs, err := parseMatchListQuery(r)
+	if err == errSeasonNotFound {
+		writeSeasonNotFound(w, r)
+		return
+	} else if err != nil {
+		dbError(w, r, err)
+		return
+	}
+	if errs != nil {
+		writeValidation(w, r, errs...)
 		return
 	}
 
-	// Asegurarse de cerrar las filas después de usarlas
-	defer rows.Close()
-
-	// Crear un slice para almacenar los partidos
-	var matches []FullMatchData
-
-	// Iterar sobre las filas y escanear los datos en la estructura Match
-	for rows.Next() {
-		// Crear una variable para almacenar el partido
-		var m FullMatchData
-
-		// Escanear cada fila en la estructura Match y agregarla al slice
-		err := rows.Scan(&m.ID, &m.HomeTeam, &m.AwayTeam, &m.MatchDate, &m.ExtraTime)
-
-		// Verificar si hubo un error al escanear la fila
-		// Si hubo un error, devolver un error 500
-		// y cerrar la conexión a la base de datos
-		if err != nil {
-			http.Error(w, err.Error(), 500)
-			return
-		}
-
-		// Contar goles por equipo y asignar a los campos correspondientes
-		db.QueryRow("SELECT COUNT(*) FROM goals WHERE match_id = ? AND team = ?", m.ID, m.HomeTeam).Scan(&m.HomeGoals)
-		db.QueryRow("SELECT COUNT(*) FROM goals WHERE match_id = ? AND team = ?", m.ID, m.AwayTeam).Scan(&m.AwayGoals)
-
-		// Contar tarjetas amarillas y rojas por equipo y asignar a los campos correspondientes
-		db.QueryRow("SELECT COUNT(*) FROM yellow_cards WHERE match_id = ? AND team = ?", m.ID, m.HomeTeam).Scan(&m.HomeYellowCardsCount)
-		db.QueryRow("SELECT COUNT(*) FROM red_cards WHERE match_id = ? AND team = ?", m.ID, m.HomeTeam).Scan(&m.HomeRedCardsCount)
-		db.QueryRow("SELECT COUNT(*) FROM yellow_cards WHERE match_id = ? AND team = ?", m.ID, m.AwayTeam).Scan(&m.AwayYellowCardsCount)
-		db.QueryRow("SELECT COUNT(*) FROM red_cards WHERE match_id = ? AND team = ?", m.ID, m.AwayTeam).Scan(&m.AwayRedCardsCount)
-
-		// Agregar el partido al slice
-		matches = append(matches, m)
+	// Obtener los partidos de la página con sus goles y tarjetas
+	// junto con el total de partidos que cumplen los filtros
+	matches, total, err := store.Matches(lq)
+	if err != nil {
+		dbError(w, r, err)
+		return
 	}
 
-	// Verificar si hubo un error al iterar sobre las filas
+	setPaginationHeaders(w, r, lq, total)
 	json.NewEncoder(w).Encode(matches)
 }
 
@@ -147,79 +162,81 @@ func getMatches(w http.ResponseWriter, r *http.Request) {
 // @Produce json
 // @Param id path int true "ID del partido"
 // @Success 200 {object} Match
-// @Failure 404 {object} map[string]string
+// @Failure 401 {object} Problem
+// @Failure 404 {object} Problem
+// @Failure 429 {object} Problem
+// @Failure 500 {object} Problem
+// @Security ApiKeyAuth
+// @Security BearerAuth
 // @Router /api/matches/{id} [get]
 func getMatch(w http.ResponseWriter, r *http.Request) {
-	// Obtener el ID del partido de los parámetros de la URL
-	id := mux.Vars(r)["id"]
-	// Ejecutar la consulta para obtener el partido por ID
-	row := db.QueryRow("SELECT id, home_team, away_team, match_date, extra_time FROM matches WHERE id = ?", id)
-
-	// Crear una variable para almacenar el partido
-	var m FullMatchData
-
-	// Escanear la fila en la estructura Match
-	err := row.Scan(&m.ID, &m.HomeTeam, &m.AwayTeam, &m.MatchDate, &m.ExtraTime)
+	// Obtener el partido por el ID de los parámetros de la URL
+	m, err := matchWithEvents(pathID(r, "id"))
 
-	// Verificar si hubo un error al escanear la fila
-	// Si hubo un error, devolver un error 404
-	// y cerrar la conexión a la base de datos
-	if err != nil {
-		http.Error(w, "Partido no encontrado", 404)
+	// Si no existe, devolver un error 404
+	if err == errNotFound {
+		writeError(w, r, http.StatusNotFound, codeMatchNotFound, "Partido no encontrado")
+		return
+	} else if err != nil {
+		dbError(w, r, err)
 		return
 	}
 
-	// Contar goles por equipo y asignar a los campos correspondientes
-	db.QueryRow("SELECT COUNT(*) FROM goals WHERE match_id = ? AND team = ?", id, m.HomeTeam).Scan(&m.HomeGoals)
-	db.QueryRow("SELECT COUNT(*) FROM goals WHERE match_id = ? AND team = ?", id, m.AwayTeam).Scan(&m.AwayGoals)
-
-	// Contar tarjetas amarillas y rojas por equipo y asignar a los campos correspondientes
-	db.QueryRow("SELECT COUNT(*) FROM yellow_cards WHERE match_id = ? AND team = ?", id, m.HomeTeam).Scan(&m.HomeYellowCardsCount)
-	db.QueryRow("SELECT COUNT(*) FROM yellow_cards WHERE match_id = ? AND team = ?", id, m.AwayTeam).Scan(&m.AwayYellowCardsCount)
-	db.QueryRow("SELECT COUNT(*) FROM red_cards WHERE match_id = ? AND team = ?", id, m.HomeTeam).Scan(&m.HomeRedCardsCount)
-	db.QueryRow("SELECT COUNT(*) FROM red_cards WHERE match_id = ? AND team = ?", id, m.AwayTeam).Scan(&m.AwayRedCardsCount)
-
-	// Listado de goles
-	m.Goals = fetchEvents("goals", id)
-
-	// Listado de tarjetas
-	m.YellowCards = fetchEvents("yellow_cards", id)
-	m.RedCards = fetchEvents("red_cards", id)
-
 	// Devolver el partido encontrado como respuesta JSON
 	json.NewEncoder(w).Encode(m)
 }
 
-// fetchEvents obtiene los eventos de un partido específico
-// y devuelve un slice de MatchEvent
-func fetchEvents(table string, matchID string) []MatchEvent {
-	// Inicializa un slice vacío para almacenar los eventos
-	var events []MatchEvent
+// matchWithEvents obtiene un partido con el listado de goles y tarjetas y los totales por equipo.
+// Devuelve errNotFound si el partido no existe.
+func matchWithEvents(id int) (FullMatchData, error) {
+	match, err := store.Match(id)
+	if err != nil {
+		return FullMatchData{}, err
+	}
 
-	// Ejecuta la consulta para obtener los eventos del partido específico
-	// y escanea los resultados en la estructura MatchEvent
-	rows, err := db.Query("SELECT id, team, player, minute FROM "+table+" WHERE match_id = ?", matchID)
+	m := FullMatchData{
+		ID:         match.ID,
+		HomeTeamID: match.HomeTeamID,
+		HomeTeam:   match.HomeTeam,
+		AwayTeamID: match.AwayTeamID,
+		AwayTeam:   match.AwayTeam,
+		MatchDate:  match.MatchDate,
+		ExtraTime:  match.ExtraTime,
+		Status:     match.Status,
+		SeasonID:   match.SeasonID,
+	}
+	if err := fetchMatchEvents(&m); err != nil {
+		return FullMatchData{}, err
+	}
+	return m, nil
+}
 
-	// Verifica si hubo un error al ejecutar la consulta
-	// Si hubo un error, devuelve un slice vacío
+// fetchMatchEvents obtiene los goles, tarjetas amarillas y rojas de un partido
+// y calcula a partir de ellos los totales de cada equipo
+func fetchMatchEvents(m *FullMatchData) error {
+	events, err := store.MatchEvents(m.ID)
 	if err != nil {
-		return events // Retornar slice vacío si hay error
-	}
-	defer rows.Close()
-
-	// Itera sobre las filas y escanea los datos en la estructura MatchEvent
-	// y agrega cada evento al slice de eventos
-	for rows.Next() {
-		// Crea una variable para almacenar el evento
-		var e MatchEvent
-		// Escanea cada fila en la estructura MatchEvent
-		// y agrega el evento al slice
-		if err := rows.Scan(&e.ID, &e.Team, &e.Player, &e.Minute); err == nil {
-			events = append(events, e)
+		return err
+	}
+	m.Goals = events["goals"]
+	m.YellowCards = events["yellow_cards"]
+	m.RedCards = events["red_cards"]
+
+	// Sumar cada evento al total del equipo local o visitante
+	count := func(list []MatchEvent, home, away *int) {
+		for _, e := range list {
+			switch e.TeamID {
+			case m.HomeTeamID:
+				*home++
+			case m.AwayTeamID:
+				*away++
+			}
 		}
 	}
-	// Retorna el slice de eventos
-	return events
+	count(m.Goals, &m.HomeGoals, &m.AwayGoals)
+	count(m.YellowCards, &m.HomeYellowCardsCount, &m.AwayYellowCardsCount)
+	count(m.RedCards, &m.HomeRedCardsCount, &m.AwayRedCardsCount)
+	return nil
 }
 
 // isValidTimeFormat valida el formato de tiempo extra
@@ -230,14 +247,62 @@ func isValidTimeFormat(extraTime string) bool {
 	return match
 }
 
+// missingMatchFields devuelve los campos requeridos que faltan en un partido:
+// el equipo local, el visitante (por ID o nombre) y matchDate
+func missingMatchFields(m Match) []FieldError {
+	var errs []FieldError
+	if m.HomeTeam == "" && m.HomeTeamID == 0 {
+		errs = append(errs, FieldError{"homeTeam", fieldRequired, "El equipo local es obligatorio"})
+	}
+	if m.AwayTeam == "" && m.AwayTeamID == 0 {
+		errs = append(errs, FieldError{"awayTeam", fieldRequired, "El equipo visitante es obligatorio"})
+	}
+	if m.MatchDate == "" {
+		errs = append(errs, FieldError{"matchDate", fieldRequired, "La fecha del partido es obligatoria"})
+	}
+	return errs
+}
+
+// resolveMatchTeams completa los IDs y nombres oficiales de los equipos de un partido.
+// Los equipos pueden indicarse por ID (homeTeamId/awayTeamId) o por nombre (homeTeam/awayTeam).
+// Devuelve el detalle de los equipos que no son válidos.
+func resolveMatchTeams(m *Match) ([]FieldError, error) {
+	var errs []FieldError
+	var homeErr, awayErr error
+	m.HomeTeamID, m.HomeTeam, homeErr = resolveTeam(m.HomeTeamID, m.HomeTeam)
+	if homeErr == errTeamNotFound {
+		errs = append(errs, FieldError{"homeTeam", fieldNotFound, "Equipo local no encontrado"})
+	} else if homeErr != nil {
+		return nil, homeErr
+	}
+
+	m.AwayTeamID, m.AwayTeam, awayErr = resolveTeam(m.AwayTeamID, m.AwayTeam)
+	if awayErr == errTeamNotFound {
+		errs = append(errs, FieldError{"awayTeam", fieldNotFound, "Equipo visitante no encontrado"})
+	} else if awayErr != nil {
+		return nil, awayErr
+	}
+
+	if errs == nil && m.HomeTeamID == m.AwayTeamID {
+		errs = append(errs, FieldError{"awayTeam", fieldInvalid, "El equipo local y el visitante deben ser distintos"})
+	}
+	return errs, nil
+}
+
 // @Summary Crear un nuevo partido
-// @Description Crea un nuevo registro de partido con los datos básicos
+// @Description Crea un nuevo registro de partido con los datos básicos. Con seasonId la fecha debe estar dentro de la temporada.
 // @Tags matches
 // @Accept json
 // @Produce json
 // @Param match body Match true "Datos del partido"
 // @Success 200 {object} Match
-// @Failure 400 {object} map[string]string
+// @Failure 400 {object} Problem
+// @Failure 401 {object} Problem
+// @Failure 403 {object} Problem
+// @Failure 429 {object} Problem
+// @Failure 500 {object} Problem
+// @Security ApiKeyAuth
+// @Security BearerAuth
 // @Router /api/matches [post]
 func createMatch(w http.ResponseWriter, r *http.Request) {
 	// Leer el cuerpo de la solicitud y decodificarlo en la estructura Match
@@ -247,116 +312,215 @@ func createMatch(w http.ResponseWriter, r *http.Request) {
 	// Si hubo un error, devolver un error 400
 	// y cerrar la conexión a la base de datos
 	if err != nil {
-		http.Error(w, "JSON inválido", http.StatusBadRequest)
+		writeInvalidJSON(w, r)
 		return
 	}
 
 	// Verificar si los campos requeridos están presentes
 	// Si faltan campos, devolver un error 400
 	// y cerrar la conexión a la base de datos
-	// Los campos requeridos son homeTeam, awayTeam y matchDate
-	if m.HomeTeam == "" || m.AwayTeam == "" || m.MatchDate == "" {
-		http.Error(w, "Todos los campos son obligatorios", http.StatusBadRequest)
+	// Los campos requeridos son el equipo local, el visitante (por ID o nombre) y matchDate
+	if errs := missingMatchFields(m); errs != nil {
+		writeValidation(w, r, errs...)
+		return
+	}
+
+	// Resolver los equipos contra la tabla teams
+	if errs, err := resolveMatchTeams(&m); err != nil {
+		dbError(w, r, err)
+		return
+	} else if errs != nil {
+		writeValidation(w, r, errs...)
+		return
+	}
+
+	// La fecha del partido tiene que caer dentro de su temporada, si se indicó una
+	if errs, err := resolveMatchSeason(m); err != nil {
+		dbError(w, r, err)
+		return
+	} else if errs != nil {
+		writeValidation(w, r, errs...)
 		return
 	}
 
 	// InsertaR solo los campos requeridos, los demás se usarán los valores por defecto
-	res, err := db.Exec(`INSERT INTO matches (home_team, away_team, match_date) VALUES (?, ?, ?)`, m.HomeTeam, m.AwayTeam, m.MatchDate)
+	id, err := store.CreateMatch(m)
 
 	// Verificar si hubo un error al insertar el partido
 	// Si hubo un error, devolver un error 500
 	// y cerrar la conexión a la base de datos
 	if err != nil {
-		http.Error(w, err.Error(), 500)
+		dbError(w, r, err)
 		return
 	}
 
+	matchesCreated.Inc()
+
 	// Obtener el ID del nuevo partido insertado
 	// y asignar valores por defecto a los demás campos
-	id, _ := res.LastInsertId()
-	m.ID = int(id)
+	m.ID = id
 	m.ExtraTime = "00:00"
+	m.Status = StatusScheduled
+	audit(r, auditMatchCreate, m.ID, nil, m)
 	json.NewEncoder(w).Encode(m)
 }
 
 // @Summary Actualizar partido
-// @Description Modifica los datos de un partido existente por ID
+// @Description Modifica los datos de un partido existente por ID. Sin seasonId el partido se mantiene en su temporada; con seasonId null la deja.
 // @Tags matches
 // @Accept json
 // @Produce json
 // @Param id path int true "ID del partido"
 // @Param match body Match true "Datos actualizados"
 // @Success 200 {object} Match
-// @Failure 400 {object} map[string]string
-// @Failure 500 {object} map[string]string
+// @Failure 400 {object} Problem
+// @Failure 401 {object} Problem
+// @Failure 403 {object} Problem
+// @Failure 404 {object} Problem
+// @Failure 429 {object} Problem
+// @Failure 500 {object} Problem
+// @Security ApiKeyAuth
+// @Security BearerAuth
 // @Router /api/matches/{id} [put]
 func updateMatch(w http.ResponseWriter, r *http.Request) {
 	// Obtener el ID del partido de los parámetros de la URL
 	// y leer el cuerpo de la solicitud para decodificarlo en la estructura Match
-	id := mux.Vars(r)["id"]
-	var m Match
-	err := json.NewDecoder(r.Body).Decode(&m)
+	id := pathID(r, "id")
+	// seasonId se lee aparte para distinguir si falta (se mantiene), es null (se quita) o es un ID (se cambia)
+	var body struct {
+		Match
+		SeasonID json.RawMessage `json:"seasonId"`
+	}
+	err := json.NewDecoder(r.Body).Decode(&body)
+	m := body.Match
+	keepSeason := body.SeasonID == nil
+	if err == nil && !keepSeason && string(body.SeasonID) != "null" {
+		err = json.Unmarshal(body.SeasonID, &m.SeasonID)
+	}
 
 	// Verificar si hubo un error al decodificar el JSON
 	// Si hubo un error, devolver un error 400
 	// y cerrar la conexión a la base de datos
 	if err != nil {
-		http.Error(w, "JSON inválido", http.StatusBadRequest)
+		writeInvalidJSON(w, r)
 		return
 	}
 
 	// Verificar si los campos requeridos están presentes
 	// Si faltan campos, devolver un error 400
 	// y cerrar la conexión a la base de datos
-	// Los campos requeridos son homeTeam, awayTeam y matchDate
-	if m.HomeTeam == "" || m.AwayTeam == "" || m.MatchDate == "" {
-		http.Error(w, "Todos los campos son obligatorios", http.StatusBadRequest)
+	// Los campos requeridos son el equipo local, el visitante (por ID o nombre) y matchDate
+	if errs := missingMatchFields(m); errs != nil {
+		writeValidation(w, r, errs...)
+		return
+	}
+
+	// Resolver los equipos contra la tabla teams
+	if errs, err := resolveMatchTeams(&m); err != nil {
+		dbError(w, r, err)
+		return
+	} else if errs != nil {
+		writeValidation(w, r, errs...)
+		return
+	}
+
+	// Guardar cómo estaba el partido para el historial de auditoría
+	before, err := store.Match(id)
+	if err == errNotFound {
+		writeError(w, r, http.StatusNotFound, codeMatchNotFound, "Partido no encontrado")
+		return
+	} else if err != nil {
+		dbError(w, r, err)
+		return
+	}
+
+	// Sin seasonId el partido sigue en su temporada, y la nueva fecha tiene que caer dentro de ella
+	if keepSeason {
+		m.SeasonID = before.SeasonID
+	}
+	if errs, err := resolveMatchSeason(m); err != nil {
+		dbError(w, r, err)
+		return
+	} else if errs != nil {
+		writeValidation(w, r, errs...)
 		return
 	}
 
 	// Solo actualizar los campos requeridos, los opcionales se mantienen sin cambios (para eso se usará PATCH)
-	_, err = db.Exec(`UPDATE matches SET home_team=?, away_team=?, match_date=? WHERE id=?`,
-		m.HomeTeam, m.AwayTeam, m.MatchDate, id)
+	// El estado tampoco se modifica aquí; se cambia con PATCH /api/matches/{id}/status
+	m.ID = id
+	err = store.UpdateMatch(m)
 
 	// Verificar si hubo un error al actualizar el partido
 	// Si hubo un error, devolver un error 500
 	// y cerrar la conexión a la base de datos
-	if err != nil {
-		http.Error(w, err.Error(), 500)
+	// El partido pudo eliminarse después de leerlo
+	if err == errNotFound {
+		writeError(w, r, http.StatusNotFound, codeMatchNotFound, "Partido no encontrado")
+		return
+	} else if err != nil {
+		dbError(w, r, err)
 		return
 	}
 
-	// Asignar el ID del partido actualizado a la estructura Match
-	// y devolver el partido actualizado como respuesta JSON
-	m.ID, _ = strconv.Atoi(id)
+	// Notificar el cambio a los clientes conectados al stream
+	// con los datos completos del partido (incluye tiempo extra y estado)
+	if updated, err := store.Match(id); err == nil {
+		m = updated
+	}
+	broker.Publish(m.ID, LiveMatchUpdated, m)
+	audit(r, auditMatchUpdate, m.ID, before, m)
+
 	json.NewEncoder(w).Encode(m)
 }
 
 // @Summary Eliminar partido
-// @Description Elimina un partido de la base de datos por ID
+// @Description Elimina un partido de la base de datos por ID junto con sus goles y tarjetas
 // @Tags matches
 // @Accept json
 // @Produce json
 // @Param id path int true "ID del partido"
 // @Success 204 {string} string "Sin contenido"
-// @Failure 500 {object} map[string]string
+// @Failure 401 {object} Problem
+// @Failure 403 {object} Problem
+// @Failure 404 {object} Problem
+// @Failure 429 {object} Problem
+// @Failure 500 {object} Problem
+// @Security ApiKeyAuth
+// @Security BearerAuth
 // @Router /api/matches/{id} [delete]
 func deleteMatch(w http.ResponseWriter, r *http.Request) {
 	// Obtener el ID del partido de los parámetros de la URL
-	// y ejecutar la consulta para eliminar el partido por ID
-	id := mux.Vars(r)["id"]
-	_, err := db.Exec("DELETE FROM matches WHERE id=?", id)
+	matchID := pathID(r, "id")
 
-	// Verificar si hubo un error al eliminar el partido
-	// Si hubo un error, devolver un error 500
-	// y cerrar la conexión a la base de datos
-	if err != nil {
-		http.Error(w, err.Error(), 500)
+	// Guardar el partido con sus eventos para el historial de auditoría
+	before, err := matchWithEvents(matchID)
+	if err == errNotFound {
+		writeError(w, r, http.StatusNotFound, codeMatchNotFound, "Partido no encontrado")
+		return
+	} else if err != nil {
+		dbError(w, r, err)
+		return
+	}
+
+	// Eliminar el partido y sus eventos en una sola transacción
+	err = store.DeleteMatch(matchID)
+
+	// Si no se eliminó ninguna fila, el partido no existe
+	if err == errNotFound {
+		writeError(w, r, http.StatusNotFound, codeMatchNotFound, "Partido no encontrado")
+		return
+	} else if err != nil {
+		dbError(w, r, err)
 		return
 	}
 
+	// Notificar la eliminación a los clientes conectados al stream
+	// Los equipos van en el evento porque el partido ya no se puede consultar
+	broker.Publish(matchID, LiveMatchDeleted, map[string]int{"id": matchID, "homeTeamId": before.HomeTeamID, "awayTeamId": before.AwayTeamID})
+	audit(r, auditMatchDelete, matchID, before, nil)
+
 	// Devolver un código de estado 204 (Sin contenido) si la eliminación fue exitosa
-	// y cerrar la conexión a la base de datos
 	w.WriteHeader(http.StatusNoContent)
 }
 
@@ -364,56 +528,55 @@ func deleteMatch(w http.ResponseWriter, r *http.Request) {
 // y lo inserta en la base de datos
 func registerEvent(w http.ResponseWriter, r *http.Request, table string) {
 	// Obtener el ID del partido de los parámetros de la URL
-	id := mux.Vars(r)["id"]
+	matchID := pathID(r, "id")
 
 	// Leer el cuerpo de la solicitud y decodificarlo en la estructura correspondiente
 	var payload EventPayload
 
 	// Verificar si hubo un error al decodificar el JSON
 	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
-		http.Error(w, "JSON inválido", http.StatusBadRequest)
+		writeInvalidJSON(w, r)
 		return
 	}
 
-	// Validar campos vacíos
-	if payload.Team == "" || payload.Player == "" || payload.Minute == "" {
-		http.Error(w, "Todos los campos son requeridos", http.StatusBadRequest)
+	// Validar el evento y resolver su equipo y jugador
+	ev, problem, err := validateEvent(matchID, payload)
+	if err != nil {
+		dbError(w, r, err)
 		return
-	}
-
-	if !isValidTimeFormat(payload.Minute) {
-		http.Error(w, "Formato de tiempo inválido. Usa MM:SS", http.StatusBadRequest)
+	} else if problem != nil {
+		writeProblem(w, r, problem)
 		return
 	}
 
-	// Verificar si el partido existe y obtener nombres reales de los equipos
-	var home, away string
-	err := db.QueryRow("SELECT home_team, away_team FROM matches WHERE id = ?", id).Scan(&home, &away)
+	// Solo se aceptan eventos con el partido en juego o recién terminado
+	status, statusUpdatedAt, err := store.MatchStatus(matchID)
 	if err != nil {
-		http.Error(w, "Partido no encontrado", http.StatusNotFound)
+		dbError(w, r, err)
 		return
 	}
-
-	// Validar que el equipo exista en este partido
-	if payload.Team != home && payload.Team != away {
-		http.Error(w, "El equipo no corresponde al partido", http.StatusBadRequest)
+	if !acceptsEvents(status, statusUpdatedAt) {
+		writeError(w, r, http.StatusConflict, codeMatchNotInPlay, "El partido no está en juego (estado: "+status+")")
 		return
 	}
 
 	// Insertar el evento en la base de datos
 	// Dependiendo de la tabla, se insertará en la tabla correspondiente (goals, yellow_cards o red_cards)
-	_, err = db.Exec(fmt.Sprintf(`
-		INSERT INTO %s (match_id, team, player, minute) 
-		VALUES (?, ?, ?, ?)`, table), id, payload.Team, payload.Player, payload.Minute)
+	eventID, err := store.CreateEvent(table, matchID, ev)
 
 	// Verificar si hubo un error al insertar el evento
 	// Si hubo un error, devolver un error 500
 	// y cerrar la conexión a la base de datos
 	if err != nil {
-		http.Error(w, "Error al registrar el gol", http.StatusInternalServerError)
+		dbError(w, r, err)
 		return
 	}
 
+	// Notificar el evento a los clientes conectados al stream
+	eventsRegistered.WithLabelValues(liveEventTypes[table]).Inc()
+	broker.Publish(matchID, liveEventTypes[table], ev.event(eventID))
+	audit(r, liveEventTypes[table]+"."+auditEventCreate, matchID, nil, ev.event(eventID))
+
 	// Mapeo de tabla → mensaje de respuesta
 	// Dependiendo de la tabla, se asigna un mensaje diferente
 	var message string
@@ -441,9 +604,15 @@ func registerEvent(w http.ResponseWriter, r *http.Request, table string) {
 // @Param id path int true "ID del partido"
 // @Param goal body EventPayload true "Datos del gol"
 // @Success 200 {object} map[string]string
-// @Failure 400 {object} map[string]string
-// @Failure 404 {object} map[string]string
-// @Failure 500 {object} map[string]string
+// @Failure 400 {object} Problem
+// @Failure 401 {object} Problem
+// @Failure 403 {object} Problem
+// @Failure 404 {object} Problem
+// @Failure 409 {object} Problem
+// @Failure 429 {object} Problem
+// @Failure 500 {object} Problem
+// @Security ApiKeyAuth
+// @Security BearerAuth
 // @Router /api/matches/{id}/goals [patch]
 func registerGoal(w http.ResponseWriter, r *http.Request) {
 	registerEvent(w, r, "goals")
@@ -457,9 +626,15 @@ func registerGoal(w http.ResponseWriter, r *http.Request) {
 // @Param id path int true "ID del partido"
 // @Param yellow_card body EventPayload true "Datos de la tarjeta amarilla"
 // @Success 200 {object} map[string]string
-// @Failure 400 {object} map[string]string
-// @Failure 404 {object} map[string]string
-// @Failure 500 {object} map[string]string
+// @Failure 400 {object} Problem
+// @Failure 401 {object} Problem
+// @Failure 403 {object} Problem
+// @Failure 404 {object} Problem
+// @Failure 409 {object} Problem
+// @Failure 429 {object} Problem
+// @Failure 500 {object} Problem
+// @Security ApiKeyAuth
+// @Security BearerAuth
 // @Router /api/matches/{id}/yellow_cards [patch]
 func registerYellowCard(w http.ResponseWriter, r *http.Request) {
 	registerEvent(w, r, "yellow_cards")
@@ -473,15 +648,20 @@ func registerYellowCard(w http.ResponseWriter, r *http.Request) {
 // @Param id path int true "ID del partido"
 // @Param red_card body EventPayload true "Datos de la tarjeta roja"
 // @Success 200 {object} map[string]string
-// @Failure 400 {object} map[string]string
-// @Failure 404 {object} map[string]string
-// @Failure 500 {object} map[string]string
+// @Failure 400 {object} Problem
+// @Failure 401 {object} Problem
+// @Failure 403 {object} Problem
+// @Failure 404 {object} Problem
+// @Failure 409 {object} Problem
+// @Failure 429 {object} Problem
+// @Failure 500 {object} Problem
+// @Security ApiKeyAuth
+// @Security BearerAuth
 // @Router /api/matches/{id}/red_cards [patch]
 func registerRedCard(w http.ResponseWriter, r *http.Request) {
 	registerEvent(w, r, "red_cards")
 }
 
-
 // @Summary Establecer tiempo extra
 // @Description Establece el valor de tiempo extra en un partido específico
 // @Tags matches
@@ -490,59 +670,83 @@ func registerRedCard(w http.ResponseWriter, r *http.Request) {
 // @Param id path int true "ID del partido"
 // @Param extra_time body ExtraTimePayload true "Tiempo extra en formato MM:SS"
 // @Success 200 {object} map[string]string
-// @Failure 400 {object} map[string]string
-// @Failure 404 {object} map[string]string
+// @Failure 400 {object} Problem
+// @Failure 401 {object} Problem
+// @Failure 403 {object} Problem
+// @Failure 404 {object} Problem
+// @Failure 429 {object} Problem
+// @Failure 500 {object} Problem
+// @Security ApiKeyAuth
+// @Security BearerAuth
 // @Router /api/matches/{id}/extratime [patch]
 func setExtraTime(w http.ResponseWriter, r *http.Request) {
-	id := mux.Vars(r)["id"]
+	matchID := pathID(r, "id")
 	var payload ExtraTimePayload
 
-	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil || payload.ExtraTime == "" {
-		http.Error(w, "JSON inválido o tiempo extra faltante", http.StatusBadRequest)
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		writeInvalidJSON(w, r)
+		return
+	}
+	if payload.ExtraTime == "" {
+		writeValidation(w, r, FieldError{"extraTime", fieldRequired, "El tiempo extra es obligatorio"})
 		return
 	}
 
 	// Verificar que el partido exista
-	var exists bool
-	err := db.QueryRow("SELECT EXISTS(SELECT 1 FROM matches WHERE id=?)", id).Scan(&exists)
-	if err != nil || !exists {
-		http.Error(w, "Partido no encontrado", http.StatusNotFound)
+	match, err := store.Match(matchID)
+	if err == errNotFound {
+		writeError(w, r, http.StatusNotFound, codeMatchNotFound, "Partido no encontrado")
+		return
+	} else if err != nil {
+		dbError(w, r, err)
 		return
 	}
 
 	// Validar el formato del tiempo extra
 	if !isValidTimeFormat(payload.ExtraTime) {
-		http.Error(w, "Formato de tiempo inválido. Usa MM:SS", http.StatusBadRequest)
+		writeValidation(w, r, FieldError{"extraTime", fieldInvalidFormat, "Formato de tiempo inválido. Usa MM:SS"})
 		return
 	}
 
 	// Actualizar el tiempo extra en la base de datos
-	_, err = db.Exec("UPDATE matches SET extra_time=? WHERE id=?", payload.ExtraTime, id)
+	err = store.SetExtraTime(matchID, payload.ExtraTime)
 
 	// Verificar si hubo un error al actualizar el tiempo extra
 	// Si hubo un error, devolver un error 500
 	if err != nil {
-		http.Error(w, "Error al actualizar el tiempo extra", http.StatusInternalServerError)
+		dbError(w, r, err)
 		return
 	}
 
+	// Notificar el cambio a los clientes conectados al stream
+	broker.Publish(matchID, LiveExtraTime, map[string]string{"extraTime": payload.ExtraTime})
+	audit(r, auditMatchExtraTime, matchID, map[string]string{"extraTime": match.ExtraTime}, map[string]string{"extraTime": payload.ExtraTime})
+
 	// Devolver un mensaje de éxito como respuesta JSON
 	json.NewEncoder(w).Encode(map[string]string{"message": "Tiempo extra actualizado correctamente"})
 }
 
-
 // enableCORS configura los encabezados necesarios para permitir solicitudes desde otros orígenes (CORS)
 // Se aplica como middleware para todas las rutas.
 func enableCORS(next http.Handler) http.Handler {
 
-	// Configura los encabezados CORS para permitir solicitudes desde cualquier origen
+	// Configura los encabezados CORS para permitir solicitudes de los orígenes configurados
 	// y permite métodos y encabezados específicos
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 
-		// Configura los encabezados CORS
-		w.Header().Set("Access-Control-Allow-Origin", "*")
+		// Configura los encabezados CORS. Con una lista de orígenes se devuelve el
+		// origen de la solicitud solo si está permitido, y Vary para que los cachés
+		// no compartan la respuesta entre orígenes distintos.
+		origin := allowedOrigin(r.Header.Get("Origin"))
+		if origin != "" {
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+		}
+		if origin != "*" {
+			w.Header().Add("Vary", "Origin")
+		}
 		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS, PATCH")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-API-Key, X-Request-ID")
+		w.Header().Set("Access-Control-Expose-Headers", "Link, X-Total-Count, X-Request-ID, X-RateLimit-Limit, X-RateLimit-Remaining, X-RateLimit-Reset, Retry-After")
 
 		// Maneja las solicitudes preflight (OPTIONS) para permitir el intercambio de recursos entre orígenes
 		if r.Method == "OPTIONS" {
@@ -556,43 +760,239 @@ func enableCORS(next http.Handler) http.Handler {
 	})
 }
 
+// allowedOrigin devuelve el valor de Access-Control-Allow-Origin para el origen de una
+// solicitud: "*" si se permite cualquiera, el mismo origen si está en la lista, o vacío
+func allowedOrigin(origin string) string {
+	for _, o := range config.CORSOrigins {
+		if o == "*" {
+			return "*"
+		}
+		if origin != "" && strings.EqualFold(o, origin) {
+			return origin
+		}
+	}
+	return ""
+}
 
 // main inicializa la conexión a la base de datos, configura las rutas y arranca el servidor HTTP
+//
+// @securityDefinitions.apikey ApiKeyAuth
+// @in header
+// @name X-API-Key
+// @description Clave de API creada con el comando apikey create
+//
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+// @description Token JWT con el formato "Bearer <token>", firmado con el comando token
 func main() {
-	// Inicializa la conexión a la base de datos SQLite
+	// Subcomandos de línea de comandos
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "cleanup-orphans":
+			runCleanupOrphans(os.Args[2:])
+			return
+		case "migrate":
+			runMigrate(os.Args[2:])
+			return
+		case "apikey":
+			runAPIKey(os.Args[2:])
+			return
+		case "token":
+			runToken(os.Args[2:])
+			return
+		}
+	}
+
+	// Leer la configuración de los flags, el entorno y el archivo YAML
+	var printConfig bool
 	var err error
-	db, err = sql.Open("sqlite3", "./database/matches.db")
+	config, printConfig, err = loadConfig(os.Args[1:])
+	if err != nil {
+		log.Fatal(err)
+	}
+	if printConfig {
+		if err := config.print(os.Stdout); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
 
-	// Verifica si hubo un error al abrir la base de datos
-	// Si hubo un error, imprime el error y termina la ejecución del programa
-	// y cierra la conexión a la base de datos
+	// Los logs del servidor se escriben en el formato configurado y se filtran por nivel
+	level, _ := config.slogLevel()
+	slog.SetDefault(newLogger(os.Stderr, config.LogFormat, level))
+
+	// Abre el almacenamiento y aplica las migraciones pendientes para que el esquema
+	// esté al día antes de atender solicitudes. Si hubo un error, termina la ejecución.
+	store, err = openStore(config.Store, config.DSN)
 	if err != nil {
 		log.Fatal(err)
 	}
+	defer store.Close()
+	slog.Info("almacenamiento abierto", "store", config.Store)
 
 	// Verifica si la base de datos está accesible
 	r := mux.NewRouter()
 
+	// Middleware de observabilidad: X-Request-ID y log de cada solicitud y, si están activas,
+	// métricas de las solicitudes y de cada operación del almacenamiento
+	observability := []mux.MiddlewareFunc{logRequests}
+	if config.Features.Metrics {
+		store = instrumentStore(store)
+		observability = append(observability, instrumentHTTP)
+	}
+	r.Use(observability...)
+
+	// mux no aplica los middleware a las respuestas 404 y 405, así que se envuelven aparte
+	var notFound http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, r, http.StatusNotFound, codeRouteNotFound, "Ruta no encontrada")
+	})
+	var methodNotAllowed http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, r, http.StatusMethodNotAllowed, codeMethodNotAllowed, "Método no permitido")
+	})
+	for i := len(observability) - 1; i >= 0; i-- {
+		notFound = observability[i](notFound)
+		methodNotAllowed = observability[i](methodNotAllowed)
+	}
+	r.NotFoundHandler = notFound
+	r.MethodNotAllowedHandler = methodNotAllowed
+
 	// Agregar middleware CORS
 	r.Use(enableCORS)
 
+	// Sondas de vida y disponibilidad para el orquestador
+	r.HandleFunc("/healthz", getHealthz).Methods("GET")
+	r.HandleFunc("/readyz", getReadyz).Methods("GET")
+
+	if config.Features.Metrics {
+		// Métricas en formato de texto de Prometheus
+		r.Handle("/metrics", promhttp.Handler()).Methods("GET")
+	}
+
+	// Permisos de cada ruta: viewer consulta, scorer además registra goles, tarjetas, tiempo
+	// extra y cambios de estado, y admin además crea, corrige y elimina. Con auth.enabled en
+	// false no se exige nada; con auth.public_reads las rutas de viewer no piden credenciales.
+	if config.Auth.Enabled {
+		slog.Info("autenticación activada", "public_reads", config.Auth.PublicReads, "jwt", config.Auth.JWTSecret != "")
+	} else {
+		slog.Warn("autenticación desactivada: cualquiera con acceso al puerto puede modificar los datos")
+	}
+
+	// Límite de solicitudes por cliente, aplicado por require en cada ruta de /api y /ws.
+	// Las sondas, las métricas y las solicitudes OPTIONS de CORS no se limitan.
+	if config.RateLimit.Enabled {
+		readLimiter = newRateLimiter(rateClassRead, config.RateLimit.ReadRate, config.RateLimit.ReadBurst)
+		writeLimiter = newRateLimiter(rateClassWrite, config.RateLimit.WriteRate, config.RateLimit.WriteBurst)
+		go readLimiter.run(rateLimitEvictInterval)
+		go writeLimiter.run(rateLimitEvictInterval)
+		slog.Info("límite de solicitudes activado",
+			"read_rate", config.RateLimit.ReadRate, "read_burst", config.RateLimit.ReadBurst,
+			"write_rate", config.RateLimit.WriteRate, "write_burst", config.RateLimit.WriteBurst)
+	}
+
 	// Endpoints REST
-	r.HandleFunc("/api/matches", getMatches).Methods("GET")
-	r.HandleFunc("/api/matches/{id}", getMatch).Methods("GET")
-	r.HandleFunc("/api/matches", createMatch).Methods("POST")
-	r.HandleFunc("/api/matches/{id}", updateMatch).Methods("PUT")
-	r.HandleFunc("/api/matches/{id}", deleteMatch).Methods("DELETE")
+	r.HandleFunc("/api/matches", require(roleViewer, getMatches)).Methods("GET")
+	r.HandleFunc("/api/matches/{id}", require(roleViewer, getMatch)).Methods("GET")
+	r.HandleFunc("/api/matches", require(roleAdmin, createMatch)).Methods("POST")
+	r.HandleFunc("/api/matches/{id}", require(roleAdmin, updateMatch)).Methods("PUT")
+	r.HandleFunc("/api/matches/{id}", require(roleAdmin, deleteMatch)).Methods("DELETE")
 
 	// Enpoints PATCH para registrar goles, tarjetas amarillas y rojas
-	r.HandleFunc("/api/matches/{id}/goals", registerGoal).Methods("PATCH")
-	r.HandleFunc("/api/matches/{id}/yellow_cards", registerYellowCard).Methods("PATCH")
-	r.HandleFunc("/api/matches/{id}/red_cards", registerRedCard).Methods("PATCH")
+	r.HandleFunc("/api/matches/{id}/goals", require(roleScorer, registerGoal)).Methods("PATCH")
+	r.HandleFunc("/api/matches/{id}/yellow_cards", require(roleScorer, registerYellowCard)).Methods("PATCH")
+	r.HandleFunc("/api/matches/{id}/red_cards", require(roleScorer, registerRedCard)).Methods("PATCH")
+
+	// Endpoints para consultar, corregir y eliminar goles y tarjetas ya registrados
+	r.HandleFunc("/api/matches/{id}/goals/{eventId}", require(roleViewer, getGoal)).Methods("GET")
+	r.HandleFunc("/api/matches/{id}/goals/{eventId}", require(roleAdmin, updateGoal)).Methods("PUT")
+	r.HandleFunc("/api/matches/{id}/goals/{eventId}", require(roleAdmin, deleteGoal)).Methods("DELETE")
+	r.HandleFunc("/api/matches/{id}/yellow_cards/{eventId}", require(roleViewer, getYellowCard)).Methods("GET")
+	r.HandleFunc("/api/matches/{id}/yellow_cards/{eventId}", require(roleAdmin, updateYellowCard)).Methods("PUT")
+	r.HandleFunc("/api/matches/{id}/yellow_cards/{eventId}", require(roleAdmin, deleteYellowCard)).Methods("DELETE")
+	r.HandleFunc("/api/matches/{id}/red_cards/{eventId}", require(roleViewer, getRedCard)).Methods("GET")
+	r.HandleFunc("/api/matches/{id}/red_cards/{eventId}", require(roleAdmin, updateRedCard)).Methods("PUT")
+	r.HandleFunc("/api/matches/{id}/red_cards/{eventId}", require(roleAdmin, deleteRedCard)).Methods("DELETE")
 
 	// Endpoint para establecer tiempo extra
-	r.HandleFunc("/api/matches/{id}/extratime", setExtraTime).Methods("PATCH")
+	r.HandleFunc("/api/matches/{id}/extratime", require(roleScorer, setExtraTime)).Methods("PATCH")
+
+	// Endpoint para la cronología del partido
+	r.HandleFunc("/api/matches/{id}/timeline", require(roleViewer, getMatchTimeline)).Methods("GET")
+
+	// Endpoint para cambiar el estado del partido
+	r.HandleFunc("/api/matches/{id}/status", require(roleScorer, setMatchStatus)).Methods("PATCH")
+
+	if config.Features.Live {
+		// Endpoints de Server-Sent Events para seguir los partidos en vivo
+		r.HandleFunc("/api/matches/{id}/stream", require(roleViewer, streamMatch)).Methods("GET")
+		r.HandleFunc("/api/stream", require(roleViewer, streamLeague)).Methods("GET")
 
-	// Endpoint para la documentación Swagger
-	r.PathPrefix("/swagger/").Handler(httpSwagger.WrapHandler)
+		// Endpoint WebSocket para el feed en vivo con suscripción por partido
+		r.HandleFunc("/ws", require(roleViewer, serveWS)).Methods("GET")
+		go hub.run(wsSnapshotInterval)
+	}
+
+	// Endpoint para la tabla de clasificación
+	r.HandleFunc("/api/standings", require(roleViewer, getStandings)).Methods("GET")
+
+	// Endpoints de estadísticas (goleadores y disciplina)
+	r.HandleFunc("/api/stats/scorers", require(roleViewer, getTopScorers)).Methods("GET")
+	r.HandleFunc("/api/stats/discipline", require(roleViewer, getDiscipline)).Methods("GET")
+
+	// Endpoints REST para equipos
+	r.HandleFunc("/api/teams", require(roleViewer, getTeams)).Methods("GET")
+	r.HandleFunc("/api/teams/{id}", require(roleViewer, getTeam)).Methods("GET")
+	r.HandleFunc("/api/teams", require(roleAdmin, createTeam)).Methods("POST")
+	r.HandleFunc("/api/teams/{id}", require(roleAdmin, updateTeam)).Methods("PUT")
+	r.HandleFunc("/api/teams/{id}", require(roleAdmin, deleteTeam)).Methods("DELETE")
+
+	// Endpoints REST para la plantilla de cada equipo
+	r.HandleFunc("/api/teams/{id}/players", require(roleViewer, getPlayers)).Methods("GET")
+	r.HandleFunc("/api/teams/{id}/players", require(roleAdmin, createPlayer)).Methods("POST")
+	r.HandleFunc("/api/teams/{id}/players/{playerId}", require(roleViewer, getPlayer)).Methods("GET")
+	r.HandleFunc("/api/teams/{id}/players/{playerId}", require(roleAdmin, updatePlayer)).Methods("PUT")
+	r.HandleFunc("/api/teams/{id}/players/{playerId}", require(roleAdmin, deletePlayer)).Methods("DELETE")
+
+	// Endpoints REST para competiciones y temporadas
+	r.HandleFunc("/api/competitions", require(roleViewer, getCompetitions)).Methods("GET")
+	r.HandleFunc("/api/competitions/{id}", require(roleViewer, getCompetition)).Methods("GET")
+	r.HandleFunc("/api/competitions", require(roleAdmin, createCompetition)).Methods("POST")
+	r.HandleFunc("/api/competitions/{id}", require(roleAdmin, updateCompetition)).Methods("PUT")
+	r.HandleFunc("/api/competitions/{id}", require(roleAdmin, deleteCompetition)).Methods("DELETE")
+	r.HandleFunc("/api/competitions/{id}/seasons", require(roleViewer, getSeasons)).Methods("GET")
+	r.HandleFunc("/api/competitions/{id}/seasons", require(roleAdmin, createSeason)).Methods("POST")
+	r.HandleFunc("/api/seasons/{id}", require(roleViewer, getSeason)).Methods("GET")
+	r.HandleFunc("/api/seasons/{id}", require(roleAdmin, updateSeason)).Methods("PUT")
+	r.HandleFunc("/api/seasons/{id}", require(roleAdmin, deleteSeason)).Methods("DELETE")
+
+	// Partidos, equipos, clasificación y estadísticas de una temporada. Son los mismos
+	// endpoints que aceptan seasonId, con la temporada en la URL.
+	r.HandleFunc("/api/seasons/{id}/matches", require(roleViewer, getSeasonMatches)).Methods("GET")
+	r.HandleFunc("/api/seasons/{id}/teams", require(roleViewer, getSeasonTeams)).Methods("GET")
+	r.HandleFunc("/api/seasons/{id}/standings", require(roleViewer, getSeasonStandings)).Methods("GET")
+	r.HandleFunc("/api/seasons/{id}/stats/scorers", require(roleViewer, getSeasonTopScorers)).Methods("GET")
+	r.HandleFunc("/api/seasons/{id}/stats/discipline", require(roleViewer, getSeasonDiscipline)).Methods("GET")
+
+	// Generador del calendario todos contra todos de una temporada
+	r.HandleFunc("/api/seasons/{id}/fixtures/generate", require(roleAdmin, generateFixtures)).Methods("POST")
+
+	// Endpoint para el historial de auditoría de partidos y eventos
+	r.HandleFunc("/api/audit", require(roleAdmin, getAudit)).Methods("GET")
+
+	if config.Features.Webhooks {
+		// Endpoints para registrar webhooks y consultar sus entregas
+		r.HandleFunc("/api/webhooks", require(roleAdmin, getWebhooks)).Methods("GET")
+		r.HandleFunc("/api/webhooks", require(roleAdmin, createWebhook)).Methods("POST")
+		r.HandleFunc("/api/webhooks/{id}", require(roleAdmin, getWebhook)).Methods("GET")
+		r.HandleFunc("/api/webhooks/{id}", require(roleAdmin, deleteWebhook)).Methods("DELETE")
+		r.HandleFunc("/api/webhooks/{id}/deliveries", require(roleAdmin, getWebhookDeliveries)).Methods("GET")
+		dispatcher.start()
+	}
+
+	if config.Features.Swagger {
+		// Endpoint para la documentación Swagger
+		r.PathPrefix("/swagger/").Handler(httpSwagger.WrapHandler)
+	}
 
 	// Manejar solicitudes preflight (OPTIONS)
 	r.HandleFunc("/api/matches", func(w http.ResponseWriter, r *http.Request) {
@@ -618,13 +1018,123 @@ func main() {
 		w.WriteHeader(http.StatusOK)
 	}).Methods("OPTIONS")
 
+	// Manejar solicitudes preflight (OPTIONS) para corregir y eliminar eventos
+	r.HandleFunc("/api/matches/{id}/goals/{eventId}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}).Methods("OPTIONS")
+
+	r.HandleFunc("/api/matches/{id}/yellow_cards/{eventId}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}).Methods("OPTIONS")
+
+	r.HandleFunc("/api/matches/{id}/red_cards/{eventId}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}).Methods("OPTIONS")
+
 	// Manejar solicitudes preflight (OPTIONS) para establecer tiempo extra
 	r.HandleFunc("/api/matches/{id}/extratime", func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
 	}).Methods("OPTIONS")
 
-	// Iniciar el servidor HTTP en el puerto 8080
+	// Manejar solicitudes preflight (OPTIONS) para la cronología del partido
+	r.HandleFunc("/api/matches/{id}/timeline", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}).Methods("OPTIONS")
+
+	// Manejar solicitudes preflight (OPTIONS) para cambiar el estado del partido
+	r.HandleFunc("/api/matches/{id}/status", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}).Methods("OPTIONS")
+
+	// Manejar solicitudes preflight (OPTIONS) para la tabla de clasificación
+	r.HandleFunc("/api/standings", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}).Methods("OPTIONS")
+
+	// Manejar solicitudes preflight (OPTIONS) para las estadísticas
+	r.HandleFunc("/api/stats/scorers", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}).Methods("OPTIONS")
+
+	r.HandleFunc("/api/stats/discipline", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}).Methods("OPTIONS")
+
+	// Manejar solicitudes preflight (OPTIONS) para equipos
+	r.HandleFunc("/api/teams", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}).Methods("OPTIONS")
+
+	r.HandleFunc("/api/teams/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}).Methods("OPTIONS")
+
+	// Manejar solicitudes preflight (OPTIONS) para la plantilla de cada equipo
+	r.HandleFunc("/api/teams/{id}/players", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}).Methods("OPTIONS")
+
+	r.HandleFunc("/api/teams/{id}/players/{playerId}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}).Methods("OPTIONS")
+
+	// Manejar solicitudes preflight (OPTIONS) para competiciones y temporadas
+	r.HandleFunc("/api/competitions", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}).Methods("OPTIONS")
+
+	r.HandleFunc("/api/competitions/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}).Methods("OPTIONS")
+
+	r.HandleFunc("/api/competitions/{id}/seasons", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}).Methods("OPTIONS")
+
+	r.HandleFunc("/api/seasons/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}).Methods("OPTIONS")
+
+	r.HandleFunc("/api/seasons/{id}/{resource:matches|teams|standings|stats/scorers|stats/discipline}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}).Methods("OPTIONS")
+
+	r.HandleFunc("/api/seasons/{id}/fixtures/generate", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}).Methods("OPTIONS")
+
+	// Manejar solicitudes preflight (OPTIONS) para el historial de auditoría
+	r.HandleFunc("/api/audit", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}).Methods("OPTIONS")
+
+	// Manejar solicitudes preflight (OPTIONS) para webhooks
+	r.HandleFunc("/api/webhooks", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}).Methods("OPTIONS")
+
+	r.HandleFunc("/api/webhooks/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}).Methods("OPTIONS")
+
+	r.HandleFunc("/api/webhooks/{id}/deliveries", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}).Methods("OPTIONS")
+
+	// Iniciar el servidor HTTP en la dirección configurada
 	// y manejar las solicitudes con el enrutador configurado
-	log.Println("Servidor escuchando en el puerto 8080")
-	http.ListenAndServe(":8080", r)
+	srv := &http.Server{
+		Addr:              config.Addr,
+		Handler:           r,
+		ReadHeaderTimeout: config.ReadHeaderTimeout,
+		ReadTimeout:       config.ReadTimeout,
+		WriteTimeout:      config.WriteTimeout,
+		IdleTimeout:       config.IdleTimeout,
+	}
+	slog.Info("servidor escuchando", "addr", config.Addr)
+
+	// Atender solicitudes hasta recibir SIGINT o SIGTERM y apagar de forma ordenada
+	if err := runServer(srv); err != nil {
+		log.Fatal(err)
+	}
 }
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+)
+
+// Ayudas para probar los handlers con httptest y el almacenamiento en memoria
+
+// useMemoryStore deja un almacenamiento en memoria vacío como almacenamiento de los handlers
+// y restaura el anterior al terminar la prueba
+func useMemoryStore(t *testing.T) *memStore {
+	t.Helper()
+	prev := store
+	s := newMemoryStore()
+	store = s
+	t.Cleanup(func() { store = prev })
+	return s
+}
+
+// serveRoute atiende la solicitud con un router que solo tiene el handler en la ruta indicada,
+// de modo que el handler reciba las variables y la plantilla de la ruta como en el servidor
+func serveRoute(route string, handler http.HandlerFunc, req *http.Request) *httptest.ResponseRecorder {
+	r := mux.NewRouter()
+	r.HandleFunc(route, handler).Methods(req.Method)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	return rec
+}
+
+// decodeResponse verifica el código de estado de la respuesta y decodifica su cuerpo JSON en v
+func decodeResponse(t *testing.T, rec *httptest.ResponseRecorder, status int, v any) {
+	t.Helper()
+	if rec.Code != status {
+		t.Fatalf("código %d, se esperaba %d: %s", rec.Code, status, rec.Body.String())
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), v); err != nil {
+		t.Fatalf("respuesta no es JSON válido: %v: %s", err, rec.Body.String())
+	}
+}
+
+// decodeProblem verifica que la respuesta sea un problem+json con el estado y código indicados
+func decodeProblem(t *testing.T, rec *httptest.ResponseRecorder, status int, code string) Problem {
+	t.Helper()
+	var p Problem
+	decodeResponse(t, rec, status, &p)
+	if ct := rec.Header().Get("Content-Type"); ct != "application/problem+json" {
+		t.Errorf("Content-Type = %q, se esperaba application/problem+json", ct)
+	}
+	if p.Status != status || p.Code != code {
+		t.Errorf("problema %d %s, se esperaba %d %s", p.Status, p.Code, status, code)
+	}
+	return p
+}
+
+func TestUpdateMatchSeason(t *testing.T) {
+	s := useMemoryStore(t)
+	teams := createTestTeams(t, s, "Athletic", "Betis")
+	compID := mustCreate(t)(s.CreateCompetition(Competition{Name: "La Liga", Country: "España", Type: "league"}))
+	first := mustCreate(t)(s.CreateSeason(Season{CompetitionID: compID, Name: "2024/25", StartDate: "2024-07-01", EndDate: "2025-06-30"}))
+	second := mustCreate(t)(s.CreateSeason(Season{CompetitionID: compID, Name: "2025/26", StartDate: "2025-07-01", EndDate: "2026-06-30"}))
+	matchID := createTestMatch(t, s, teams[0], teams[1], "2025-01-10", first)
+	target := "/api/matches/" + strconv.Itoa(matchID)
+	match := `{"homeTeamId":` + strconv.Itoa(teams[0]) + `,"awayTeamId":` + strconv.Itoa(teams[1])
+
+	tests := []struct {
+		name   string
+		body   string
+		status int
+		code   string // Código del problema si no es 200
+		field  string // Campo inválido si es un problema de validación
+		season int    // Temporada del partido después de la solicitud
+	}{
+		{"sin seasonId se mantiene", match + `,"matchDate":"2025-02-01"}`, http.StatusOK, "", "", first},
+		{"sin seasonId la fecha tiene que caer en la temporada", match + `,"matchDate":"2025-09-01"}`, http.StatusBadRequest, codeValidationFailed, "matchDate", first},
+		{"un ID lo cambia de temporada", match + `,"matchDate":"2025-09-01","seasonId":` + strconv.Itoa(second) + `}`, http.StatusOK, "", "", second},
+		{"null lo quita de la temporada", match + `,"matchDate":"2025-09-01","seasonId":null}`, http.StatusOK, "", "", 0},
+		{"temporada inexistente", match + `,"matchDate":"2025-09-01","seasonId":99}`, http.StatusBadRequest, codeValidationFailed, "seasonId", 0},
+		{"seasonId que no es un número", match + `,"matchDate":"2025-09-01","seasonId":"2025/26"}`, http.StatusBadRequest, codeInvalidJSON, "", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPut, target, strings.NewReader(tt.body))
+			rec := serveRoute("/api/matches/{id}", updateMatch, req)
+			if tt.status == http.StatusOK {
+				var m Match
+				decodeResponse(t, rec, http.StatusOK, &m)
+			} else if p := decodeProblem(t, rec, tt.status, tt.code); tt.field != "" && (len(p.Errors) != 1 || p.Errors[0].Field != tt.field) {
+				t.Errorf("errores = %+v, se esperaba el campo %s", p.Errors, tt.field)
+			}
+			if m, _ := s.Match(matchID); m.SeasonID != tt.season {
+				t.Errorf("temporada %d, se esperaba %d", m.SeasonID, tt.season)
+			}
+		})
+	}
+}
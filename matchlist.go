@@ -0,0 +1,198 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Límites para la paginación del listado de partidos.
+// El desplazamiento de la página tiene que caber en un entero de 32 bits para las bases de datos.
+const (
+	defaultMatchesLimit = 100
+	maxMatchesLimit     = 500
+	maxMatchesOffset    = math.MaxInt32
+)
+
+// matchSortColumns mapea los valores del parámetro sort a la columna de ordenamiento.
+// Un guion al inicio (por ejemplo -date) invierte el orden.
+var matchSortColumns = map[string]string{
+	"id":   "m.id",
+	"date": "m.match_date",
+}
+
+// seasonPattern acepta temporadas como 2024-25, 2024/25, 2024-2025 o solo el año de inicio
+var seasonPattern = regexp.MustCompile(`^(\d{4})(?:[-/](\d{2}|\d{4}))?$`)
+
+// matchListQuery contiene los filtros, el orden y la página pedidos para el listado de partidos
+type matchListQuery struct {
+	matchScope
+	TeamID   int
+	From     string
+	To       string
+	Statuses []string
+	Sort     string
+	Page     int
+	Limit    int
+}
+
+// parseMatchListQuery lee los parámetros page, limit, team, from, to, status, season y sort,
+// y la competición y temporada de parseMatchScope. Devuelve el detalle de los parámetros
+// que no son válidos.
+func parseMatchListQuery(r *http.Request) (matchListQuery, []FieldError, error) {
+	q := r.URL.Query()
+	lq := matchListQuery{From: q.Get("from"), To: q.Get("to"), Sort: "id", Page: 1, Limit: defaultMatchesLimit}
+
+	sc, errs, err := parseMatchScope(r)
+	if err != nil {
+		return lq, nil, err
+	}
+	lq.matchScope = sc
+
+	if page := q.Get("page"); page != "" {
+		n, err := strconv.Atoi(page)
+		if err != nil || n < 1 {
+			errs = append(errs, FieldError{"page", fieldOutOfRange, "La página debe ser un número mayor a 0"})
+		} else {
+			lq.Page = n
+		}
+	}
+
+	if limit := q.Get("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 1 || n > maxMatchesLimit {
+			errs = append(errs, FieldError{"limit", fieldOutOfRange, "El límite debe estar entre 1 y " + strconv.Itoa(maxMatchesLimit)})
+		} else {
+			lq.Limit = n
+		}
+	}
+
+	// Comparar sin multiplicar para que una página enorme no desborde el desplazamiento
+	if lq.Page-1 > maxMatchesOffset/lq.Limit {
+		errs = append(errs, FieldError{"page", fieldOutOfRange, "La página es demasiado grande para el límite indicado"})
+	}
+
+	// Las fechas deben venir en el mismo formato que match_date
+	for _, d := range []struct{ field, value string }{{"from", lq.From}, {"to", lq.To}} {
+		if d.value == "" {
+			continue
+		}
+		if _, err := time.Parse("2006-01-02", d.value); err != nil {
+			errs = append(errs, FieldError{d.field, fieldInvalidFormat, "Formato de fecha inválido. Usa YYYY-MM-DD"})
+		}
+	}
+
+	// Una temporada va del 1 de julio al 30 de junio del año siguiente.
+	// Se combina con from y to si también se indican. Es un rango de fechas sin relación
+	// con las temporadas de las competiciones, que se eligen con seasonId.
+	if season := q.Get("season"); season != "" {
+		parts := seasonPattern.FindStringSubmatch(season)
+		var start int
+		if parts != nil {
+			start, _ = strconv.Atoi(parts[1])
+		}
+		if parts == nil {
+			errs = append(errs, FieldError{"season", fieldInvalidFormat, "Formato de temporada inválido. Usa YYYY-YY (por ejemplo 2024-25)"})
+		} else if end := parts[2]; end != "" && !strings.HasSuffix(strconv.Itoa(start+1), end) {
+			errs = append(errs, FieldError{"season", fieldInvalid, "La temporada debe abarcar dos años consecutivos"})
+		} else {
+			seasonFrom := fmt.Sprintf("%d-07-01", start)
+			seasonTo := fmt.Sprintf("%d-06-30", start+1)
+			if lq.From == "" || lq.From < seasonFrom {
+				lq.From = seasonFrom
+			}
+			if lq.To == "" || lq.To > seasonTo {
+				lq.To = seasonTo
+			}
+		}
+	}
+
+	// El equipo se puede indicar por ID o por nombre, y puede jugar de local o de visitante
+	if team := q.Get("team"); team != "" {
+		id, _ := strconv.Atoi(team)
+		teamID, _, err := resolveTeam(id, team)
+		if err == errTeamNotFound {
+			errs = append(errs, FieldError{"team", fieldNotFound, "Equipo no encontrado"})
+		} else if err != nil {
+			return lq, nil, err
+		}
+		lq.TeamID = teamID
+	}
+
+	// Se pueden pedir varios estados separados por comas
+	if status := q.Get("status"); status != "" {
+		for _, s := range strings.Split(status, ",") {
+			if _, ok := allowedTransitions[s]; !ok {
+				errs = append(errs, FieldError{"status", fieldInvalid, "Estado inválido: " + s})
+				continue
+			}
+			lq.Statuses = append(lq.Statuses, s)
+		}
+	}
+
+	if sort := q.Get("sort"); sort != "" {
+		if _, ok := matchSortColumns[strings.TrimPrefix(sort, "-")]; !ok {
+			errs = append(errs, FieldError{"sort", fieldInvalid, "Orden inválido. Usa id, -id, date o -date"})
+		}
+		lq.Sort = sort
+	}
+
+	return lq, errs, nil
+}
+
+// orderBy devuelve la expresión ORDER BY del listado. El ID desempata los partidos del mismo día.
+func (lq matchListQuery) orderBy() string {
+	dir := "ASC"
+	if strings.HasPrefix(lq.Sort, "-") {
+		dir = "DESC"
+	}
+	column := matchSortColumns[strings.TrimPrefix(lq.Sort, "-")]
+	if column == "m.id" {
+		return "m.id " + dir
+	}
+	return column + " " + dir + ", m.id " + dir
+}
+
+// offset devuelve la cantidad de partidos que se saltan para llegar a la página pedida
+func (lq matchListQuery) offset() int {
+	return (lq.Page - 1) * lq.Limit
+}
+
+// lastPage devuelve el número de la última página para un total de partidos
+func (lq matchListQuery) lastPage(total int) int {
+	if total == 0 {
+		return 1
+	}
+	return (total + lq.Limit - 1) / lq.Limit
+}
+
+// setPaginationHeaders agrega el total de partidos y los enlaces first, prev, next y last
+// en el encabezado Link, conservando el resto de los parámetros de la URL
+func setPaginationHeaders(w http.ResponseWriter, r *http.Request, lq matchListQuery, total int) {
+	last := lq.lastPage(total)
+
+	link := func(page int, rel string) string {
+		q := r.URL.Query()
+		q.Set("page", strconv.Itoa(page))
+		q.Set("limit", strconv.Itoa(lq.Limit))
+		u := url.URL{Path: r.URL.Path, RawQuery: q.Encode()}
+		return fmt.Sprintf(`<%s>; rel="%s"`, u.String(), rel)
+	}
+
+	links := []string{link(1, "first")}
+	if lq.Page > 1 {
+		links = append(links, link(min(lq.Page-1, last), "prev"))
+	}
+	if lq.Page < last {
+		links = append(links, link(lq.Page+1, "next"))
+	}
+	links = append(links, link(last, "last"))
+
+	w.Header().Set("X-Total-Count", strconv.Itoa(total))
+	w.Header().Set("Link", strings.Join(links, ", "))
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestParseMatchListQuery(t *testing.T) {
+	s := useMemoryStore(t)
+	teams := createTestTeams(t, s, "Athletic")
+
+	// Primera página que ya no cabe en el desplazamiento con el límite máximo
+	overflowPage := strconv.Itoa(maxMatchesOffset/maxMatchesLimit + 2)
+
+	tests := []struct {
+		query  string
+		want   matchListQuery
+		fields []string // Campos inválidos esperados; si hay, want no se compara
+	}{
+		{"", matchListQuery{Sort: "id", Page: 1, Limit: defaultMatchesLimit}, nil},
+		{"page=3&limit=20&sort=-date", matchListQuery{Sort: "-date", Page: 3, Limit: 20}, nil},
+		{"season=2024-25&from=2024-09-01", matchListQuery{From: "2024-09-01", To: "2025-06-30", Sort: "id", Page: 1, Limit: defaultMatchesLimit}, nil},
+		{"status=live,half_time&team=athletic", matchListQuery{TeamID: teams[0], Statuses: []string{StatusLive, StatusHalfTime}, Sort: "id", Page: 1, Limit: defaultMatchesLimit}, nil},
+		{"page=0", matchListQuery{}, []string{"page"}},
+		{"page=dos&limit=501", matchListQuery{}, []string{"page", "limit"}},
+		{"page=" + overflowPage + "&limit=" + strconv.Itoa(maxMatchesLimit), matchListQuery{}, []string{"page"}},
+		{"page=99999999999999999999", matchListQuery{}, []string{"page"}},
+		{"from=01/02/2025&season=2024-26", matchListQuery{}, []string{"from", "season"}},
+		{"status=live,abandoned&sort=name&team=Zaragoza", matchListQuery{}, []string{"team", "status", "sort"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			lq, errs, err := parseMatchListQuery(httptest.NewRequest(http.MethodGet, "/api/matches?"+tt.query, nil))
+			if err != nil {
+				t.Fatal(err)
+			}
+			fields := []string{}
+			for _, e := range errs {
+				fields = append(fields, e.Field)
+			}
+			if tt.fields != nil {
+				if !reflect.DeepEqual(fields, tt.fields) {
+					t.Errorf("campos inválidos %v, se esperaban %v", fields, tt.fields)
+				}
+				return
+			}
+			if len(errs) != 0 || !reflect.DeepEqual(lq, tt.want) {
+				t.Errorf("parseMatchListQuery = %+v %v, se esperaba %+v", lq, errs, tt.want)
+			}
+		})
+	}
+
+	// La última página que cabe sigue siendo válida
+	lastPage := strconv.Itoa(maxMatchesOffset/maxMatchesLimit + 1)
+	if _, errs, _ := parseMatchListQuery(httptest.NewRequest(http.MethodGet, "/api/matches?limit=500&page="+lastPage, nil)); errs != nil {
+		t.Errorf("página %s: %v", lastPage, errs)
+	}
+}
+
+func TestGetMatchesPagination(t *testing.T) {
+	s := useMemoryStore(t)
+	teams := createTestTeams(t, s, "Athletic", "Betis")
+	for i := 1; i <= 5; i++ {
+		createTestMatch(t, s, teams[0], teams[1], "2025-01-0"+strconv.Itoa(i), 0)
+	}
+
+	link := func(page int, rel string) string {
+		return `</api/matches?limit=2&page=` + strconv.Itoa(page) + `&status=scheduled>; rel="` + rel + `"`
+	}
+	tests := []struct {
+		page  int
+		ids   []int
+		links []string
+	}{
+		{1, []int{1, 2}, []string{link(1, "first"), link(2, "next"), link(3, "last")}},
+		{2, []int{3, 4}, []string{link(1, "first"), link(1, "prev"), link(3, "next"), link(3, "last")}},
+		{3, []int{5}, []string{link(1, "first"), link(2, "prev"), link(3, "last")}},
+		// Más allá del final la página está vacía y prev apunta a la última
+		{7, []int{}, []string{link(1, "first"), link(3, "prev"), link(3, "last")}},
+	}
+	for _, tt := range tests {
+		t.Run("página "+strconv.Itoa(tt.page), func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/matches?status=scheduled&limit=2&page="+strconv.Itoa(tt.page), nil)
+			rec := serveRoute("/api/matches", getMatches, req)
+			var matches []FullMatchData
+			decodeResponse(t, rec, http.StatusOK, &matches)
+
+			ids := []int{}
+			for _, m := range matches {
+				ids = append(ids, m.ID)
+			}
+			if !reflect.DeepEqual(ids, tt.ids) {
+				t.Errorf("partidos %v, se esperaban %v", ids, tt.ids)
+			}
+			if got := rec.Header().Get("X-Total-Count"); got != "5" {
+				t.Errorf("X-Total-Count = %s, se esperaba 5", got)
+			}
+			if got, want := rec.Header().Get("Link"), strings.Join(tt.links, ", "); got != want {
+				t.Errorf("Link =\n%s\nse esperaba\n%s", got, want)
+			}
+		})
+	}
+
+	// Sin resultados hay una sola página
+	rec := serveRoute("/api/matches", getMatches, httptest.NewRequest(http.MethodGet, "/api/matches?status=live&limit=2", nil))
+	if got := rec.Header().Get("X-Total-Count"); got != "0" {
+		t.Errorf("sin resultados: X-Total-Count = %s, se esperaba 0", got)
+	}
+	if got, want := rec.Header().Get("Link"), `</api/matches?limit=2&page=1&status=live>; rel="first", </api/matches?limit=2&page=1&status=live>; rel="last"`; got != want {
+		t.Errorf("sin resultados: Link =\n%s\nse esperaba\n%s", got, want)
+	}
+
+	// Un parámetro inválido es un problema de validación y no devuelve encabezados de paginación
+	rec = serveRoute("/api/matches", getMatches, httptest.NewRequest(http.MethodGet, "/api/matches?page=0", nil))
+	decodeProblem(t, rec, http.StatusBadRequest, codeValidationFailed)
+	if rec.Header().Get("Link") != "" {
+		t.Error("una solicitud inválida devolvió el encabezado Link")
+	}
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Métricas HTTP. Las rutas se identifican por la plantilla de mux (/api/matches/{id})
+// y no por la URL, para que cada partido o equipo no cree una serie nueva.
+var (
+	httpRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "laliga_http_requests_total",
+		Help: "Solicitudes HTTP atendidas por método, ruta y código de estado.",
+	}, []string{"method", "route", "status"})
+
+	// Los streams SSE y WebSocket se miden al cerrarse, así que su duración es la de la conexión
+	httpDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "laliga_http_request_duration_seconds",
+		Help:    "Duración de las solicitudes HTTP por método y ruta.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "route"})
+
+	httpInFlight = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "laliga_http_requests_in_flight",
+		Help: "Solicitudes HTTP en curso, incluidos los streams abiertos.",
+	})
+)
+
+// Métricas del almacenamiento, por operación del Store (Matches, CreateEvent, ...)
+var (
+	dbDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "laliga_db_operation_duration_seconds",
+		Help:    "Duración de las operaciones del almacenamiento.",
+		Buckets: []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1},
+	}, []string{"operation"})
+
+	dbErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "laliga_db_operation_errors_total",
+		Help: "Operaciones del almacenamiento que fallaron (sin contar registros no encontrados ni conflictos).",
+	}, []string{"operation"})
+)
+
+// Métricas del dominio
+var (
+	eventsRegistered = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "laliga_events_registered_total",
+		Help: "Goles y tarjetas registrados por tipo: goal, yellow_card o red_card.",
+	}, []string{"type"})
+
+	matchesCreated = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "laliga_matches_created_total",
+		Help: "Partidos creados.",
+	})
+
+	statusChanges = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "laliga_match_status_changes_total",
+		Help: "Cambios de estado de los partidos por estado de destino.",
+	}, []string{"status"})
+
+	webhookDeliveries = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "laliga_webhook_deliveries_total",
+		Help: "Intentos de entrega de webhooks por resultado: success o failure.",
+	}, []string{"result"})
+
+	liveConnections = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "laliga_live_connections",
+		Help: "Conexiones en vivo abiertas por transporte: sse o ws.",
+	}, []string{"transport"})
+)
+
+// Métricas del límite de solicitudes, por clase: read o write
+var (
+	rateLimited = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "laliga_rate_limited_total",
+		Help: "Solicitudes rechazadas con 429 por el límite de solicitudes, por clase: read o write.",
+	}, []string{"class"})
+
+	rateLimitClients = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "laliga_rate_limit_clients",
+		Help: "Clientes con el balde de tokens incompleto en la última limpieza, por clase: read o write.",
+	}, []string{"class"})
+)
+
+// instrumentHTTP cuenta y mide las solicitudes. Se registra con r.Use para que mux
+// ya haya elegido la ruta, y envuelve también los handlers de 404 y 405.
+func instrumentHTTP(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		route := routeTemplate(r)
+
+		httpInFlight.Inc()
+		defer httpInFlight.Dec()
+
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w}
+		next.ServeHTTP(rec, r)
+
+		httpRequests.WithLabelValues(r.Method, route, strconv.Itoa(rec.code())).Inc()
+		httpDuration.WithLabelValues(r.Method, route).Observe(time.Since(start).Seconds())
+	})
+}
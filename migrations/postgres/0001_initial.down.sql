@@ -0,0 +1,9 @@
+-- Elimina el esquema completo
+DROP TABLE webhook_deliveries;
+DROP TABLE webhooks;
+DROP TABLE red_cards;
+DROP TABLE yellow_cards;
+DROP TABLE goals;
+DROP TABLE players;
+DROP TABLE matches;
+DROP TABLE teams;
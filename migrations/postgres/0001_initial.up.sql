@@ -0,0 +1,98 @@
+-- Esquema completo para PostgreSQL. Equivale a las migraciones 0001 a 0007 de SQLite:
+-- una base de datos nueva no necesita repetir la historia de cambios de SQLite.
+-- Las fechas se guardan como texto con los mismos formatos que en SQLite
+-- (YYYY-MM-DD y YYYY-MM-DD HH:MM:SS en UTC) para que la API devuelva los mismos valores.
+
+-- Equipos
+CREATE TABLE teams (
+  id SERIAL PRIMARY KEY,                              -- ID del equipo
+  name TEXT NOT NULL,                                 -- Nombre oficial del equipo
+  short_name TEXT NOT NULL DEFAULT '',                -- Abreviatura (RMA, FCB, ...)
+  city TEXT NOT NULL DEFAULT '',                      -- Ciudad del equipo
+  stadium TEXT NOT NULL DEFAULT '',                   -- Estadio donde juega de local
+  founded_year INTEGER NOT NULL DEFAULT 0             -- Año de fundación (0 si se desconoce)
+);
+-- El nombre es único sin distinguir mayúsculas, igual que COLLATE NOCASE en SQLite
+CREATE UNIQUE INDEX idx_teams_name ON teams (LOWER(name));
+
+-- Partidos
+CREATE TABLE matches (
+  id SERIAL PRIMARY KEY,                              -- ID del partido
+  home_team_id INTEGER NOT NULL REFERENCES teams(id), -- Referencia al equipo local
+  away_team_id INTEGER NOT NULL REFERENCES teams(id), -- Referencia al equipo visitante
+  match_date TEXT NOT NULL,                           -- Fecha del partido (YYYY-MM-DD)
+  extra_time TEXT NOT NULL DEFAULT '00:00',           -- Tiempo extra en formato MM:SS
+  status TEXT NOT NULL DEFAULT 'scheduled',           -- Estado (scheduled, live, half_time, finished, postponed, cancelled)
+  status_updated_at TEXT NOT NULL DEFAULT (to_char(now() AT TIME ZONE 'UTC', 'YYYY-MM-DD HH24:MI:SS')) -- Fecha del último cambio de estado (UTC)
+);
+
+-- Plantillas
+CREATE TABLE players (
+  id SERIAL PRIMARY KEY,                              -- ID del jugador
+  team_id INTEGER NOT NULL REFERENCES teams(id),      -- Equipo en cuya plantilla está inscrito
+  name TEXT NOT NULL,                                 -- Nombre del jugador
+  shirt_number INTEGER NOT NULL DEFAULT 0,            -- Dorsal (0 si no tiene asignado)
+  position TEXT NOT NULL DEFAULT '',                  -- Posición (Portero, Defensa, ...)
+  nationality TEXT NOT NULL DEFAULT ''                -- Nacionalidad
+);
+
+-- Goles
+CREATE TABLE goals (
+  id SERIAL PRIMARY KEY,                              -- ID del gol
+  match_id INTEGER NOT NULL REFERENCES matches(id) ON DELETE CASCADE, -- Se elimina junto con el partido
+  team_id INTEGER NOT NULL REFERENCES teams(id),      -- Referencia al equipo que anotó
+  player_id INTEGER REFERENCES players(id) ON DELETE SET NULL, -- Jugador registrado (NULL si es texto libre)
+  player TEXT NOT NULL,                               -- Jugador que anotó
+  minute TEXT NOT NULL                                -- Minuto del gol (MM:SS)
+);
+CREATE INDEX idx_goals_match ON goals(match_id);
+
+-- Tarjetas amarillas
+CREATE TABLE yellow_cards (
+  id SERIAL PRIMARY KEY,                              -- ID de la tarjeta amarilla
+  match_id INTEGER NOT NULL REFERENCES matches(id) ON DELETE CASCADE, -- Se elimina junto con el partido
+  team_id INTEGER NOT NULL REFERENCES teams(id),      -- Referencia al equipo que recibió la tarjeta
+  player_id INTEGER REFERENCES players(id) ON DELETE SET NULL, -- Jugador registrado (NULL si es texto libre)
+  player TEXT NOT NULL,                               -- Jugador que recibió la tarjeta
+  minute TEXT NOT NULL                                -- Minuto de la tarjeta (MM:SS)
+);
+CREATE INDEX idx_yellow_cards_match ON yellow_cards(match_id);
+
+-- Tarjetas rojas
+CREATE TABLE red_cards (
+  id SERIAL PRIMARY KEY,                              -- ID de la tarjeta roja
+  match_id INTEGER NOT NULL REFERENCES matches(id) ON DELETE CASCADE, -- Se elimina junto con el partido
+  team_id INTEGER NOT NULL REFERENCES teams(id),      -- Referencia al equipo que recibió la tarjeta
+  player_id INTEGER REFERENCES players(id) ON DELETE SET NULL, -- Jugador registrado (NULL si es texto libre)
+  player TEXT NOT NULL,                               -- Jugador que recibió la tarjeta
+  minute TEXT NOT NULL                                -- Minuto de la tarjeta (MM:SS)
+);
+CREATE INDEX idx_red_cards_match ON red_cards(match_id);
+
+-- Webhooks
+CREATE TABLE webhooks (
+  id SERIAL PRIMARY KEY,                              -- ID del webhook
+  url TEXT NOT NULL,                                  -- URL que recibe los eventos por POST
+  events TEXT NOT NULL,                               -- Tipos de evento separados por comas
+  match_id INTEGER,                                   -- Filtro opcional por partido
+  team_id INTEGER REFERENCES teams(id) ON DELETE CASCADE, -- Filtro opcional por equipo
+  secret TEXT NOT NULL,                               -- Secreto compartido para firmar las entregas
+  active BOOLEAN NOT NULL DEFAULT TRUE,               -- Si el webhook recibe eventos
+  created_at TEXT NOT NULL DEFAULT (to_char(now() AT TIME ZONE 'UTC', 'YYYY-MM-DD HH24:MI:SS')) -- Fecha de registro
+);
+
+-- Historial de entregas de los webhooks
+CREATE TABLE webhook_deliveries (
+  id SERIAL PRIMARY KEY,                              -- ID del intento
+  webhook_id INTEGER NOT NULL REFERENCES webhooks(id) ON DELETE CASCADE, -- Se elimina junto con el webhook
+  delivery_id TEXT NOT NULL,                          -- ID de la entrega, igual en todos sus reintentos
+  event_id BIGINT NOT NULL,                           -- ID del evento en vivo entregado
+  event_type TEXT NOT NULL,                           -- Tipo de evento entregado
+  match_id INTEGER NOT NULL,                          -- Partido del evento
+  attempt INTEGER NOT NULL,                           -- Número de intento (desde 1)
+  status_code INTEGER NOT NULL DEFAULT 0,             -- Código HTTP de la respuesta (0 si no hubo respuesta)
+  success BOOLEAN NOT NULL DEFAULT FALSE,             -- Si el receptor respondió 2xx
+  error TEXT NOT NULL DEFAULT '',                     -- Motivo del fallo
+  duration_ms BIGINT NOT NULL DEFAULT 0,              -- Duración del intento en milisegundos
+  created_at TEXT NOT NULL DEFAULT (to_char(now() AT TIME ZONE 'UTC', 'YYYY-MM-DD HH24:MI:SS')) -- Fecha del intento
+);
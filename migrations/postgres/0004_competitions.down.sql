@@ -0,0 +1,4 @@
+-- Quita la temporada de los partidos y elimina las competiciones y temporadas
+ALTER TABLE matches DROP COLUMN season_id;
+DROP TABLE seasons;
+DROP TABLE competitions;
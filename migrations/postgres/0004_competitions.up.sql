@@ -0,0 +1,27 @@
+-- Agrega las competiciones y sus temporadas. Cada partido puede pertenecer a una temporada;
+-- los partidos existentes quedan sin temporada hasta que se les asigne una con PUT /api/matches/{id}.
+
+-- Competiciones
+CREATE TABLE competitions (
+  id SERIAL PRIMARY KEY,                              -- ID de la competición
+  name TEXT NOT NULL,                                 -- Nombre (La Liga, Copa del Rey, ...)
+  country TEXT NOT NULL DEFAULT '',                   -- País donde se disputa
+  type TEXT NOT NULL DEFAULT 'league'                 -- Formato: league o cup
+);
+-- El nombre es único sin distinguir mayúsculas, igual que COLLATE NOCASE en SQLite
+CREATE UNIQUE INDEX idx_competitions_name ON competitions (LOWER(name));
+
+-- Temporadas
+CREATE TABLE seasons (
+  id SERIAL PRIMARY KEY,                              -- ID de la temporada
+  competition_id INTEGER NOT NULL REFERENCES competitions(id), -- Referencia a la competición
+  name TEXT NOT NULL,                                 -- Nombre (2024/25, 2025, ...)
+  start_date TEXT NOT NULL,                           -- Primer día de la temporada (YYYY-MM-DD)
+  end_date TEXT NOT NULL                              -- Último día de la temporada (YYYY-MM-DD)
+);
+-- El nombre no se repite en la competición
+CREATE UNIQUE INDEX idx_seasons_name ON seasons (competition_id, LOWER(name));
+
+-- Temporada de cada partido
+ALTER TABLE matches ADD COLUMN season_id INTEGER REFERENCES seasons(id);
+CREATE INDEX idx_matches_season ON matches(season_id);
@@ -0,0 +1,9 @@
+-- El nombre de la clave de API identifica al actor en la auditoría: no se repite sin distinguir
+-- mayúsculas, tampoco entre las claves revocadas.
+
+-- Las claves repetidas que ya existan, salvo la más antigua de cada nombre, pasan a llamarse "nombre (ID)"
+UPDATE api_keys SET name = name || ' (' || id || ')'
+WHERE EXISTS (SELECT 1 FROM api_keys k WHERE LOWER(k.name) = LOWER(api_keys.name) AND k.id < api_keys.id);
+
+-- El nombre es único sin distinguir mayúsculas, igual que COLLATE NOCASE en SQLite
+CREATE UNIQUE INDEX idx_api_keys_name ON api_keys (LOWER(name));
@@ -0,0 +1,5 @@
+-- Elimina el esquema inicial
+DROP TABLE red_cards;
+DROP TABLE yellow_cards;
+DROP TABLE goals;
+DROP TABLE matches;
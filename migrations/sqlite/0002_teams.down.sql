@@ -0,0 +1,65 @@
+-- Vuelve a guardar el nombre del equipo en texto libre en partidos y eventos y elimina la tabla teams
+
+-- Partidos
+CREATE TABLE matches_old (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  home_team TEXT NOT NULL,
+  away_team TEXT NOT NULL,
+  match_date TEXT NOT NULL,
+  extra_time TEXT DEFAULT '00:00'
+);
+INSERT INTO matches_old (id, home_team, away_team, match_date, extra_time)
+  SELECT m.id,
+         (SELECT name FROM teams WHERE id = m.home_team_id),
+         (SELECT name FROM teams WHERE id = m.away_team_id),
+         m.match_date, m.extra_time
+  FROM matches m;
+DROP TABLE matches;
+ALTER TABLE matches_old RENAME TO matches;
+
+-- Goles
+CREATE TABLE goals_old (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  match_id INTEGER NOT NULL,
+  team TEXT NOT NULL,
+  player TEXT NOT NULL,
+  minute TEXT NOT NULL,
+  FOREIGN KEY (match_id) REFERENCES matches(id)
+);
+INSERT INTO goals_old (id, match_id, team, player, minute)
+  SELECT e.id, e.match_id, (SELECT name FROM teams WHERE id = e.team_id), e.player, e.minute
+  FROM goals e;
+DROP TABLE goals;
+ALTER TABLE goals_old RENAME TO goals;
+
+-- Tarjetas amarillas
+CREATE TABLE yellow_cards_old (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  match_id INTEGER NOT NULL,
+  team TEXT NOT NULL,
+  player TEXT NOT NULL,
+  minute TEXT NOT NULL,
+  FOREIGN KEY (match_id) REFERENCES matches(id)
+);
+INSERT INTO yellow_cards_old (id, match_id, team, player, minute)
+  SELECT e.id, e.match_id, (SELECT name FROM teams WHERE id = e.team_id), e.player, e.minute
+  FROM yellow_cards e;
+DROP TABLE yellow_cards;
+ALTER TABLE yellow_cards_old RENAME TO yellow_cards;
+
+-- Tarjetas rojas
+CREATE TABLE red_cards_old (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  match_id INTEGER NOT NULL,
+  team TEXT NOT NULL,
+  player TEXT NOT NULL,
+  minute TEXT NOT NULL,
+  FOREIGN KEY (match_id) REFERENCES matches(id)
+);
+INSERT INTO red_cards_old (id, match_id, team, player, minute)
+  SELECT e.id, e.match_id, (SELECT name FROM teams WHERE id = e.team_id), e.player, e.minute
+  FROM red_cards e;
+DROP TABLE red_cards;
+ALTER TABLE red_cards_old RENAME TO red_cards;
+
+DROP TABLE teams;
@@ -0,0 +1,86 @@
+-- Pasa los equipos en texto libre a la tabla teams y los partidos y eventos a referenciarla por ID
+
+-- Tabla de equipos
+CREATE TABLE teams (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,               -- ID del equipo
+  name TEXT NOT NULL UNIQUE COLLATE NOCASE,           -- Nombre oficial del equipo
+  short_name TEXT NOT NULL DEFAULT '',                -- Abreviatura (RMA, FCB, ...)
+  city TEXT NOT NULL DEFAULT '',                      -- Ciudad del equipo
+  stadium TEXT NOT NULL DEFAULT '',                   -- Estadio donde juega de local
+  founded_year INTEGER NOT NULL DEFAULT 0             -- Año de fundación (0 si se desconoce)
+);
+
+-- Crear un equipo por cada nombre distinto usado en partidos y eventos
+INSERT OR IGNORE INTO teams (name)
+  SELECT TRIM(home_team) FROM matches
+  UNION SELECT TRIM(away_team) FROM matches
+  UNION SELECT TRIM(team) FROM goals
+  UNION SELECT TRIM(team) FROM yellow_cards
+  UNION SELECT TRIM(team) FROM red_cards;
+
+-- Partidos
+CREATE TABLE matches_new (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  home_team_id INTEGER NOT NULL,
+  away_team_id INTEGER NOT NULL,
+  match_date TEXT NOT NULL,
+  extra_time TEXT DEFAULT '00:00',
+  FOREIGN KEY (home_team_id) REFERENCES teams(id),
+  FOREIGN KEY (away_team_id) REFERENCES teams(id)
+);
+INSERT INTO matches_new (id, home_team_id, away_team_id, match_date, extra_time)
+  SELECT m.id,
+         (SELECT id FROM teams WHERE name = TRIM(m.home_team)),
+         (SELECT id FROM teams WHERE name = TRIM(m.away_team)),
+         m.match_date, m.extra_time
+  FROM matches m;
+DROP TABLE matches;
+ALTER TABLE matches_new RENAME TO matches;
+
+-- Goles
+CREATE TABLE goals_new (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  match_id INTEGER NOT NULL,
+  team_id INTEGER NOT NULL,
+  player TEXT NOT NULL,
+  minute TEXT NOT NULL,
+  FOREIGN KEY (match_id) REFERENCES matches(id),
+  FOREIGN KEY (team_id) REFERENCES teams(id)
+);
+INSERT INTO goals_new (id, match_id, team_id, player, minute)
+  SELECT e.id, e.match_id, (SELECT id FROM teams WHERE name = TRIM(e.team)), e.player, e.minute
+  FROM goals e;
+DROP TABLE goals;
+ALTER TABLE goals_new RENAME TO goals;
+
+-- Tarjetas amarillas
+CREATE TABLE yellow_cards_new (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  match_id INTEGER NOT NULL,
+  team_id INTEGER NOT NULL,
+  player TEXT NOT NULL,
+  minute TEXT NOT NULL,
+  FOREIGN KEY (match_id) REFERENCES matches(id),
+  FOREIGN KEY (team_id) REFERENCES teams(id)
+);
+INSERT INTO yellow_cards_new (id, match_id, team_id, player, minute)
+  SELECT e.id, e.match_id, (SELECT id FROM teams WHERE name = TRIM(e.team)), e.player, e.minute
+  FROM yellow_cards e;
+DROP TABLE yellow_cards;
+ALTER TABLE yellow_cards_new RENAME TO yellow_cards;
+
+-- Tarjetas rojas
+CREATE TABLE red_cards_new (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  match_id INTEGER NOT NULL,
+  team_id INTEGER NOT NULL,
+  player TEXT NOT NULL,
+  minute TEXT NOT NULL,
+  FOREIGN KEY (match_id) REFERENCES matches(id),
+  FOREIGN KEY (team_id) REFERENCES teams(id)
+);
+INSERT INTO red_cards_new (id, match_id, team_id, player, minute)
+  SELECT e.id, e.match_id, (SELECT id FROM teams WHERE name = TRIM(e.team)), e.player, e.minute
+  FROM red_cards e;
+DROP TABLE red_cards;
+ALTER TABLE red_cards_new RENAME TO red_cards;
@@ -0,0 +1,49 @@
+-- Quita la referencia a los jugadores de los eventos y elimina la tabla players.
+-- SQLite no permite eliminar una columna con clave foránea, por eso se reconstruyen las tablas.
+
+-- Goles
+CREATE TABLE goals_old (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  match_id INTEGER NOT NULL,
+  team_id INTEGER NOT NULL,
+  player TEXT NOT NULL,
+  minute TEXT NOT NULL,
+  FOREIGN KEY (match_id) REFERENCES matches(id),
+  FOREIGN KEY (team_id) REFERENCES teams(id)
+);
+INSERT INTO goals_old (id, match_id, team_id, player, minute)
+  SELECT id, match_id, team_id, player, minute FROM goals;
+DROP TABLE goals;
+ALTER TABLE goals_old RENAME TO goals;
+
+-- Tarjetas amarillas
+CREATE TABLE yellow_cards_old (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  match_id INTEGER NOT NULL,
+  team_id INTEGER NOT NULL,
+  player TEXT NOT NULL,
+  minute TEXT NOT NULL,
+  FOREIGN KEY (match_id) REFERENCES matches(id),
+  FOREIGN KEY (team_id) REFERENCES teams(id)
+);
+INSERT INTO yellow_cards_old (id, match_id, team_id, player, minute)
+  SELECT id, match_id, team_id, player, minute FROM yellow_cards;
+DROP TABLE yellow_cards;
+ALTER TABLE yellow_cards_old RENAME TO yellow_cards;
+
+-- Tarjetas rojas
+CREATE TABLE red_cards_old (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  match_id INTEGER NOT NULL,
+  team_id INTEGER NOT NULL,
+  player TEXT NOT NULL,
+  minute TEXT NOT NULL,
+  FOREIGN KEY (match_id) REFERENCES matches(id),
+  FOREIGN KEY (team_id) REFERENCES teams(id)
+);
+INSERT INTO red_cards_old (id, match_id, team_id, player, minute)
+  SELECT id, match_id, team_id, player, minute FROM red_cards;
+DROP TABLE red_cards;
+ALTER TABLE red_cards_old RENAME TO red_cards;
+
+DROP TABLE players;
@@ -0,0 +1,17 @@
+-- Agrega el registro de jugadores. Los eventos existentes quedan con player_id en NULL
+-- y conservan el nombre en texto libre
+
+-- Tabla de jugadores
+CREATE TABLE players (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,               -- ID del jugador
+  team_id INTEGER NOT NULL,                           -- Equipo en cuya plantilla está inscrito
+  name TEXT NOT NULL,                                 -- Nombre del jugador
+  shirt_number INTEGER NOT NULL DEFAULT 0,            -- Dorsal (0 si no tiene asignado)
+  position TEXT NOT NULL DEFAULT '',                  -- Posición (Portero, Defensa, ...)
+  nationality TEXT NOT NULL DEFAULT '',               -- Nacionalidad
+  FOREIGN KEY (team_id) REFERENCES teams(id)          -- Relación con la tabla de equipos
+);
+
+ALTER TABLE goals ADD COLUMN player_id INTEGER REFERENCES players(id);
+ALTER TABLE yellow_cards ADD COLUMN player_id INTEGER REFERENCES players(id);
+ALTER TABLE red_cards ADD COLUMN player_id INTEGER REFERENCES players(id);
@@ -0,0 +1,3 @@
+-- Quita el estado de los partidos
+ALTER TABLE matches DROP COLUMN status_updated_at;
+ALTER TABLE matches DROP COLUMN status;
@@ -0,0 +1,21 @@
+-- Agrega el estado de los partidos. Los partidos con fecha pasada quedan como terminados
+-- y el resto como programados. SQLite no admite expresiones como valor por defecto en
+-- ALTER TABLE, por eso se reconstruye la tabla para que status_updated_at tome la fecha actual.
+CREATE TABLE matches_new (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,               -- ID del partido
+  home_team_id INTEGER NOT NULL,                      -- Referencia al equipo local
+  away_team_id INTEGER NOT NULL,                      -- Referencia al equipo visitante
+  match_date TEXT NOT NULL,                           -- Fecha del partido (YYYY-MM-DD)
+  extra_time TEXT DEFAULT '00:00',                    -- Tiempo extra en formato MM:SS
+  status TEXT NOT NULL DEFAULT 'scheduled',           -- Estado (scheduled, live, half_time, finished, postponed, cancelled)
+  status_updated_at TEXT NOT NULL DEFAULT (datetime('now')), -- Fecha del último cambio de estado (UTC)
+  FOREIGN KEY (home_team_id) REFERENCES teams(id),    -- Relación con la tabla de equipos
+  FOREIGN KEY (away_team_id) REFERENCES teams(id)     -- Relación con la tabla de equipos
+);
+INSERT INTO matches_new (id, home_team_id, away_team_id, match_date, extra_time, status, status_updated_at)
+  SELECT id, home_team_id, away_team_id, match_date, extra_time,
+         CASE WHEN date(match_date) < date('now') THEN 'finished' ELSE 'scheduled' END,
+         CASE WHEN date(match_date) < date('now') THEN date(match_date) || ' 23:59:59' ELSE datetime('now') END
+  FROM matches;
+DROP TABLE matches;
+ALTER TABLE matches_new RENAME TO matches;
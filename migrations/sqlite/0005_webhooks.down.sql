@@ -0,0 +1,3 @@
+-- Elimina los webhooks y su historial de entregas
+DROP TABLE webhook_deliveries;
+DROP TABLE webhooks;
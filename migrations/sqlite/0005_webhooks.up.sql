@@ -0,0 +1,31 @@
+-- Agrega los webhooks y su historial de entregas
+
+-- Tabla de webhooks registrados por sitios externos
+CREATE TABLE webhooks (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,               -- ID del webhook
+  url TEXT NOT NULL,                                  -- URL que recibe los eventos por POST
+  events TEXT NOT NULL,                               -- Tipos de evento separados por comas
+  match_id INTEGER,                                   -- Filtro opcional por partido
+  team_id INTEGER,                                    -- Filtro opcional por equipo
+  secret TEXT NOT NULL,                               -- Secreto compartido para firmar las entregas
+  active INTEGER NOT NULL DEFAULT 1,                  -- Si el webhook recibe eventos
+  created_at TEXT NOT NULL DEFAULT (datetime('now')), -- Fecha de registro
+  FOREIGN KEY (team_id) REFERENCES teams(id)          -- Relación con la tabla de equipos
+);
+
+-- Historial de intentos de entrega de los webhooks
+CREATE TABLE webhook_deliveries (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,               -- ID del intento
+  webhook_id INTEGER NOT NULL,                        -- Referencia al webhook
+  delivery_id TEXT NOT NULL,                          -- ID de la entrega, igual en todos sus reintentos
+  event_id INTEGER NOT NULL,                          -- ID del evento en vivo entregado
+  event_type TEXT NOT NULL,                           -- Tipo de evento entregado
+  match_id INTEGER NOT NULL,                          -- Partido del evento
+  attempt INTEGER NOT NULL,                           -- Número de intento (desde 1)
+  status_code INTEGER NOT NULL DEFAULT 0,             -- Código HTTP de la respuesta (0 si no hubo respuesta)
+  success INTEGER NOT NULL DEFAULT 0,                 -- Si el receptor respondió 2xx
+  error TEXT NOT NULL DEFAULT '',                     -- Motivo del fallo
+  duration_ms INTEGER NOT NULL DEFAULT 0,             -- Duración del intento en milisegundos
+  created_at TEXT NOT NULL DEFAULT (datetime('now')), -- Fecha del intento
+  FOREIGN KEY (webhook_id) REFERENCES webhooks(id)    -- Relación con la tabla de webhooks
+);
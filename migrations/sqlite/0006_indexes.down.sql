@@ -0,0 +1,4 @@
+-- Elimina los índices de eventos por partido
+DROP INDEX idx_red_cards_match;
+DROP INDEX idx_yellow_cards_match;
+DROP INDEX idx_goals_match;
@@ -0,0 +1,4 @@
+-- Índices para buscar los eventos de cada partido sin recorrer las tablas completas
+CREATE INDEX idx_goals_match ON goals(match_id);
+CREATE INDEX idx_yellow_cards_match ON yellow_cards(match_id);
+CREATE INDEX idx_red_cards_match ON red_cards(match_id);
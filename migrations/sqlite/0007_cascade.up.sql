@@ -0,0 +1,96 @@
+-- Borra en cascada los eventos de un partido, desenlaza los eventos de los jugadores eliminados
+-- y elimina los webhooks de los equipos eliminados. SQLite no permite modificar claves foráneas,
+-- por eso se reconstruyen las tablas de eventos y de webhooks copiando sus filas.
+-- Las filas huérfanas se copian tal cual; para eliminarlas ejecutar después: go run . cleanup-orphans
+
+-- Goles
+CREATE TABLE goals_new (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,               -- ID del gol
+  match_id INTEGER NOT NULL,                          -- Referencia al partido
+  team_id INTEGER NOT NULL,                           -- Referencia al equipo que anotó
+  player_id INTEGER,                                  -- Jugador registrado (NULL si es texto libre)
+  player TEXT NOT NULL,                               -- Jugador que anotó
+  minute TEXT NOT NULL,                               -- Minuto del gol (MM:SS)
+  FOREIGN KEY (match_id) REFERENCES matches(id) ON DELETE CASCADE,   -- Se elimina junto con el partido
+  FOREIGN KEY (team_id) REFERENCES teams(id),         -- Relación con la tabla de equipos
+  FOREIGN KEY (player_id) REFERENCES players(id) ON DELETE SET NULL -- Queda como texto libre si se elimina el jugador
+);
+INSERT INTO goals_new (id, match_id, team_id, player_id, player, minute)
+  SELECT id, match_id, team_id, player_id, player, minute FROM goals;
+DROP TABLE goals;
+ALTER TABLE goals_new RENAME TO goals;
+CREATE INDEX idx_goals_match ON goals(match_id);
+
+-- Tarjetas amarillas
+CREATE TABLE yellow_cards_new (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,               -- ID de la tarjeta amarilla
+  match_id INTEGER NOT NULL,                          -- Referencia al partido
+  team_id INTEGER NOT NULL,                           -- Referencia al equipo que recibió la tarjeta
+  player_id INTEGER,                                  -- Jugador registrado (NULL si es texto libre)
+  player TEXT NOT NULL,                               -- Jugador que recibió la tarjeta
+  minute TEXT NOT NULL,                               -- Minuto de la tarjeta (MM:SS)
+  FOREIGN KEY (match_id) REFERENCES matches(id) ON DELETE CASCADE,   -- Se elimina junto con el partido
+  FOREIGN KEY (team_id) REFERENCES teams(id),         -- Relación con la tabla de equipos
+  FOREIGN KEY (player_id) REFERENCES players(id) ON DELETE SET NULL -- Queda como texto libre si se elimina el jugador
+);
+INSERT INTO yellow_cards_new (id, match_id, team_id, player_id, player, minute)
+  SELECT id, match_id, team_id, player_id, player, minute FROM yellow_cards;
+DROP TABLE yellow_cards;
+ALTER TABLE yellow_cards_new RENAME TO yellow_cards;
+CREATE INDEX idx_yellow_cards_match ON yellow_cards(match_id);
+
+-- Tarjetas rojas
+CREATE TABLE red_cards_new (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,               -- ID de la tarjeta roja
+  match_id INTEGER NOT NULL,                          -- Referencia al partido
+  team_id INTEGER NOT NULL,                           -- Referencia al equipo que recibió la tarjeta
+  player_id INTEGER,                                  -- Jugador registrado (NULL si es texto libre)
+  player TEXT NOT NULL,                               -- Jugador que recibió la tarjeta
+  minute TEXT NOT NULL,                               -- Minuto de la tarjeta (MM:SS)
+  FOREIGN KEY (match_id) REFERENCES matches(id) ON DELETE CASCADE,   -- Se elimina junto con el partido
+  FOREIGN KEY (team_id) REFERENCES teams(id),         -- Relación con la tabla de equipos
+  FOREIGN KEY (player_id) REFERENCES players(id) ON DELETE SET NULL -- Queda como texto libre si se elimina el jugador
+);
+INSERT INTO red_cards_new (id, match_id, team_id, player_id, player, minute)
+  SELECT id, match_id, team_id, player_id, player, minute FROM red_cards;
+DROP TABLE red_cards;
+ALTER TABLE red_cards_new RENAME TO red_cards;
+CREATE INDEX idx_red_cards_match ON red_cards(match_id);
+
+-- Webhooks
+CREATE TABLE webhooks_new (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,               -- ID del webhook
+  url TEXT NOT NULL,                                  -- URL que recibe los eventos por POST
+  events TEXT NOT NULL,                               -- Tipos de evento separados por comas
+  match_id INTEGER,                                   -- Filtro opcional por partido
+  team_id INTEGER,                                    -- Filtro opcional por equipo
+  secret TEXT NOT NULL,                               -- Secreto compartido para firmar las entregas
+  active INTEGER NOT NULL DEFAULT 1,                  -- Si el webhook recibe eventos
+  created_at TEXT NOT NULL DEFAULT (datetime('now')), -- Fecha de registro
+  FOREIGN KEY (team_id) REFERENCES teams(id) ON DELETE CASCADE -- Se elimina junto con el equipo del filtro
+);
+INSERT INTO webhooks_new (id, url, events, match_id, team_id, secret, active, created_at)
+  SELECT id, url, events, match_id, team_id, secret, active, created_at FROM webhooks;
+DROP TABLE webhooks;
+ALTER TABLE webhooks_new RENAME TO webhooks;
+
+-- Historial de entregas de los webhooks
+CREATE TABLE webhook_deliveries_new (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,               -- ID del intento
+  webhook_id INTEGER NOT NULL,                        -- Referencia al webhook
+  delivery_id TEXT NOT NULL,                          -- ID de la entrega, igual en todos sus reintentos
+  event_id INTEGER NOT NULL,                          -- ID del evento en vivo entregado
+  event_type TEXT NOT NULL,                           -- Tipo de evento entregado
+  match_id INTEGER NOT NULL,                          -- Partido del evento
+  attempt INTEGER NOT NULL,                           -- Número de intento (desde 1)
+  status_code INTEGER NOT NULL DEFAULT 0,             -- Código HTTP de la respuesta (0 si no hubo respuesta)
+  success INTEGER NOT NULL DEFAULT 0,                 -- Si el receptor respondió 2xx
+  error TEXT NOT NULL DEFAULT '',                     -- Motivo del fallo
+  duration_ms INTEGER NOT NULL DEFAULT 0,             -- Duración del intento en milisegundos
+  created_at TEXT NOT NULL DEFAULT (datetime('now')), -- Fecha del intento
+  FOREIGN KEY (webhook_id) REFERENCES webhooks(id) ON DELETE CASCADE -- Se elimina junto con el webhook
+);
+INSERT INTO webhook_deliveries_new (id, webhook_id, delivery_id, event_id, event_type, match_id, attempt, status_code, success, error, duration_ms, created_at)
+  SELECT id, webhook_id, delivery_id, event_id, event_type, match_id, attempt, status_code, success, error, duration_ms, created_at FROM webhook_deliveries;
+DROP TABLE webhook_deliveries;
+ALTER TABLE webhook_deliveries_new RENAME TO webhook_deliveries;
@@ -0,0 +1,2 @@
+-- Elimina las claves de API
+DROP TABLE api_keys;
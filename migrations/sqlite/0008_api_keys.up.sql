@@ -0,0 +1,12 @@
+-- Agrega las claves de API con las que se autentican los scripts e integraciones
+
+-- La clave no se guarda: solo su hash SHA-256 y el comienzo para reconocerla
+CREATE TABLE api_keys (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,               -- ID de la clave
+  name TEXT NOT NULL,                                 -- Nombre del script o integración que la usa
+  role TEXT NOT NULL,                                 -- Rol: viewer, scorer o admin
+  prefix TEXT NOT NULL,                               -- Comienzo de la clave para reconocerla en los listados
+  key_hash TEXT NOT NULL UNIQUE,                      -- SHA-256 de la clave en hexadecimal
+  active INTEGER NOT NULL DEFAULT 1,                  -- Si la clave no fue revocada
+  created_at TEXT NOT NULL DEFAULT (datetime('now'))  -- Fecha de creación
+);
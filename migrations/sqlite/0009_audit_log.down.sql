@@ -0,0 +1,2 @@
+-- Elimina el historial de auditoría
+DROP TABLE audit_log;
@@ -0,0 +1,20 @@
+-- Agrega el historial de auditoría de los cambios de partidos y eventos
+
+-- match_id no tiene clave foránea para que el historial de un partido sobreviva a su eliminación
+CREATE TABLE audit_log (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,               -- ID de la entrada
+  actor TEXT NOT NULL,                                -- Clave de API, sub del token o anonymous
+  actor_role TEXT NOT NULL DEFAULT '',                -- Rol del actor (vacío sin autenticación)
+  action TEXT NOT NULL,                               -- Acción: match.update, goal.create, ...
+  method TEXT NOT NULL,                               -- Método HTTP de la solicitud
+  route TEXT NOT NULL,                                -- Plantilla de la ruta (/api/matches/{id})
+  path TEXT NOT NULL,                                 -- URL de la solicitud (/api/matches/1)
+  match_id INTEGER,                                   -- Partido afectado
+  before_json TEXT,                                   -- Estado anterior en JSON (NULL si no existía)
+  after_json TEXT,                                    -- Estado posterior en JSON (NULL si se eliminó)
+  request_id TEXT NOT NULL DEFAULT '',                -- X-Request-ID para buscar la solicitud en los logs
+  remote_addr TEXT NOT NULL DEFAULT '',               -- Dirección del cliente
+  created_at TEXT NOT NULL DEFAULT (datetime('now'))  -- Fecha del cambio (UTC)
+);
+CREATE INDEX idx_audit_log_match ON audit_log(match_id);
+CREATE INDEX idx_audit_log_actor ON audit_log(actor);
@@ -0,0 +1,5 @@
+-- Quita la temporada de los partidos y elimina las competiciones y temporadas
+DROP INDEX idx_matches_season;
+ALTER TABLE matches DROP COLUMN season_id;
+DROP TABLE seasons;
+DROP TABLE competitions;
@@ -0,0 +1,25 @@
+-- Agrega las competiciones y sus temporadas. Cada partido puede pertenecer a una temporada;
+-- los partidos existentes quedan sin temporada hasta que se les asigne una con PUT /api/matches/{id}.
+
+-- Competiciones
+CREATE TABLE competitions (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,               -- ID de la competición
+  name TEXT NOT NULL UNIQUE COLLATE NOCASE,           -- Nombre (La Liga, Copa del Rey, ...)
+  country TEXT NOT NULL DEFAULT '',                   -- País donde se disputa
+  type TEXT NOT NULL DEFAULT 'league'                 -- Formato: league o cup
+);
+
+-- Temporadas
+CREATE TABLE seasons (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,               -- ID de la temporada
+  competition_id INTEGER NOT NULL,                    -- Referencia a la competición
+  name TEXT NOT NULL COLLATE NOCASE,                  -- Nombre (2024/25, 2025, ...)
+  start_date TEXT NOT NULL,                           -- Primer día de la temporada (YYYY-MM-DD)
+  end_date TEXT NOT NULL,                             -- Último día de la temporada (YYYY-MM-DD)
+  UNIQUE (competition_id, name),                      -- El nombre no se repite en la competición
+  FOREIGN KEY (competition_id) REFERENCES competitions(id) -- Relación con la tabla de competiciones
+);
+
+-- Temporada de cada partido
+ALTER TABLE matches ADD COLUMN season_id INTEGER REFERENCES seasons(id);
+CREATE INDEX idx_matches_season ON matches(season_id);
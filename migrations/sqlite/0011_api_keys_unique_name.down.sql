@@ -0,0 +1,2 @@
+-- Permite repetir el nombre de las claves de API. Los nombres cambiados al aplicar la migración no se restauran.
+DROP INDEX idx_api_keys_name;
@@ -0,0 +1,8 @@
+-- El nombre de la clave de API identifica al actor en la auditoría: no se repite sin distinguir
+-- mayúsculas, tampoco entre las claves revocadas.
+
+-- Las claves repetidas que ya existan, salvo la más antigua de cada nombre, pasan a llamarse "nombre (ID)"
+UPDATE api_keys SET name = name || ' (' || id || ')'
+WHERE EXISTS (SELECT 1 FROM api_keys k WHERE k.name = api_keys.name COLLATE NOCASE AND k.id < api_keys.id);
+
+CREATE UNIQUE INDEX idx_api_keys_name ON api_keys (name COLLATE NOCASE);
@@ -0,0 +1,299 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// Player representa a un jugador inscrito en la plantilla de un equipo
+// @description Modelo que contiene la información de un jugador
+// @property id, teamId, name, shirtNumber, position, nationality
+// @example { "id": 1, "teamId": 1, "name": "Vinicius Jr.", "shirtNumber": 7, "position": "Delantero", "nationality": "Brasil" }
+type Player struct {
+	ID          int    `json:"id"`
+	TeamID      int    `json:"teamId"`
+	Name        string `json:"name"`
+	ShirtNumber int    `json:"shirtNumber"`
+	Position    string `json:"position"`
+	Nationality string `json:"nationality"`
+}
+
+// errPlayerNotInSquad indica que el jugador no pertenece a la plantilla del equipo del evento
+var errPlayerNotInSquad = errors.New("el jugador no pertenece a la plantilla del equipo")
+
+// resolveEventPlayer determina el jugador de un evento.
+// Si playerID es distinto de cero, el jugador debe estar inscrito en el equipo teamID.
+// Si solo se envía el nombre, se intenta enlazar con un jugador de la plantilla con ese
+// nombre; si no existe, se conserva el nombre en texto libre y el ID queda en NULL.
+func resolveEventPlayer(teamID, playerID int, name string) (sql.NullInt64, string, error) {
+	if playerID != 0 {
+		p, err := store.Player(playerID)
+		if err == errNotFound || (err == nil && p.TeamID != teamID) {
+			return sql.NullInt64{}, "", errPlayerNotInSquad
+		} else if err != nil {
+			return sql.NullInt64{}, "", err
+		}
+		return sql.NullInt64{Int64: int64(playerID), Valid: true}, p.Name, nil
+	}
+
+	// Compatibilidad con clientes que solo envían el nombre del jugador
+	name = strings.TrimSpace(name)
+	p, err := store.PlayerByName(teamID, name)
+	if err == errNotFound {
+		return sql.NullInt64{}, name, nil
+	} else if err != nil {
+		return sql.NullInt64{}, "", err
+	}
+	return sql.NullInt64{Int64: int64(p.ID), Valid: true}, p.Name, nil
+}
+
+// playerTeam devuelve el equipo al que pertenece un jugador
+func playerTeam(playerID int) (int, error) {
+	p, err := store.Player(playerID)
+	if err == errNotFound {
+		return 0, errPlayerNotInSquad
+	}
+	return p.TeamID, err
+}
+
+// validatePlayer verifica los campos de un jugador y que el dorsal no esté repetido
+// en la plantilla. Devuelve el detalle de los campos que no son válidos.
+func validatePlayer(p *Player) ([]FieldError, error) {
+	var errs []FieldError
+	p.Name = strings.TrimSpace(p.Name)
+	if p.Name == "" {
+		errs = append(errs, FieldError{"name", fieldRequired, "El nombre del jugador es obligatorio"})
+	}
+	if p.ShirtNumber < 0 || p.ShirtNumber > 99 {
+		errs = append(errs, FieldError{"shirtNumber", fieldOutOfRange, "El dorsal debe estar entre 1 y 99, o 0 si todavía no tiene"})
+	} else if p.ShirtNumber > 0 {
+		// El dorsal 0 significa que el jugador todavía no tiene número asignado
+		taken, err := store.ShirtNumberTaken(p.TeamID, p.ShirtNumber, p.ID)
+		if err != nil {
+			return nil, err
+		}
+		if taken {
+			errs = append(errs, FieldError{"shirtNumber", fieldDuplicate, "El dorsal ya está asignado a otro jugador del equipo"})
+		}
+	}
+	return errs, nil
+}
+
+// teamExists verifica si existe un equipo con el ID indicado
+func teamExists(id int) (bool, error) {
+	_, err := store.Team(id)
+	if err == errNotFound {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// @Summary Obtener plantilla de un equipo
+// @Description Retorna los jugadores inscritos en un equipo
+// @Tags players
+// @Accept json
+// @Produce json
+// @Param id path int true "ID del equipo"
+// @Success 200 {array} Player
+// @Failure 401 {object} Problem
+// @Failure 404 {object} Problem
+// @Failure 429 {object} Problem
+// @Failure 500 {object} Problem
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/teams/{id}/players [get]
+func getPlayers(w http.ResponseWriter, r *http.Request) {
+	teamID := pathID(r, "id")
+
+	if ok, err := teamExists(teamID); err != nil {
+		dbError(w, r, err)
+		return
+	} else if !ok {
+		writeError(w, r, http.StatusNotFound, codeTeamNotFound, "Equipo no encontrado")
+		return
+	}
+
+	players, err := store.Players(teamID)
+	if err != nil {
+		dbError(w, r, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(players)
+}
+
+// @Summary Obtener jugador
+// @Description Retorna los datos de un jugador de la plantilla de un equipo
+// @Tags players
+// @Accept json
+// @Produce json
+// @Param id path int true "ID del equipo"
+// @Param playerId path int true "ID del jugador"
+// @Success 200 {object} Player
+// @Failure 401 {object} Problem
+// @Failure 404 {object} Problem
+// @Failure 429 {object} Problem
+// @Failure 500 {object} Problem
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/teams/{id}/players/{playerId} [get]
+func getPlayer(w http.ResponseWriter, r *http.Request) {
+	// El jugador debe pertenecer a la plantilla del equipo de la URL
+	p, err := store.Player(pathID(r, "playerId"))
+	if err == errNotFound || (err == nil && p.TeamID != pathID(r, "id")) {
+		writeError(w, r, http.StatusNotFound, codePlayerNotFound, "Jugador no encontrado")
+		return
+	} else if err != nil {
+		dbError(w, r, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(p)
+}
+
+// @Summary Inscribir jugador
+// @Description Agrega un jugador a la plantilla de un equipo
+// @Tags players
+// @Accept json
+// @Produce json
+// @Param id path int true "ID del equipo"
+// @Param player body Player true "Datos del jugador"
+// @Success 200 {object} Player
+// @Failure 400 {object} Problem
+// @Failure 401 {object} Problem
+// @Failure 403 {object} Problem
+// @Failure 404 {object} Problem
+// @Failure 429 {object} Problem
+// @Failure 500 {object} Problem
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/teams/{id}/players [post]
+func createPlayer(w http.ResponseWriter, r *http.Request) {
+	teamID := pathID(r, "id")
+
+	if ok, err := teamExists(teamID); err != nil {
+		dbError(w, r, err)
+		return
+	} else if !ok {
+		writeError(w, r, http.StatusNotFound, codeTeamNotFound, "Equipo no encontrado")
+		return
+	}
+
+	var p Player
+	if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+		writeInvalidJSON(w, r)
+		return
+	}
+
+	// El equipo siempre se toma de la URL
+	p.ID = 0
+	p.TeamID = teamID
+	if errs, err := validatePlayer(&p); err != nil {
+		dbError(w, r, err)
+		return
+	} else if errs != nil {
+		writeValidation(w, r, errs...)
+		return
+	}
+
+	id, err := store.CreatePlayer(p)
+	if err != nil {
+		dbError(w, r, err)
+		return
+	}
+
+	p.ID = id
+	json.NewEncoder(w).Encode(p)
+}
+
+// @Summary Actualizar jugador
+// @Description Modifica los datos de un jugador de la plantilla
+// @Tags players
+// @Accept json
+// @Produce json
+// @Param id path int true "ID del equipo"
+// @Param playerId path int true "ID del jugador"
+// @Param player body Player true "Datos actualizados"
+// @Success 200 {object} Player
+// @Failure 400 {object} Problem
+// @Failure 401 {object} Problem
+// @Failure 403 {object} Problem
+// @Failure 404 {object} Problem
+// @Failure 429 {object} Problem
+// @Failure 500 {object} Problem
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/teams/{id}/players/{playerId} [put]
+func updatePlayer(w http.ResponseWriter, r *http.Request) {
+	teamID, playerID := pathID(r, "id"), pathID(r, "playerId")
+
+	var p Player
+	if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+		writeInvalidJSON(w, r)
+		return
+	}
+
+	// Un traspaso se indica enviando un teamId distinto al de la URL
+	if p.TeamID == 0 {
+		p.TeamID = teamID
+	} else if ok, err := teamExists(p.TeamID); err != nil {
+		dbError(w, r, err)
+		return
+	} else if !ok {
+		writeValidation(w, r, FieldError{"teamId", fieldNotFound, "Equipo no encontrado"})
+		return
+	}
+
+	p.ID = playerID
+	if errs, err := validatePlayer(&p); err != nil {
+		dbError(w, r, err)
+		return
+	} else if errs != nil {
+		writeValidation(w, r, errs...)
+		return
+	}
+
+	err := store.UpdatePlayer(teamID, p)
+	if err == errNotFound {
+		writeError(w, r, http.StatusNotFound, codePlayerNotFound, "Jugador no encontrado")
+		return
+	} else if err != nil {
+		dbError(w, r, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(p)
+}
+
+// @Summary Dar de baja jugador
+// @Description Elimina a un jugador de la plantilla. Los eventos ya registrados conservan su nombre
+// @Tags players
+// @Accept json
+// @Produce json
+// @Param id path int true "ID del equipo"
+// @Param playerId path int true "ID del jugador"
+// @Success 204 {string} string "Sin contenido"
+// @Failure 401 {object} Problem
+// @Failure 403 {object} Problem
+// @Failure 404 {object} Problem
+// @Failure 429 {object} Problem
+// @Failure 500 {object} Problem
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/teams/{id}/players/{playerId} [delete]
+func deletePlayer(w http.ResponseWriter, r *http.Request) {
+	// Los eventos ya registrados del jugador quedan como texto libre con su nombre
+	err := store.DeletePlayer(pathID(r, "id"), pathID(r, "playerId"))
+	if err == errNotFound {
+		writeError(w, r, http.StatusNotFound, codePlayerNotFound, "Jugador no encontrado")
+		return
+	} else if err != nil {
+		dbError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
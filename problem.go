@@ -0,0 +1,124 @@
+package main
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+)
+
+// Problem es el cuerpo de todas las respuestas de error, con el formato problem+json
+// de RFC 7807. Code identifica el error de forma estable para los clientes; Detail es
+// el mensaje para mostrar al usuario y puede cambiar de redacción.
+// @description Error de la API (RFC 7807, application/problem+json)
+// @property type, title, status, code, detail, instance, requestId, errors
+// @example { "type": "about:blank", "title": "Bad Request", "status": 400, "code": "validation_failed", "detail": "El nombre del equipo es obligatorio", "instance": "/api/teams", "requestId": "1fe55764dfdc350c23155ad4fd7673b4", "errors": [{ "field": "name", "code": "required", "message": "El nombre del equipo es obligatorio" }] }
+type Problem struct {
+	Type      string       `json:"type" example:"about:blank"`
+	Title     string       `json:"title" example:"Not Found"`
+	Status    int          `json:"status" example:"404"`
+	Code      string       `json:"code" example:"match_not_found"`
+	Detail    string       `json:"detail" example:"Partido no encontrado"`
+	Instance  string       `json:"instance,omitempty" example:"/api/matches/99"`
+	RequestID string       `json:"requestId,omitempty" example:"1fe55764dfdc350c23155ad4fd7673b4"`
+	Errors    []FieldError `json:"errors,omitempty"`
+}
+
+// FieldError describe un campo o parámetro inválido de la solicitud
+// @description Detalle de un campo del cuerpo o parámetro de la URL que no es válido
+// @property field, code, message
+type FieldError struct {
+	Field   string `json:"field" example:"minute"`
+	Code    string `json:"code" example:"invalid_format" enums:"required,invalid_format,out_of_range,not_found,duplicate,invalid"`
+	Message string `json:"message" example:"Formato de tiempo inválido. Usa MM:SS"`
+}
+
+// Códigos de error de Problem.Code. Son parte del contrato de la API: no se renombran.
+const (
+	codeInvalidJSON           = "invalid_json"
+	codeValidationFailed      = "validation_failed"
+	codeMatchNotFound         = "match_not_found"
+	codeTeamNotFound          = "team_not_found"
+	codePlayerNotFound        = "player_not_found"
+	codeEventNotFound         = "event_not_found"
+	codeWebhookNotFound       = "webhook_not_found"
+	codeCompetitionNotFound   = "competition_not_found"
+	codeSeasonNotFound        = "season_not_found"
+	codeTeamNameTaken         = "team_name_taken"
+	codeTeamHasMatches        = "team_has_matches"
+	codeCompetitionNameTaken  = "competition_name_taken"
+	codeSeasonNameTaken       = "season_name_taken"
+	codeCompetitionHasSeasons = "competition_has_seasons"
+	codeSeasonHasMatches      = "season_has_matches"
+	codeInvalidTransition     = "invalid_status_transition"
+	codeStatusChanged         = "status_changed"
+	codeMatchNotInPlay        = "match_not_in_play"
+	codeStreamingUnsupported  = "streaming_unsupported"
+	codeWebSocketHandshake    = "websocket_handshake_failed"
+	codeOriginNotAllowed      = "origin_not_allowed"
+	codeRouteNotFound         = "route_not_found"
+	codeMethodNotAllowed      = "method_not_allowed"
+	codeAuthRequired          = "authentication_required"
+	codeInvalidCredentials    = "invalid_credentials"
+	codeInsufficientRole      = "insufficient_role"
+	codeRateLimited           = "rate_limited"
+	codeInternalError         = "internal_error"
+)
+
+// Códigos de FieldError.Code
+const (
+	fieldRequired      = "required"
+	fieldInvalidFormat = "invalid_format"
+	fieldOutOfRange    = "out_of_range"
+	fieldNotFound      = "not_found"
+	fieldDuplicate     = "duplicate"
+	fieldInvalid       = "invalid"
+)
+
+// newProblem arma un Problem con el título estándar del código HTTP
+func newProblem(status int, code, detail string) *Problem {
+	return &Problem{Type: "about:blank", Title: http.StatusText(status), Status: status, Code: code, Detail: detail}
+}
+
+// validationProblem arma la respuesta 400 para uno o más campos inválidos.
+// Si hay un solo campo su mensaje también es el detail.
+func validationProblem(errs []FieldError) *Problem {
+	detail := "La solicitud tiene campos inválidos"
+	if len(errs) == 1 {
+		detail = errs[0].Message
+	}
+	p := newProblem(http.StatusBadRequest, codeValidationFailed, detail)
+	p.Errors = errs
+	return p
+}
+
+// writeProblem escribe p como application/problem+json, completando la URL y el ID de la solicitud
+func writeProblem(w http.ResponseWriter, r *http.Request, p *Problem) {
+	p.Instance = r.URL.Path
+	p.RequestID = requestID(r.Context())
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(p.Status)
+	json.NewEncoder(w).Encode(p)
+}
+
+// writeError responde con un error sin detalle de campos. Reemplaza a http.Error en los handlers.
+func writeError(w http.ResponseWriter, r *http.Request, status int, code, detail string) {
+	writeProblem(w, r, newProblem(status, code, detail))
+}
+
+// writeValidation responde 400 con el detalle de los campos inválidos
+func writeValidation(w http.ResponseWriter, r *http.Request, errs ...FieldError) {
+	writeProblem(w, r, validationProblem(errs))
+}
+
+// writeInvalidJSON responde 400 cuando el cuerpo no se puede decodificar
+func writeInvalidJSON(w http.ResponseWriter, r *http.Request) {
+	writeError(w, r, http.StatusBadRequest, codeInvalidJSON, "JSON inválido")
+}
+
+// dbError registra un error de la base de datos con el ID de la solicitud y responde 500.
+// El mensaje del driver no se envía al cliente; se busca en los logs por requestId.
+func dbError(w http.ResponseWriter, r *http.Request, err error) {
+	slog.ErrorContext(r.Context(), "error de base de datos", "error", err)
+	writeError(w, r, http.StatusInternalServerError, codeInternalError, "Error interno del servidor")
+}
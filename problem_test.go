@@ -0,0 +1,81 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestWriteProblem(t *testing.T) {
+	// logRequests asigna el ID de la solicitud que luego aparece en el problema
+	handler := logRequests(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, r, http.StatusNotFound, codeMatchNotFound, "Partido no encontrado")
+	}))
+	req := httptest.NewRequest(http.MethodGet, "/api/matches/99?expand=events", nil)
+	req.Header.Set(requestIDHeader, "solicitud-1")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	p := decodeProblem(t, rec, http.StatusNotFound, codeMatchNotFound)
+	want := Problem{Type: "about:blank", Title: "Not Found", Status: 404, Code: codeMatchNotFound, Detail: "Partido no encontrado", Instance: "/api/matches/99", RequestID: "solicitud-1"}
+	if !reflect.DeepEqual(p, want) {
+		t.Errorf("problema =\n%+v\nse esperaba\n%+v", p, want)
+	}
+	if got := rec.Header().Get("X-Content-Type-Options"); got != "nosniff" {
+		t.Errorf("X-Content-Type-Options = %q, se esperaba nosniff", got)
+	}
+}
+
+func TestValidationProblem(t *testing.T) {
+	one := []FieldError{{"minute", fieldInvalidFormat, "Formato de tiempo inválido. Usa MM:SS"}}
+	if p := validationProblem(one); p.Status != http.StatusBadRequest || p.Code != codeValidationFailed || p.Detail != one[0].Message || !reflect.DeepEqual(p.Errors, one) {
+		t.Errorf("un campo: %+v", p)
+	}
+
+	two := append(one, FieldError{"player", fieldRequired, "El jugador es obligatorio"})
+	if p := validationProblem(two); p.Detail != "La solicitud tiene campos inválidos" || !reflect.DeepEqual(p.Errors, two) {
+		t.Errorf("dos campos: %+v", p)
+	}
+}
+
+func TestHandlerProblems(t *testing.T) {
+	s := useMemoryStore(t)
+	teams := createTestTeams(t, s, "Athletic", "Betis")
+	matchID := strconv.Itoa(createTestMatch(t, s, teams[0], teams[1], "2025-01-10", 0))
+	validMatch := `{"homeTeamId":` + strconv.Itoa(teams[0]) + `,"awayTeamId":` + strconv.Itoa(teams[1]) + `,"matchDate":"2025-02-01"}`
+
+	tests := []struct {
+		name    string
+		route   string
+		handler http.HandlerFunc
+		method  string
+		target  string
+		body    string
+		status  int
+		code    string
+	}{
+		{"partido inexistente", "/api/matches/{id}", getMatch, http.MethodGet, "/api/matches/99", "", http.StatusNotFound, codeMatchNotFound},
+		{"actualizar partido inexistente", "/api/matches/{id}", updateMatch, http.MethodPut, "/api/matches/99", validMatch, http.StatusNotFound, codeMatchNotFound},
+		{"actualizar con JSON inválido", "/api/matches/{id}", updateMatch, http.MethodPut, "/api/matches/" + matchID, `{"matchDate":`, http.StatusBadRequest, codeInvalidJSON},
+		{"actualizar sin campos", "/api/matches/{id}", updateMatch, http.MethodPut, "/api/matches/" + matchID, `{}`, http.StatusBadRequest, codeValidationFailed},
+		{"eliminar partido inexistente", "/api/matches/{id}", deleteMatch, http.MethodDelete, "/api/matches/99", "", http.StatusNotFound, codeMatchNotFound},
+		{"tiempo extra inválido", "/api/matches/{id}/extratime", setExtraTime, http.MethodPatch, "/api/matches/" + matchID + "/extratime", `{"extraTime":"5 min"}`, http.StatusBadRequest, codeValidationFailed},
+		{"gol inexistente", "/api/matches/{id}/goals/{eventId}", getGoal, http.MethodGet, "/api/matches/" + matchID + "/goals/99", "", http.StatusNotFound, codeEventNotFound},
+		{"corregir gol inexistente", "/api/matches/{id}/goals/{eventId}", updateGoal, http.MethodPut, "/api/matches/" + matchID + "/goals/99", `{"teamId":` + strconv.Itoa(teams[0]) + `,"player":"Williams","minute":"10:00"}`, http.StatusNotFound, codeEventNotFound},
+		{"equipo inexistente", "/api/teams/{id}", getTeam, http.MethodGet, "/api/teams/99", "", http.StatusNotFound, codeTeamNotFound},
+		{"equipo con partidos", "/api/teams/{id}", deleteTeam, http.MethodDelete, "/api/teams/" + strconv.Itoa(teams[0]), "", http.StatusConflict, codeTeamHasMatches},
+		{"temporada inexistente", "/api/seasons/{id}", getSeason, http.MethodGet, "/api/seasons/99", "", http.StatusNotFound, codeSeasonNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body))
+			p := decodeProblem(t, serveRoute(tt.route, tt.handler, req), tt.status, tt.code)
+			if p.Instance != req.URL.Path {
+				t.Errorf("instance = %q, se esperaba %q", p.Instance, req.URL.Path)
+			}
+		})
+	}
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Clases de solicitudes con presupuesto propio: las consultas no gastan el de las escrituras
+const (
+	rateClassRead  = "read"  // GET y HEAD
+	rateClassWrite = "write" // POST, PUT, PATCH y DELETE
+)
+
+// rateLimitEvictInterval es cada cuánto se descartan los baldes de los clientes inactivos
+const rateLimitEvictInterval = time.Minute
+
+// Limitadores de las consultas y de las escrituras. Son nil con rate_limit.enabled en false.
+var readLimiter, writeLimiter *rateLimiter
+
+// tokenBucket es el balde de un cliente: cada solicitud gasta un token y los tokens se
+// recuperan a ritmo constante hasta llenar el balde
+type tokenBucket struct {
+	tokens float64
+	last   time.Time // Última vez que se recalcularon los tokens
+}
+
+// rateLimiter limita las solicitudes de cada cliente con un balde de tokens en memoria.
+// rate es la cantidad de solicitudes por segundo que se recuperan y burst el tamaño del
+// balde, es decir, cuántas solicitudes seguidas se admiten después de un rato sin actividad.
+type rateLimiter struct {
+	class string
+	rate  float64
+	burst int
+
+	mu      sync.Mutex
+	buckets map[string]*tokenBucket
+}
+
+// rateDecision es el resultado de una solicitud en el limitador
+type rateDecision struct {
+	Allowed    bool
+	Remaining  int           // Tokens que quedan después de la solicitud
+	RetryAfter time.Duration // Espera hasta el próximo token si se rechazó
+	Reset      time.Duration // Espera hasta que el balde vuelva a estar lleno
+}
+
+// newRateLimiter crea un limitador de rate solicitudes por segundo con ráfagas de burst
+func newRateLimiter(class string, rate float64, burst int) *rateLimiter {
+	return &rateLimiter{class: class, rate: rate, burst: burst, buckets: make(map[string]*tokenBucket)}
+}
+
+// refill suma los tokens recuperados desde la última solicitud, sin pasar del tamaño del balde
+func (l *rateLimiter) refill(b *tokenBucket, now time.Time) {
+	if elapsed := now.Sub(b.last).Seconds(); elapsed > 0 {
+		b.tokens = math.Min(float64(l.burst), b.tokens+elapsed*l.rate)
+		b.last = now
+	}
+}
+
+// allow gasta un token del cliente key si le queda alguno.
+// Los clientes nuevos empiezan con el balde lleno.
+func (l *rateLimiter) allow(key string, now time.Time) rateDecision {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &tokenBucket{tokens: float64(l.burst), last: now}
+		l.buckets[key] = b
+	}
+	l.refill(b, now)
+
+	var d rateDecision
+	if b.tokens >= 1 {
+		b.tokens--
+		d.Allowed = true
+	} else {
+		d.RetryAfter = l.wait(1 - b.tokens)
+	}
+	d.Remaining = int(b.tokens)
+	d.Reset = l.wait(float64(l.burst) - b.tokens)
+	return d
+}
+
+// wait es el tiempo que tarda en recuperarse la cantidad de tokens indicada
+func (l *rateLimiter) wait(tokens float64) time.Duration {
+	return time.Duration(tokens / l.rate * float64(time.Second))
+}
+
+// evict descarta los baldes que ya se llenaron: un cliente que vuelve empieza igual con el
+// balde lleno, así que no hace falta recordarlo. Devuelve cuántos quedan.
+func (l *rateLimiter) evict(now time.Time) int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for key, b := range l.buckets {
+		l.refill(b, now)
+		if b.tokens >= float64(l.burst) {
+			delete(l.buckets, key)
+		}
+	}
+	return len(l.buckets)
+}
+
+// run descarta periódicamente los baldes de los clientes inactivos para que el mapa
+// no crezca con cada IP que alguna vez hizo una solicitud
+func (l *rateLimiter) run(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for now := range ticker.C {
+		rateLimitClients.WithLabelValues(l.class).Set(float64(l.evict(now)))
+	}
+}
+
+// limitRequest cuenta la solicitud contra el presupuesto de key y agrega los encabezados
+// X-RateLimit-*. Si no le quedan tokens responde 429 con Retry-After y devuelve false.
+func limitRequest(w http.ResponseWriter, r *http.Request, key string) bool {
+	l := writeLimiter
+	if r.Method == http.MethodGet || r.Method == http.MethodHead {
+		l = readLimiter
+	}
+	if l == nil {
+		return true
+	}
+
+	d := l.allow(key, time.Now())
+	h := w.Header()
+	h.Set("X-RateLimit-Limit", strconv.Itoa(l.burst))
+	h.Set("X-RateLimit-Remaining", strconv.Itoa(d.Remaining))
+	h.Set("X-RateLimit-Reset", strconv.Itoa(ceilSeconds(d.Reset)))
+	if d.Allowed {
+		return true
+	}
+
+	retry := ceilSeconds(d.RetryAfter)
+	h.Set("Retry-After", strconv.Itoa(retry))
+	rateLimited.WithLabelValues(l.class).Inc()
+	writeError(w, r, http.StatusTooManyRequests, codeRateLimited,
+		fmt.Sprintf("Demasiadas solicitudes; vuelve a intentar en %d s", retry))
+	return false
+}
+
+// ceilSeconds redondea d hacia arriba a segundos enteros, como se envía en los encabezados
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
+
+// clientIP devuelve la IP del cliente. Con rate_limit.trust_proxy se toma la última de
+// X-Forwarded-For, que es la que agregó el proxy; las anteriores las puede escribir el cliente.
+func clientIP(r *http.Request) string {
+	if config.RateLimit.TrustProxy {
+		if values := r.Header.Values("X-Forwarded-For"); len(values) > 0 {
+			hops := strings.Split(values[len(values)-1], ",")
+			if ip := strings.TrimSpace(hops[len(hops)-1]); ip != "" {
+				return ip
+			}
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
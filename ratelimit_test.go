@@ -0,0 +1,139 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRateLimiterAllow(t *testing.T) {
+	// 2 solicitudes por segundo con ráfagas de 3
+	l := newRateLimiter(rateClassRead, 2, 3)
+	start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	steps := []struct {
+		after time.Duration // Desde start
+		key   string
+		want  rateDecision
+	}{
+		// Un cliente nuevo empieza con el balde lleno
+		{0, "a", rateDecision{Allowed: true, Remaining: 2, Reset: 500 * time.Millisecond}},
+		{0, "a", rateDecision{Allowed: true, Remaining: 1, Reset: time.Second}},
+		{0, "a", rateDecision{Allowed: true, Remaining: 0, Reset: 1500 * time.Millisecond}},
+		{0, "a", rateDecision{RetryAfter: 500 * time.Millisecond, Reset: 1500 * time.Millisecond}},
+		// Otro cliente tiene su propio balde
+		{0, "b", rateDecision{Allowed: true, Remaining: 2, Reset: 500 * time.Millisecond}},
+		// A mitad de camino todavía falta medio token
+		{250 * time.Millisecond, "a", rateDecision{RetryAfter: 250 * time.Millisecond, Reset: 1250 * time.Millisecond}},
+		{500 * time.Millisecond, "a", rateDecision{Allowed: true, Remaining: 0, Reset: 1500 * time.Millisecond}},
+		// Después de un rato sin actividad el balde no pasa de burst
+		{time.Minute, "a", rateDecision{Allowed: true, Remaining: 2, Reset: 500 * time.Millisecond}},
+	}
+	for i, step := range steps {
+		if got := l.allow(step.key, start.Add(step.after)); got != step.want {
+			t.Errorf("paso %d (%s a los %v): %+v, se esperaba %+v", i, step.key, step.after, got, step.want)
+		}
+	}
+}
+
+func TestRateLimiterEvict(t *testing.T) {
+	l := newRateLimiter(rateClassWrite, 1, 2)
+	start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	l.allow("a", start)
+	l.allow("b", start)
+	l.allow("b", start)
+
+	// A los 1.5s el balde de a ya se llenó y el de b todavía no
+	if n := l.evict(start.Add(1500 * time.Millisecond)); n != 1 {
+		t.Errorf("evict dejó %d baldes, se esperaba 1", n)
+	}
+	if _, ok := l.buckets["b"]; !ok {
+		t.Error("se descartó el balde de b antes de llenarse")
+	}
+
+	// Un cliente descartado vuelve con el balde lleno
+	if d := l.allow("a", start.Add(2*time.Second)); !d.Allowed || d.Remaining != 1 {
+		t.Errorf("a después de evict: %+v", d)
+	}
+	if n := l.evict(start.Add(time.Hour)); n != 0 {
+		t.Errorf("evict dejó %d baldes con todos llenos", n)
+	}
+}
+
+// useRateLimits deja los limitadores indicados (nil para no limitar) y restaura los anteriores al terminar la prueba
+func useRateLimits(t *testing.T, read, write *rateLimiter) {
+	t.Helper()
+	prevRead, prevWrite := readLimiter, writeLimiter
+	readLimiter, writeLimiter = read, write
+	t.Cleanup(func() { readLimiter, writeLimiter = prevRead, prevWrite })
+}
+
+func TestLimitRequest(t *testing.T) {
+	useRateLimits(t, newRateLimiter(rateClassRead, 0.5, 2), nil)
+	useAuth(t, Auth{})
+	ok := func(w http.ResponseWriter, r *http.Request) {}
+
+	get := func(remoteAddr string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/matches", nil)
+		req.RemoteAddr = remoteAddr
+		return serveRoute("/api/matches", require(roleViewer, ok), req)
+	}
+	for i, remaining := range []string{"1", "0"} {
+		rec := get("192.0.2.1:1234")
+		if rec.Code != http.StatusOK {
+			t.Fatalf("solicitud %d: código %d", i+1, rec.Code)
+		}
+		if h := rec.Header(); h.Get("X-RateLimit-Limit") != "2" || h.Get("X-RateLimit-Remaining") != remaining {
+			t.Errorf("solicitud %d: límite %s restantes %s, se esperaba 2 y %s", i+1, h.Get("X-RateLimit-Limit"), h.Get("X-RateLimit-Remaining"), remaining)
+		}
+	}
+
+	// Sin tokens: 429 con la espera hasta el próximo token (2s a 0.5 por segundo) y hasta llenar el balde (4s)
+	rec := get("192.0.2.1:5678")
+	decodeProblem(t, rec, http.StatusTooManyRequests, codeRateLimited)
+	if h := rec.Header(); h.Get("Retry-After") != "2" || h.Get("X-RateLimit-Reset") != "4" {
+		t.Errorf("Retry-After %s y X-RateLimit-Reset %s, se esperaba 2 y 4", h.Get("Retry-After"), h.Get("X-RateLimit-Reset"))
+	}
+
+	// Otra IP tiene su propio presupuesto, y las escrituras sin limitador no se limitan
+	if rec := get("192.0.2.2:1234"); rec.Code != http.StatusOK {
+		t.Errorf("otra IP: código %d", rec.Code)
+	}
+	req := httptest.NewRequest(http.MethodPost, "/api/matches", nil)
+	req.RemoteAddr = "192.0.2.1:1234"
+	if rec := serveRoute("/api/matches", require(roleAdmin, ok), req); rec.Code != http.StatusOK || rec.Header().Get("X-RateLimit-Limit") != "" {
+		t.Errorf("escritura sin limitador: código %d, límite %q", rec.Code, rec.Header().Get("X-RateLimit-Limit"))
+	}
+}
+
+func TestClientIP(t *testing.T) {
+	prev := config.RateLimit
+	t.Cleanup(func() { config.RateLimit = prev })
+
+	tests := []struct {
+		trustProxy bool
+		forwarded  []string
+		want       string
+	}{
+		{false, nil, "192.0.2.1"},
+		// Sin trust_proxy el encabezado lo puede falsificar cualquiera
+		{false, []string{"203.0.113.9"}, "192.0.2.1"},
+		{true, nil, "192.0.2.1"},
+		// Solo la última IP la agregó el proxy
+		{true, []string{"198.51.100.7, 203.0.113.9"}, "203.0.113.9"},
+		{true, []string{"198.51.100.7", "203.0.113.9"}, "203.0.113.9"},
+	}
+	for _, tt := range tests {
+		config.RateLimit.TrustProxy = tt.trustProxy
+		req := httptest.NewRequest(http.MethodGet, "/api/matches", nil)
+		req.RemoteAddr = "192.0.2.1:1234"
+		for _, v := range tt.forwarded {
+			req.Header.Add("X-Forwarded-For", v)
+		}
+		if got := clientIP(req); got != tt.want {
+			t.Errorf("clientIP(trust_proxy=%v, %q) = %s, se esperaba %s", tt.trustProxy, tt.forwarded, got, tt.want)
+		}
+	}
+}
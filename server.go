@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// runServer atiende solicitudes hasta recibir SIGINT o SIGTERM y luego apaga el servidor
+// de forma ordenada:
+//
+//  1. /readyz empieza a responder 503 y se sigue atendiendo normalmente durante ShutdownDelay,
+//     para que el balanceador lo detecte y deje de enviar tráfico antes de cerrar el puerto.
+//     Una segunda señal termina la espera.
+//  2. Se dejan de aceptar conexiones y se cierran los streams SSE y WebSocket abiertos,
+//     que de otro modo nunca terminarían.
+//  3. Se esperan las solicitudes en curso hasta ShutdownTimeout; las que sigan
+//     abiertas después se cortan.
+//  4. Se detiene la entrega de webhooks.
+func runServer(srv *http.Server) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Los streams se cierran al comenzar el apagado, en paralelo con la espera de Shutdown.
+	// Primero el hub, para que los clientes WebSocket reciban el mensaje de cierre antes
+	// de que el broker cierre su suscripción y con ella la conexión.
+	srv.RegisterOnShutdown(func() {
+		hub.Close()
+		broker.Close()
+	})
+
+	errc := make(chan error, 1)
+	go func() {
+		errc <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errc:
+		// No se pudo abrir el puerto u otro error al escuchar
+		return err
+	case <-ctx.Done():
+	}
+	stop()
+
+	slog.Info("apagando servidor", "delay", config.ShutdownDelay.String(), "timeout", config.ShutdownTimeout.String())
+	shuttingDown.Store(true)
+
+	// El puerto sigue abierto mientras el balanceador deja de enviar tráfico
+	if config.ShutdownDelay > 0 {
+		drainCtx, stopDrain := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		select {
+		case <-time.After(config.ShutdownDelay):
+		case <-drainCtx.Done():
+			slog.Warn("segunda señal de apagado; se omite la espera")
+		}
+		stopDrain()
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), config.ShutdownTimeout)
+	defer cancel()
+	err := srv.Shutdown(shutdownCtx)
+	if errors.Is(err, context.DeadlineExceeded) {
+		slog.Warn("solicitudes sin terminar al vencer el plazo de apagado; se cortan")
+		err = srv.Close()
+	}
+
+	dispatcher.Close()
+	slog.Info("servidor detenido")
+	return err
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// sseHeartbeat es el intervalo con el que se envía un comentario vacío para mantener viva la conexión
+const sseHeartbeat = 15 * time.Second
+
+// @Summary Stream de eventos de un partido
+// @Description Envía por Server-Sent Events los goles, tarjetas, cambios de tiempo extra, de estado y de datos del partido.
+// @Description Para reanudar sin perder eventos, enviar el encabezado Last-Event-ID (o el parámetro lastEventId).
+// @Description Si el historial no alcanza para reanudar, se envía un evento "resync" y el cliente debe volver a consultar el partido.
+// @Tags stream
+// @Produce text/event-stream
+// @Param id path int true "ID del partido"
+// @Param Last-Event-ID header string false "ID del último evento recibido"
+// @Param access_token query string false "Token JWT, para los clientes que no pueden enviar Authorization"
+// @Success 200 {object} LiveEvent
+// @Failure 401 {object} Problem
+// @Failure 404 {object} Problem
+// @Failure 429 {object} Problem
+// @Failure 500 {object} Problem
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/matches/{id}/stream [get]
+func streamMatch(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		writeError(w, r, http.StatusNotFound, codeMatchNotFound, "Partido no encontrado")
+		return
+	}
+
+	// Verificar que el partido exista antes de abrir el stream
+	if _, err := store.Match(id); err == errNotFound {
+		writeError(w, r, http.StatusNotFound, codeMatchNotFound, "Partido no encontrado")
+		return
+	} else if err != nil {
+		dbError(w, r, err)
+		return
+	}
+
+	serveSSE(w, r, id)
+}
+
+// @Summary Stream de eventos de la liga
+// @Description Envía por Server-Sent Events los cambios de todos los partidos.
+// @Description Admite Last-Event-ID para reanudar igual que el stream de un partido.
+// @Tags stream
+// @Produce text/event-stream
+// @Param Last-Event-ID header string false "ID del último evento recibido"
+// @Param access_token query string false "Token JWT, para los clientes que no pueden enviar Authorization"
+// @Success 200 {object} LiveEvent
+// @Failure 401 {object} Problem
+// @Failure 429 {object} Problem
+// @Failure 500 {object} Problem
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/stream [get]
+func streamLeague(w http.ResponseWriter, r *http.Request) {
+	serveSSE(w, r, 0)
+}
+
+// serveSSE mantiene abierta la conexión y escribe los eventos del partido matchID
+// (o de todos si es 0) hasta que el cliente se desconecte o el broker se cierre
+func serveSSE(w http.ResponseWriter, r *http.Request, matchID int) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, r, http.StatusInternalServerError, codeStreamingUnsupported, "Streaming no soportado")
+		return
+	}
+
+	// El ID del último evento puede venir del encabezado estándar o de la URL
+	lastID, _ := strconv.ParseUint(r.Header.Get("Last-Event-ID"), 10, 64)
+	if lastID == 0 {
+		lastID, _ = strconv.ParseUint(r.URL.Query().Get("lastEventId"), 10, 64)
+	}
+
+	// El stream dura más que los timeouts del servidor, que cortarían la conexión
+	rc := http.NewResponseController(w)
+	rc.SetReadDeadline(time.Time{})
+	rc.SetWriteDeadline(time.Time{})
+
+	sub, backlog, complete := broker.Subscribe(matchID, lastID)
+	defer broker.Unsubscribe(sub)
+	liveConnections.WithLabelValues("sse").Inc()
+	defer liveConnections.WithLabelValues("sse").Dec()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	// Indicar al navegador cuánto esperar antes de reconectarse
+	fmt.Fprint(w, "retry: 3000\n\n")
+
+	// Avisar al cliente que se perdieron eventos y debe recargar el estado completo
+	if !complete {
+		fmt.Fprint(w, "event: resync\ndata: {}\n\n")
+	}
+
+	for _, ev := range backlog {
+		writeSSE(w, ev)
+	}
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(sseHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case ev, ok := <-sub.ch:
+			// Canal cerrado: el cliente se atrasó o el servidor se está apagando
+			if !ok {
+				return
+			}
+			writeSSE(w, ev)
+			flusher.Flush()
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": ping\n\n")
+			flusher.Flush()
+		}
+	}
+}
+
+// writeSSE escribe un evento en formato Server-Sent Events
+func writeSSE(w http.ResponseWriter, ev LiveEvent) {
+	data, err := json.Marshal(ev)
+	if err != nil {
+		return
+	}
+	fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", ev.ID, ev.Type, data)
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// useBroker deja un broker nuevo como broker de los handlers y restaura el anterior al terminar la prueba
+func useBroker(t *testing.T, size int) *Broker {
+	t.Helper()
+	prev := broker
+	broker = newBroker(size)
+	t.Cleanup(func() { broker = prev })
+	return broker
+}
+
+// sseStream es una conexión abierta a un stream de eventos
+type sseStream struct {
+	t      *testing.T
+	reader *bufio.Reader
+}
+
+// openSSE abre el stream de la URL con el encabezado Last-Event-ID indicado (vacío para omitirlo)
+func openSSE(t *testing.T, url, lastEventID string) *sseStream {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		resp.Body.Close()
+		cancel()
+	})
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("stream respondió %d %s", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+	return &sseStream{t: t, reader: bufio.NewReader(resp.Body)}
+}
+
+// next devuelve las líneas del siguiente mensaje, sin la línea vacía que lo termina
+func (s *sseStream) next() []string {
+	s.t.Helper()
+	var lines []string
+	for {
+		line, err := s.reader.ReadString('\n')
+		if err != nil {
+			s.t.Fatalf("el stream terminó después de %q: %v", lines, err)
+		}
+		line = strings.TrimSuffix(line, "\n")
+		if line == "" {
+			return lines
+		}
+		lines = append(lines, line)
+	}
+}
+
+// expect lee el siguiente mensaje y verifica su ID y tipo; en una resincronización id es 0
+func (s *sseStream) expect(id uint64, eventType string) {
+	s.t.Helper()
+	lines := s.next()
+	want := []string{"event: " + eventType}
+	if id != 0 {
+		want = append([]string{"id: " + strconv.FormatUint(id, 10)}, want...)
+	}
+	if len(lines) != len(want)+1 || strings.Join(lines[:len(want)], "\n") != strings.Join(want, "\n") || !strings.HasPrefix(lines[len(want)], "data: ") {
+		s.t.Fatalf("mensaje %q, se esperaba %q seguido de data", lines, want)
+	}
+}
+
+func TestStreamResume(t *testing.T) {
+	s := useMemoryStore(t)
+	b := useBroker(t, 3)
+	teams := createTestTeams(t, s, "Athletic", "Betis")
+	matchID := createTestMatch(t, s, teams[0], teams[1], "2025-01-10", 0)
+	otherID := createTestMatch(t, s, teams[1], teams[0], "2025-01-17", 0)
+
+	r := mux.NewRouter()
+	r.HandleFunc("/api/matches/{id}/stream", streamMatch)
+	r.HandleFunc("/api/stream", streamLeague)
+	srv := httptest.NewServer(r)
+	defer srv.Close()
+	matchURL := srv.URL + "/api/matches/" + strconv.Itoa(matchID) + "/stream"
+
+	b.Publish(matchID, LiveGoal, nil)       // 1
+	b.Publish(otherID, LiveGoal, nil)       // 2
+	b.Publish(matchID, LiveYellowCard, nil) // 3
+
+	t.Run("Last-Event-ID", func(t *testing.T) {
+		// Se reciben los eventos del partido posteriores al 1 y después los nuevos
+		stream := openSSE(t, matchURL, "1")
+		if lines := stream.next(); len(lines) != 1 || lines[0] != "retry: 3000" {
+			t.Fatalf("primer mensaje %q, se esperaba el retry", lines)
+		}
+		stream.expect(3, LiveYellowCard)
+
+		b.Publish(otherID, LiveRedCard, nil) // 4, de otro partido
+		b.Publish(matchID, LiveRedCard, nil) // 5
+		stream.expect(5, LiveRedCard)
+	})
+
+	t.Run("lastEventId", func(t *testing.T) {
+		// El parámetro sirve igual que el encabezado para el stream de la liga
+		stream := openSSE(t, srv.URL+"/api/stream?lastEventId=3", "")
+		stream.next()
+		stream.expect(4, LiveRedCard)
+		stream.expect(5, LiveRedCard)
+	})
+
+	t.Run("historial recortado", func(t *testing.T) {
+		// El historial guarda 3..5: desde el 1 se perdió el 2 y el cliente debe resincronizar
+		stream := openSSE(t, matchURL, "1")
+		stream.next()
+		stream.expect(0, "resync")
+		stream.expect(3, LiveYellowCard)
+		stream.expect(5, LiveRedCard)
+	})
+
+	t.Run("servidor reiniciado", func(t *testing.T) {
+		stream := openSSE(t, matchURL, "99")
+		stream.next()
+		stream.expect(0, "resync")
+	})
+}
+
+func TestStreamMatchNotFound(t *testing.T) {
+	useMemoryStore(t)
+	rec := serveRoute("/api/matches/{id}/stream", streamMatch, httptest.NewRequest(http.MethodGet, "/api/matches/99/stream", nil))
+	decodeProblem(t, rec, http.StatusNotFound, codeMatchNotFound)
+}
@@ -0,0 +1,210 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+)
+
+// Standing representa la fila de un equipo en la tabla de clasificación
+// @description Modelo que contiene las estadísticas acumuladas de un equipo en la liga
+// @property position, team, played, won, drawn, lost, goalsFor, goalsAgainst, goalDifference, points
+type Standing struct {
+	Position       int    `json:"position"`
+	Team           string `json:"team"`
+	Played         int    `json:"played"`
+	Won            int    `json:"won"`
+	Drawn          int    `json:"drawn"`
+	Lost           int    `json:"lost"`
+	GoalsFor       int    `json:"goalsFor"`
+	GoalsAgainst   int    `json:"goalsAgainst"`
+	GoalDifference int    `json:"goalDifference"`
+	Points         int    `json:"points"`
+}
+
+// matchResult representa el marcador final de un partido terminado
+// y se usa como insumo para calcular la clasificación
+type matchResult struct {
+	HomeTeam  string
+	AwayTeam  string
+	HomeGoals int
+	AwayGoals int
+}
+
+// @Summary Obtener tabla de clasificación
+// @Description Calcula la clasificación a partir de los partidos terminados y sus goles.
+// @Description Sin competition ni seasonId suma los partidos de todas las competiciones.
+// @Tags standings
+// @Accept json
+// @Produce json
+// @Param competition query string false "ID o nombre de la competición"
+// @Param seasonId query int false "ID de la temporada"
+// @Success 200 {array} Standing
+// @Failure 400 {object} Problem
+// @Failure 401 {object} Problem
+// @Failure 429 {object} Problem
+// @Failure 500 {object} Problem
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/standings [get]
+func getStandings(w http.ResponseWriter, r *http.Request) {
+	sc, errs, err := parseMatchScope(r)
+	if err == errSeasonNotFound {
+		writeSeasonNotFound(w, r)
+		return
+	} else if err != nil {
+		dbError(w, r, err)
+		return
+	} else if errs != nil {
+		writeValidation(w, r, errs...)
+		return
+	}
+
+	// Obtener los resultados de los partidos terminados de la competición o temporada.
+	// Los programados, en juego, aplazados o cancelados no suman para la clasificación.
+	results, err := store.FinishedResults(sc)
+	if err != nil {
+		dbError(w, r, err)
+		return
+	}
+
+	// Calcular la tabla y devolverla como respuesta JSON
+	json.NewEncoder(w).Encode(computeStandings(results))
+}
+
+// computeStandings construye la tabla de clasificación a partir de los resultados.
+// Asigna 3 puntos por victoria, 1 por empate y 0 por derrota, y ordena según
+// los criterios de desempate de La Liga (ver sortStandings).
+func computeStandings(results []matchResult) []Standing {
+	table := tally(results, nil)
+
+	// Pasar el mapa a un slice para poder ordenarlo
+	standings := make([]Standing, 0, len(table))
+	for _, s := range table {
+		standings = append(standings, *s)
+	}
+
+	sortStandings(standings, results)
+
+	// Asignar la posición final de cada equipo
+	for i := range standings {
+		standings[i].Position = i + 1
+	}
+	return standings
+}
+
+// tally acumula las estadísticas de cada equipo a partir de los resultados.
+// Si only no es nil, solo se consideran los partidos jugados entre equipos de ese conjunto,
+// lo que permite construir la mini-liga del enfrentamiento directo.
+func tally(results []matchResult, only map[string]bool) map[string]*Standing {
+	table := make(map[string]*Standing)
+
+	// get devuelve la fila del equipo, creándola si todavía no existe
+	get := func(team string) *Standing {
+		s, ok := table[team]
+		if !ok {
+			s = &Standing{Team: team}
+			table[team] = s
+		}
+		return s
+	}
+
+	for _, res := range results {
+		if only != nil && (!only[res.HomeTeam] || !only[res.AwayTeam]) {
+			continue
+		}
+
+		home := get(res.HomeTeam)
+		away := get(res.AwayTeam)
+
+		home.Played++
+		away.Played++
+		home.GoalsFor += res.HomeGoals
+		home.GoalsAgainst += res.AwayGoals
+		away.GoalsFor += res.AwayGoals
+		away.GoalsAgainst += res.HomeGoals
+
+		switch {
+		case res.HomeGoals > res.AwayGoals:
+			home.Won++
+			home.Points += 3
+			away.Lost++
+		case res.HomeGoals < res.AwayGoals:
+			away.Won++
+			away.Points += 3
+			home.Lost++
+		default:
+			home.Drawn++
+			away.Drawn++
+			home.Points++
+			away.Points++
+		}
+	}
+
+	for _, s := range table {
+		s.GoalDifference = s.GoalsFor - s.GoalsAgainst
+	}
+	return table
+}
+
+// sortStandings ordena la tabla según los criterios de desempate de La Liga:
+//  1. Puntos
+//  2. Puntos en los enfrentamientos directos entre los equipos empatados
+//  3. Diferencia de goles en los enfrentamientos directos
+//  4. Diferencia de goles general
+//  5. Goles a favor
+//
+// Como último recurso se ordena alfabéticamente para que el resultado sea estable.
+func sortStandings(standings []Standing, results []matchResult) {
+	// Primero ordenar solo por puntos para agrupar a los equipos empatados
+	sort.SliceStable(standings, func(i, j int) bool {
+		return standings[i].Points > standings[j].Points
+	})
+
+	// Recorrer cada grupo de equipos con los mismos puntos
+	for start := 0; start < len(standings); {
+		end := start + 1
+		for end < len(standings) && standings[end].Points == standings[start].Points {
+			end++
+		}
+
+		group := standings[start:end]
+		if len(group) > 1 {
+			// Construir la mini-liga con los partidos entre los equipos empatados
+			tied := make(map[string]bool, len(group))
+			for _, s := range group {
+				tied[s.Team] = true
+			}
+			h2h := tally(results, tied)
+
+			sort.SliceStable(group, func(i, j int) bool {
+				a, b := group[i], group[j]
+				ha, hb := h2hRow(h2h, a.Team), h2hRow(h2h, b.Team)
+				if ha.Points != hb.Points {
+					return ha.Points > hb.Points
+				}
+				if ha.GoalDifference != hb.GoalDifference {
+					return ha.GoalDifference > hb.GoalDifference
+				}
+				if a.GoalDifference != b.GoalDifference {
+					return a.GoalDifference > b.GoalDifference
+				}
+				if a.GoalsFor != b.GoalsFor {
+					return a.GoalsFor > b.GoalsFor
+				}
+				return a.Team < b.Team
+			})
+		}
+
+		start = end
+	}
+}
+
+// h2hRow devuelve las estadísticas de enfrentamiento directo de un equipo,
+// o una fila vacía si no jugó contra ninguno de los equipos empatados
+func h2hRow(table map[string]*Standing, team string) Standing {
+	if s, ok := table[team]; ok {
+		return *s
+	}
+	return Standing{Team: team}
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+// standingsOrder devuelve los equipos en el orden de la tabla
+func standingsOrder(standings []Standing) []string {
+	teams := make([]string, len(standings))
+	for i, s := range standings {
+		teams[i] = s.Team
+	}
+	return teams
+}
+
+func TestComputeStandingsRows(t *testing.T) {
+	results := []matchResult{
+		{"Athletic", "Betis", 3, 1},
+		{"Betis", "Celta", 2, 2},
+		{"Celta", "Athletic", 1, 0},
+	}
+
+	want := []Standing{
+		{Position: 1, Team: "Celta", Played: 2, Won: 1, Drawn: 1, Lost: 0, GoalsFor: 3, GoalsAgainst: 2, GoalDifference: 1, Points: 4},
+		{Position: 2, Team: "Athletic", Played: 2, Won: 1, Drawn: 0, Lost: 1, GoalsFor: 3, GoalsAgainst: 2, GoalDifference: 1, Points: 3},
+		{Position: 3, Team: "Betis", Played: 2, Won: 0, Drawn: 1, Lost: 1, GoalsFor: 3, GoalsAgainst: 5, GoalDifference: -2, Points: 1},
+	}
+	if got := computeStandings(results); !reflect.DeepEqual(got, want) {
+		t.Errorf("computeStandings =\n%+v\nse esperaba\n%+v", got, want)
+	}
+
+	if got := computeStandings(nil); len(got) != 0 {
+		t.Errorf("computeStandings sin partidos = %+v, se esperaba una tabla vacía", got)
+	}
+}
+
+func TestSortStandingsTieBreaks(t *testing.T) {
+	tests := []struct {
+		name    string
+		results []matchResult
+		want    []string
+	}{
+		{
+			// Athletic y Betis empatan a 4 puntos; Betis tiene mejor diferencia de goles general,
+			// pero Athletic ganó el partido entre ambos. Celta y Deportivo no se enfrentaron:
+			// los separa la diferencia de goles general.
+			name: "puntos del enfrentamiento directo antes que la diferencia de goles",
+			results: []matchResult{
+				{"Athletic", "Betis", 1, 0},
+				{"Betis", "Celta", 5, 0},
+				{"Athletic", "Celta", 1, 1},
+				{"Betis", "Deportivo", 0, 0},
+			},
+			want: []string{"Athletic", "Betis", "Deportivo", "Celta"},
+		},
+		{
+			// Cada uno ganó un partido del enfrentamiento directo, pero Betis por más goles
+			name: "diferencia de goles del enfrentamiento directo",
+			results: []matchResult{
+				{"Athletic", "Betis", 1, 0},
+				{"Betis", "Athletic", 3, 1},
+				{"Athletic", "Celta", 6, 0},
+				{"Betis", "Celta", 1, 0},
+			},
+			want: []string{"Betis", "Athletic", "Celta"},
+		},
+		{
+			name: "goles a favor si todo lo demás es igual",
+			results: []matchResult{
+				{"Athletic", "Betis", 2, 2},
+				{"Athletic", "Celta", 2, 0},
+				{"Betis", "Celta", 3, 1},
+			},
+			want: []string{"Betis", "Athletic", "Celta"},
+		},
+		{
+			name: "orden alfabético como último recurso",
+			results: []matchResult{
+				{"Celta", "Athletic", 1, 1},
+				{"Betis", "Deportivo", 0, 3},
+			},
+			want: []string{"Deportivo", "Athletic", "Celta", "Betis"},
+		},
+		{
+			// Triple empate a 3 puntos: la mini-liga entre los tres decide por diferencia de goles
+			name: "mini-liga de tres equipos",
+			results: []matchResult{
+				{"Athletic", "Betis", 2, 0},
+				{"Betis", "Celta", 1, 0},
+				{"Celta", "Athletic", 1, 0},
+			},
+			want: []string{"Athletic", "Celta", "Betis"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// El orden de la tabla no puede depender del orden del mapa de tally
+			for i := 0; i < 10; i++ {
+				if got := standingsOrder(computeStandings(tt.results)); !reflect.DeepEqual(got, tt.want) {
+					t.Fatalf("orden = %v, se esperaba %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestGetStandingsOnlyFinished(t *testing.T) {
+	s := useMemoryStore(t)
+	teams := createTestTeams(t, s, "Athletic", "Betis")
+
+	// Un partido terminado 2-1 y otro en juego que todavía no suma
+	finished := createTestMatch(t, s, teams[0], teams[1], "2025-01-10", 0)
+	addTestGoals(t, s, finished, teams[0], 2)
+	addTestGoals(t, s, finished, teams[1], 1)
+	finishTestMatch(t, s, finished)
+
+	live := createTestMatch(t, s, teams[1], teams[0], "2025-01-17", 0)
+	if err := s.SetMatchStatus(live, StatusScheduled, StatusLive); err != nil {
+		t.Fatal(err)
+	}
+	addTestGoals(t, s, live, teams[1], 3)
+
+	var got []Standing
+	rec := serveRoute("/api/standings", getStandings, httptest.NewRequest(http.MethodGet, "/api/standings", nil))
+	decodeResponse(t, rec, http.StatusOK, &got)
+
+	want := []Standing{
+		{Position: 1, Team: "Athletic", Played: 1, Won: 1, GoalsFor: 2, GoalsAgainst: 1, GoalDifference: 1, Points: 3},
+		{Position: 2, Team: "Betis", Played: 1, Lost: 1, GoalsFor: 1, GoalsAgainst: 2, GoalDifference: -1},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("clasificación =\n%+v\nse esperaba\n%+v", got, want)
+	}
+
+	// Una temporada inexistente es un parámetro inválido y no una tabla vacía
+	rec = serveRoute("/api/standings", getStandings, httptest.NewRequest(http.MethodGet, "/api/standings?seasonId=99", nil))
+	p := decodeProblem(t, rec, http.StatusBadRequest, codeValidationFailed)
+	if len(p.Errors) != 1 || p.Errors[0].Field != "seasonId" || p.Errors[0].Code != fieldNotFound {
+		t.Errorf("errores = %+v, se esperaba seasonId not_found", p.Errors)
+	}
+}
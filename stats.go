@@ -0,0 +1,177 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// ScorerStat representa la fila de un jugador en la tabla de goleadores
+// @description Modelo que contiene los goles acumulados de un jugador
+// @property rank, playerId, player, teamId, team, goals
+type ScorerStat struct {
+	Rank     int    `json:"rank"`
+	PlayerID int    `json:"playerId,omitempty"`
+	Player   string `json:"player"`
+	TeamID   int    `json:"teamId"`
+	Team     string `json:"team"`
+	Goals    int    `json:"goals"`
+}
+
+// DisciplineStat representa la fila de un jugador en la tabla de disciplina
+// @description Modelo que contiene las tarjetas acumuladas de un jugador
+// @property rank, playerId, player, teamId, team, yellowCards, redCards
+type DisciplineStat struct {
+	Rank        int    `json:"rank"`
+	PlayerID    int    `json:"playerId,omitempty"`
+	Player      string `json:"player"`
+	TeamID      int    `json:"teamId"`
+	Team        string `json:"team"`
+	YellowCards int    `json:"yellowCards"`
+	RedCards    int    `json:"redCards"`
+}
+
+// statsFilter contiene los filtros comunes de las tablas de estadísticas
+type statsFilter struct {
+	matchScope
+	From   string
+	To     string
+	TeamID int
+	Limit  int
+}
+
+// Límites para el parámetro limit de las estadísticas
+const (
+	defaultStatsLimit = 20
+	maxStatsLimit     = 100
+)
+
+// parseStatsFilter lee los parámetros from, to, team y limit de la URL, y la competición
+// y temporada de parseMatchScope. Devuelve el detalle de los parámetros que no son válidos.
+func parseStatsFilter(r *http.Request) (statsFilter, []FieldError, error) {
+	q := r.URL.Query()
+	f := statsFilter{From: q.Get("from"), To: q.Get("to"), Limit: defaultStatsLimit}
+
+	sc, errs, err := parseMatchScope(r)
+	if err != nil {
+		return f, nil, err
+	}
+	f.matchScope = sc
+
+	// Las fechas deben venir en el mismo formato que match_date
+	for _, d := range []struct{ field, value string }{{"from", f.From}, {"to", f.To}} {
+		if d.value == "" {
+			continue
+		}
+		if _, err := time.Parse("2006-01-02", d.value); err != nil {
+			errs = append(errs, FieldError{d.field, fieldInvalidFormat, "Formato de fecha inválido. Usa YYYY-MM-DD"})
+		}
+	}
+
+	// El equipo se puede indicar por ID o por nombre
+	if team := q.Get("team"); team != "" {
+		id, _ := strconv.Atoi(team)
+		teamID, _, err := resolveTeam(id, team)
+		if err == errTeamNotFound {
+			errs = append(errs, FieldError{"team", fieldNotFound, "Equipo no encontrado"})
+		} else if err != nil {
+			return f, nil, err
+		}
+		f.TeamID = teamID
+	}
+
+	if limit := q.Get("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 1 || n > maxStatsLimit {
+			errs = append(errs, FieldError{"limit", fieldOutOfRange, "El límite debe estar entre 1 y " + strconv.Itoa(maxStatsLimit)})
+		}
+		f.Limit = n
+	}
+
+	return f, errs, nil
+}
+
+// @Summary Tabla de goleadores
+// @Description Retorna los máximos goleadores agrupados por jugador y equipo
+// @Tags stats
+// @Accept json
+// @Produce json
+// @Param from query string false "Fecha inicial (YYYY-MM-DD)"
+// @Param to query string false "Fecha final (YYYY-MM-DD)"
+// @Param team query string false "ID o nombre del equipo"
+// @Param competition query string false "ID o nombre de la competición"
+// @Param seasonId query int false "ID de la temporada"
+// @Param limit query int false "Cantidad máxima de filas (1-100, por defecto 20)"
+// @Success 200 {array} ScorerStat
+// @Failure 400 {object} Problem
+// @Failure 401 {object} Problem
+// @Failure 429 {object} Problem
+// @Failure 500 {object} Problem
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/stats/scorers [get]
+func getTopScorers(w http.ResponseWriter, r *http.Request) {
+	f, errs, err := parseStatsFilter(r)
+	if err == errSeasonNotFound {
+		writeSeasonNotFound(w, r)
+		return
+	} else if err != nil {
+		dbError(w, r, err)
+		return
+	} else if errs != nil {
+		writeValidation(w, r, errs...)
+		return
+	}
+
+	// Agrupar los goles por jugador y equipo
+	scorers, err := store.TopScorers(f)
+	if err != nil {
+		dbError(w, r, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(scorers)
+}
+
+// @Summary Tabla de disciplina
+// @Description Retorna las tarjetas amarillas y rojas acumuladas por jugador y equipo, ordenadas por rojas y luego amarillas
+// @Tags stats
+// @Accept json
+// @Produce json
+// @Param from query string false "Fecha inicial (YYYY-MM-DD)"
+// @Param to query string false "Fecha final (YYYY-MM-DD)"
+// @Param team query string false "ID o nombre del equipo"
+// @Param competition query string false "ID o nombre de la competición"
+// @Param seasonId query int false "ID de la temporada"
+// @Param limit query int false "Cantidad máxima de filas (1-100, por defecto 20)"
+// @Success 200 {array} DisciplineStat
+// @Failure 400 {object} Problem
+// @Failure 401 {object} Problem
+// @Failure 429 {object} Problem
+// @Failure 500 {object} Problem
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/stats/discipline [get]
+func getDiscipline(w http.ResponseWriter, r *http.Request) {
+	f, errs, err := parseStatsFilter(r)
+	if err == errSeasonNotFound {
+		writeSeasonNotFound(w, r)
+		return
+	} else if err != nil {
+		dbError(w, r, err)
+		return
+	} else if errs != nil {
+		writeValidation(w, r, errs...)
+		return
+	}
+
+	// Agrupar las tarjetas amarillas y rojas por jugador y equipo
+	discipline, err := store.Discipline(f)
+	if err != nil {
+		dbError(w, r, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(discipline)
+}
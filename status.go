@@ -0,0 +1,136 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// Estados posibles de un partido
+const (
+	StatusScheduled = "scheduled"
+	StatusLive      = "live"
+	StatusHalfTime  = "half_time"
+	StatusFinished  = "finished"
+	StatusPostponed = "postponed"
+	StatusCancelled = "cancelled"
+)
+
+// allowedTransitions define a qué estados puede pasar un partido desde su estado actual.
+// Los partidos terminados o cancelados no admiten más cambios.
+var allowedTransitions = map[string][]string{
+	StatusScheduled: {StatusLive, StatusPostponed, StatusCancelled},
+	StatusLive:      {StatusHalfTime, StatusFinished},
+	StatusHalfTime:  {StatusLive},
+	StatusPostponed: {StatusScheduled, StatusCancelled},
+	StatusFinished:  {},
+	StatusCancelled: {},
+}
+
+// eventGracePeriod es el tiempo durante el cual se aceptan eventos después de que un
+// partido terminó, para registrar lo que se haya quedado pendiente al pitido final
+const eventGracePeriod = 30 * time.Minute
+
+// StatusPayload representa la carga útil para cambiar el estado de un partido
+// @description Modelo que contiene el nuevo estado de un partido
+// @property Status
+type StatusPayload struct {
+	Status string `json:"status" enums:"scheduled,live,half_time,finished,postponed,cancelled"`
+}
+
+// canTransition indica si un partido puede pasar del estado from al estado to
+func canTransition(from, to string) bool {
+	for _, next := range allowedTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// acceptsEvents indica si un partido admite el registro de goles y tarjetas.
+// Solo se aceptan eventos con el partido en juego (incluido el descanso) o
+// durante eventGracePeriod después de haber terminado.
+func acceptsEvents(status string, statusUpdatedAt time.Time) bool {
+	switch status {
+	case StatusLive, StatusHalfTime:
+		return true
+	case StatusFinished:
+		return time.Since(statusUpdatedAt) <= eventGracePeriod
+	default:
+		return false
+	}
+}
+
+// @Summary Cambiar estado del partido
+// @Description Cambia el estado de un partido respetando las transiciones permitidas:
+// @Description scheduled → live | postponed | cancelled, live → half_time | finished,
+// @Description half_time → live, postponed → scheduled | cancelled
+// @Tags matches
+// @Accept json
+// @Produce json
+// @Param id path int true "ID del partido"
+// @Param status body StatusPayload true "Nuevo estado"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} Problem
+// @Failure 401 {object} Problem
+// @Failure 403 {object} Problem
+// @Failure 404 {object} Problem
+// @Failure 409 {object} Problem
+// @Failure 429 {object} Problem
+// @Failure 500 {object} Problem
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/matches/{id}/status [patch]
+func setMatchStatus(w http.ResponseWriter, r *http.Request) {
+	matchID := pathID(r, "id")
+
+	var payload StatusPayload
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		writeInvalidJSON(w, r)
+		return
+	}
+	if payload.Status == "" {
+		writeValidation(w, r, FieldError{"status", fieldRequired, "El estado es obligatorio"})
+		return
+	}
+
+	// Validar que el estado exista
+	if _, ok := allowedTransitions[payload.Status]; !ok {
+		writeValidation(w, r, FieldError{"status", fieldInvalid, "Estado inválido"})
+		return
+	}
+
+	// Obtener el estado actual del partido
+	current, _, err := store.MatchStatus(matchID)
+	if err == errNotFound {
+		writeError(w, r, http.StatusNotFound, codeMatchNotFound, "Partido no encontrado")
+		return
+	} else if err != nil {
+		dbError(w, r, err)
+		return
+	}
+
+	// Validar que la transición esté permitida
+	if !canTransition(current, payload.Status) {
+		writeError(w, r, http.StatusConflict, codeInvalidTransition, "No se puede pasar de "+current+" a "+payload.Status)
+		return
+	}
+
+	// Actualizar el estado solo si nadie lo cambió mientras tanto
+	err = store.SetMatchStatus(matchID, current, payload.Status)
+	if err == errStatusChanged {
+		writeError(w, r, http.StatusConflict, codeStatusChanged, "El estado del partido cambió, intenta de nuevo")
+		return
+	} else if err != nil {
+		dbError(w, r, err)
+		return
+	}
+
+	// Notificar el cambio a los clientes conectados al stream
+	statusChanges.WithLabelValues(payload.Status).Inc()
+	broker.Publish(matchID, LiveStatusChanged, map[string]string{"from": current, "status": payload.Status})
+	audit(r, auditMatchStatus, matchID, map[string]string{"status": current}, map[string]string{"status": payload.Status})
+
+	json.NewEncoder(w).Encode(map[string]string{"message": "Estado actualizado correctamente", "status": payload.Status})
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCanTransition(t *testing.T) {
+	// Transiciones permitidas escritas a mano: cualquier otro par debe rechazarse
+	allowed := map[[2]string]bool{
+		{StatusScheduled, StatusLive}:      true,
+		{StatusScheduled, StatusPostponed}: true,
+		{StatusScheduled, StatusCancelled}: true,
+		{StatusLive, StatusHalfTime}:       true,
+		{StatusLive, StatusFinished}:       true,
+		{StatusHalfTime, StatusLive}:       true,
+		{StatusPostponed, StatusScheduled}: true,
+		{StatusPostponed, StatusCancelled}: true,
+	}
+	statuses := []string{StatusScheduled, StatusLive, StatusHalfTime, StatusFinished, StatusPostponed, StatusCancelled, "unknown"}
+
+	for _, from := range statuses {
+		for _, to := range statuses {
+			if got, want := canTransition(from, to), allowed[[2]string{from, to}]; got != want {
+				t.Errorf("canTransition(%s, %s) = %v, se esperaba %v", from, to, got, want)
+			}
+		}
+	}
+}
+
+func TestAcceptsEvents(t *testing.T) {
+	tests := []struct {
+		status string
+		age    time.Duration
+		want   bool
+	}{
+		{StatusScheduled, 0, false},
+		{StatusLive, 0, true},
+		{StatusLive, 3 * time.Hour, true},
+		{StatusHalfTime, 0, true},
+		{StatusFinished, 0, true},
+		{StatusFinished, 29 * time.Minute, true},
+		{StatusFinished, 31 * time.Minute, false},
+		{StatusPostponed, 0, false},
+		{StatusCancelled, 0, false},
+	}
+	for _, tt := range tests {
+		if got := acceptsEvents(tt.status, time.Now().Add(-tt.age)); got != tt.want {
+			t.Errorf("acceptsEvents(%s, hace %v) = %v, se esperaba %v", tt.status, tt.age, got, tt.want)
+		}
+	}
+}
+
+// patchStatus envía un cambio de estado al handler setMatchStatus
+func patchStatus(matchID int, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPatch, "/api/matches/"+strconv.Itoa(matchID)+"/status", strings.NewReader(body))
+	return serveRoute("/api/matches/{id}/status", setMatchStatus, req)
+}
+
+func TestSetMatchStatus(t *testing.T) {
+	s := useMemoryStore(t)
+	teams := createTestTeams(t, s, "Athletic", "Betis")
+	matchID := createTestMatch(t, s, teams[0], teams[1], "2025-01-10", 0)
+
+	tests := []struct {
+		name   string
+		id     int
+		body   string
+		status int
+		code   string
+	}{
+		{"JSON inválido", matchID, `{`, http.StatusBadRequest, codeInvalidJSON},
+		{"sin estado", matchID, `{}`, http.StatusBadRequest, codeValidationFailed},
+		{"estado desconocido", matchID, `{"status":"abandoned"}`, http.StatusBadRequest, codeValidationFailed},
+		{"partido inexistente", 99, `{"status":"live"}`, http.StatusNotFound, codeMatchNotFound},
+		{"transición no permitida", matchID, `{"status":"finished"}`, http.StatusConflict, codeInvalidTransition},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decodeProblem(t, patchStatus(tt.id, tt.body), tt.status, tt.code)
+		})
+	}
+
+	// Recorrer el partido hasta terminarlo
+	for _, next := range []string{StatusLive, StatusHalfTime, StatusLive, StatusFinished} {
+		var got map[string]string
+		decodeResponse(t, patchStatus(matchID, `{"status":"`+next+`"}`), http.StatusOK, &got)
+		if got["status"] != next {
+			t.Fatalf("respuesta %v, se esperaba el estado %s", got, next)
+		}
+		if current, _, _ := s.MatchStatus(matchID); current != next {
+			t.Fatalf("estado guardado %s, se esperaba %s", current, next)
+		}
+	}
+
+	// Un partido terminado ya no cambia
+	decodeProblem(t, patchStatus(matchID, `{"status":"live"}`), http.StatusConflict, codeInvalidTransition)
+}
+
+// racingStatusStore simula que otra solicitud cambió el estado entre la lectura y la escritura
+type racingStatusStore struct {
+	Store
+}
+
+func (racingStatusStore) SetMatchStatus(id int, from, to string) error {
+	return errStatusChanged
+}
+
+func TestSetMatchStatusConcurrentChange(t *testing.T) {
+	s := useMemoryStore(t)
+	teams := createTestTeams(t, s, "Athletic", "Betis")
+	matchID := createTestMatch(t, s, teams[0], teams[1], "2025-01-10", 0)
+	store = racingStatusStore{s}
+
+	decodeProblem(t, patchStatus(matchID, `{"status":"live"}`), http.StatusConflict, codeStatusChanged)
+}
+
+func TestRegisterEventRequiresPlay(t *testing.T) {
+	s := useMemoryStore(t)
+	teams := createTestTeams(t, s, "Athletic", "Betis")
+	matchID := createTestMatch(t, s, teams[0], teams[1], "2025-01-10", 0)
+
+	goal := func() *httptest.ResponseRecorder {
+		body := `{"teamId":` + strconv.Itoa(teams[0]) + `,"player":"Williams","minute":"10:00"}`
+		req := httptest.NewRequest(http.MethodPatch, "/api/matches/"+strconv.Itoa(matchID)+"/goals", strings.NewReader(body))
+		return serveRoute("/api/matches/{id}/goals", registerGoal, req)
+	}
+
+	// Programado: todavía no admite eventos
+	decodeProblem(t, goal(), http.StatusConflict, codeMatchNotInPlay)
+
+	// En juego y recién terminado sí
+	if err := s.SetMatchStatus(matchID, StatusScheduled, StatusLive); err != nil {
+		t.Fatal(err)
+	}
+	if rec := goal(); rec.Code != http.StatusOK {
+		t.Fatalf("en juego: código %d: %s", rec.Code, rec.Body.String())
+	}
+	if err := s.SetMatchStatus(matchID, StatusLive, StatusFinished); err != nil {
+		t.Fatal(err)
+	}
+	if rec := goal(); rec.Code != http.StatusOK {
+		t.Fatalf("recién terminado: código %d: %s", rec.Code, rec.Body.String())
+	}
+
+	// Pasado el margen después del final se rechaza
+	s.mu.Lock()
+	m := s.matches[matchID]
+	m.StatusUpdatedAt = time.Now().Add(-eventGracePeriod - time.Minute)
+	s.matches[matchID] = m
+	s.mu.Unlock()
+	decodeProblem(t, goal(), http.StatusConflict, codeMatchNotInPlay)
+
+	if events, _ := s.MatchEvents(matchID); len(events["goals"]) != 2 {
+		t.Errorf("goles registrados = %d, se esperaban 2", len(events["goals"]))
+	}
+}
@@ -0,0 +1,1259 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// memEvent es un gol o tarjeta guardado en memoria
+type memEvent struct {
+	ID       int
+	MatchID  int
+	TeamID   int
+	PlayerID int // 0 si el jugador es texto libre
+	Player   string
+	Minute   string
+}
+
+// memMatch es un partido guardado en memoria. Los nombres de los equipos
+// se completan al leerlo para reflejar los cambios de nombre.
+type memMatch struct {
+	Match
+	StatusUpdatedAt time.Time
+}
+
+// memStore implementa Store en memoria, sin persistencia. Sirve para pruebas y demos:
+// reproduce el orden, los filtros y las eliminaciones en cascada de las bases de datos.
+type memStore struct {
+	mu         sync.RWMutex
+	teams      map[int]Team
+	players    map[int]Player
+	comps      map[int]Competition
+	seasons    map[int]Season // Sin el nombre de la competición, que se completa al leerla
+	matches    map[int]memMatch
+	events     map[string]map[int]memEvent // Por tabla: goals, yellow_cards o red_cards
+	webhooks   map[int]Webhook
+	deliveries []WebhookDelivery
+	apiKeys    map[int]APIKey
+	audit      []AuditEntry
+	lastID     map[string]int // Último ID asignado por tabla, como AUTOINCREMENT
+}
+
+// newMemoryStore crea un almacenamiento en memoria vacío
+func newMemoryStore() *memStore {
+	s := &memStore{
+		teams:    map[int]Team{},
+		players:  map[int]Player{},
+		comps:    map[int]Competition{},
+		seasons:  map[int]Season{},
+		matches:  map[int]memMatch{},
+		events:   map[string]map[int]memEvent{},
+		webhooks: map[int]Webhook{},
+		apiKeys:  map[int]APIKey{},
+		lastID:   map[string]int{},
+	}
+	for _, table := range eventTables {
+		s.events[table] = map[int]memEvent{}
+	}
+	return s
+}
+
+// nextID devuelve el siguiente ID de la tabla indicada
+func (s *memStore) nextID(table string) int {
+	s.lastID[table]++
+	return s.lastID[table]
+}
+
+// memNow devuelve la fecha actual en UTC con el mismo formato que las bases de datos
+func memNow() string {
+	return time.Now().UTC().Format(time.DateTime)
+}
+
+// Ping siempre tiene éxito: no hay base de datos que consultar
+func (s *memStore) Ping(ctx context.Context) error {
+	return nil
+}
+
+// PendingMigrations siempre devuelve 0: el almacenamiento en memoria no tiene esquema
+func (s *memStore) PendingMigrations(ctx context.Context) (int, error) {
+	return 0, nil
+}
+
+// Close no hace nada: no hay conexión que liberar
+func (s *memStore) Close() error {
+	return nil
+}
+
+// ================================================================
+// Equipos
+// ================================================================
+
+// Teams devuelve los equipos ordenados por nombre sin distinguir mayúsculas
+func (s *memStore) Teams(sc matchScope) ([]Team, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	// Con una competición o temporada, solo los equipos que jugaron en ella
+	var played map[int]bool
+	if sc != (matchScope{}) {
+		played = map[int]bool{}
+		for _, m := range s.matches {
+			if s.inScope(m, sc) {
+				played[m.HomeTeamID], played[m.AwayTeamID] = true, true
+			}
+		}
+	}
+
+	teams := []Team{}
+	for _, t := range s.teams {
+		if played == nil || played[t.ID] {
+			teams = append(teams, t)
+		}
+	}
+	sort.Slice(teams, func(i, j int) bool {
+		return strings.ToLower(teams[i].Name) < strings.ToLower(teams[j].Name)
+	})
+	return teams, nil
+}
+
+// Team devuelve un equipo por ID
+func (s *memStore) Team(id int) (Team, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	t, ok := s.teams[id]
+	if !ok {
+		return Team{}, errNotFound
+	}
+	return t, nil
+}
+
+// TeamByName busca un equipo por nombre o nombre corto, prefiriendo la coincidencia por nombre
+func (s *memStore) TeamByName(name string) (Team, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var found Team
+	for _, t := range s.teams {
+		byName := strings.EqualFold(t.Name, name)
+		if !byName && !strings.EqualFold(t.ShortName, name) {
+			continue
+		}
+		foundByName := found.ID != 0 && strings.EqualFold(found.Name, name)
+		if found.ID == 0 || (byName && !foundByName) || (byName == foundByName && t.ID < found.ID) {
+			found = t
+		}
+	}
+	if found.ID == 0 {
+		return Team{}, errNotFound
+	}
+	return found, nil
+}
+
+// teamNameTaken indica si otro equipo ya usa el nombre; las bases de datos lo impiden con un índice único
+func (s *memStore) teamNameTaken(name string, exceptID int) bool {
+	for _, t := range s.teams {
+		if t.ID != exceptID && strings.EqualFold(t.Name, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// CreateTeam inserta un equipo y devuelve su ID
+func (s *memStore) CreateTeam(t Team) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.teamNameTaken(t.Name, 0) {
+		return 0, errors.New("ya existe un equipo con el nombre " + t.Name)
+	}
+	t.ID = s.nextID("teams")
+	s.teams[t.ID] = t
+	return t.ID, nil
+}
+
+// UpdateTeam actualiza los datos de un equipo
+func (s *memStore) UpdateTeam(t Team) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.teams[t.ID]; !ok {
+		return errNotFound
+	}
+	if s.teamNameTaken(t.Name, t.ID) {
+		return errors.New("ya existe un equipo con el nombre " + t.Name)
+	}
+	s.teams[t.ID] = t
+	return nil
+}
+
+// DeleteTeam elimina un equipo sin partidos junto con su plantilla y los webhooks filtrados por él
+func (s *memStore) DeleteTeam(id int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, m := range s.matches {
+		if m.HomeTeamID == id || m.AwayTeamID == id {
+			return errTeamHasMatches
+		}
+	}
+	if _, ok := s.teams[id]; !ok {
+		return errNotFound
+	}
+
+	// Los eventos de sus jugadores quedan como texto libre
+	for pid, p := range s.players {
+		if p.TeamID == id {
+			s.unlinkPlayerEvents(pid)
+			delete(s.players, pid)
+		}
+	}
+	for hid, h := range s.webhooks {
+		if h.TeamID == id {
+			s.deleteWebhook(hid)
+		}
+	}
+	delete(s.teams, id)
+	return nil
+}
+
+// ================================================================
+// Jugadores
+// ================================================================
+
+// Players devuelve la plantilla de un equipo ordenada por dorsal y nombre
+func (s *memStore) Players(teamID int) ([]Player, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	players := []Player{}
+	for _, p := range s.players {
+		if p.TeamID == teamID {
+			players = append(players, p)
+		}
+	}
+	sort.Slice(players, func(i, j int) bool {
+		if players[i].ShirtNumber != players[j].ShirtNumber {
+			return players[i].ShirtNumber < players[j].ShirtNumber
+		}
+		return players[i].Name < players[j].Name
+	})
+	return players, nil
+}
+
+// Player devuelve un jugador por ID
+func (s *memStore) Player(id int) (Player, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	p, ok := s.players[id]
+	if !ok {
+		return Player{}, errNotFound
+	}
+	return p, nil
+}
+
+// PlayerByName busca un jugador de la plantilla por nombre sin distinguir mayúsculas
+func (s *memStore) PlayerByName(teamID int, name string) (Player, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var found Player
+	for _, p := range s.players {
+		if p.TeamID == teamID && strings.EqualFold(p.Name, name) && (found.ID == 0 || p.ID < found.ID) {
+			found = p
+		}
+	}
+	if found.ID == 0 {
+		return Player{}, errNotFound
+	}
+	return found, nil
+}
+
+// ShirtNumberTaken indica si otro jugador del equipo ya usa el dorsal
+func (s *memStore) ShirtNumberTaken(teamID, number, exceptID int) (bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, p := range s.players {
+		if p.TeamID == teamID && p.ShirtNumber == number && p.ID != exceptID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// CreatePlayer inserta un jugador y devuelve su ID
+func (s *memStore) CreatePlayer(p Player) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.teams[p.TeamID]; !ok {
+		return 0, errTeamNotFound
+	}
+	p.ID = s.nextID("players")
+	s.players[p.ID] = p
+	return p.ID, nil
+}
+
+// UpdatePlayer actualiza un jugador del equipo teamID
+func (s *memStore) UpdatePlayer(teamID int, p Player) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if old, ok := s.players[p.ID]; !ok || old.TeamID != teamID {
+		return errNotFound
+	}
+	if _, ok := s.teams[p.TeamID]; !ok {
+		return errTeamNotFound
+	}
+	s.players[p.ID] = p
+	return nil
+}
+
+// DeletePlayer elimina un jugador del equipo dejando sus eventos como texto libre
+func (s *memStore) DeletePlayer(teamID, id int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if p, ok := s.players[id]; !ok || p.TeamID != teamID {
+		return errNotFound
+	}
+	s.unlinkPlayerEvents(id)
+	delete(s.players, id)
+	return nil
+}
+
+// unlinkPlayerEvents deja como texto libre los eventos del jugador
+func (s *memStore) unlinkPlayerEvents(playerID int) {
+	for _, events := range s.events {
+		for id, e := range events {
+			if e.PlayerID == playerID {
+				e.PlayerID = 0
+				events[id] = e
+			}
+		}
+	}
+}
+
+// ================================================================
+// Competiciones y temporadas
+// ================================================================
+
+// Competitions devuelve todas las competiciones ordenadas por nombre sin distinguir mayúsculas
+func (s *memStore) Competitions() ([]Competition, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	competitions := []Competition{}
+	for _, c := range s.comps {
+		competitions = append(competitions, c)
+	}
+	sort.Slice(competitions, func(i, j int) bool {
+		return strings.ToLower(competitions[i].Name) < strings.ToLower(competitions[j].Name)
+	})
+	return competitions, nil
+}
+
+// Competition devuelve una competición por ID
+func (s *memStore) Competition(id int) (Competition, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	c, ok := s.comps[id]
+	if !ok {
+		return Competition{}, errNotFound
+	}
+	return c, nil
+}
+
+// CompetitionByName busca una competición por nombre sin distinguir mayúsculas
+func (s *memStore) CompetitionByName(name string) (Competition, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, c := range s.comps {
+		if strings.EqualFold(c.Name, name) {
+			return c, nil
+		}
+	}
+	return Competition{}, errNotFound
+}
+
+// competitionNameTaken indica si otra competición ya usa el nombre, como el índice único de las bases de datos
+func (s *memStore) competitionNameTaken(name string, exceptID int) bool {
+	for _, c := range s.comps {
+		if c.ID != exceptID && strings.EqualFold(c.Name, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// CreateCompetition inserta una competición y devuelve su ID
+func (s *memStore) CreateCompetition(c Competition) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.competitionNameTaken(c.Name, 0) {
+		return 0, errors.New("ya existe una competición con el nombre " + c.Name)
+	}
+	c.ID = s.nextID("competitions")
+	s.comps[c.ID] = c
+	return c.ID, nil
+}
+
+// UpdateCompetition actualiza los datos de una competición
+func (s *memStore) UpdateCompetition(c Competition) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.comps[c.ID]; !ok {
+		return errNotFound
+	}
+	if s.competitionNameTaken(c.Name, c.ID) {
+		return errors.New("ya existe una competición con el nombre " + c.Name)
+	}
+	s.comps[c.ID] = c
+	return nil
+}
+
+// DeleteCompetition elimina una competición sin temporadas
+func (s *memStore) DeleteCompetition(id int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, se := range s.seasons {
+		if se.CompetitionID == id {
+			return errCompetitionHasSeasons
+		}
+	}
+	if _, ok := s.comps[id]; !ok {
+		return errNotFound
+	}
+	delete(s.comps, id)
+	return nil
+}
+
+// season completa el nombre de la competición de una temporada guardada
+func (s *memStore) season(se Season) Season {
+	se.Competition = s.comps[se.CompetitionID].Name
+	return se
+}
+
+// Seasons devuelve las temporadas de una competición ordenadas por fecha de inicio
+func (s *memStore) Seasons(competitionID int) ([]Season, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	seasons := []Season{}
+	for _, se := range s.seasons {
+		if se.CompetitionID == competitionID {
+			seasons = append(seasons, s.season(se))
+		}
+	}
+	sort.Slice(seasons, func(i, j int) bool {
+		if seasons[i].StartDate != seasons[j].StartDate {
+			return seasons[i].StartDate < seasons[j].StartDate
+		}
+		return seasons[i].ID < seasons[j].ID
+	})
+	return seasons, nil
+}
+
+// Season devuelve una temporada por ID con el nombre de su competición
+func (s *memStore) Season(id int) (Season, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	se, ok := s.seasons[id]
+	if !ok {
+		return Season{}, errNotFound
+	}
+	return s.season(se), nil
+}
+
+// CreateSeason inserta una temporada y devuelve su ID
+func (s *memStore) CreateSeason(se Season) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.comps[se.CompetitionID]; !ok {
+		return 0, errCompetitionNotFound
+	}
+	se.ID = s.nextID("seasons")
+	se.Competition = ""
+	s.seasons[se.ID] = se
+	return se.ID, nil
+}
+
+// UpdateSeason actualiza el nombre y las fechas de una temporada; la competición no cambia
+func (s *memStore) UpdateSeason(se Season) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, ok := s.seasons[se.ID]
+	if !ok {
+		return errNotFound
+	}
+	stored.Name, stored.StartDate, stored.EndDate = se.Name, se.StartDate, se.EndDate
+	s.seasons[se.ID] = stored
+	return nil
+}
+
+// DeleteSeason elimina una temporada sin partidos
+func (s *memStore) DeleteSeason(id int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, m := range s.matches {
+		if m.SeasonID == id {
+			return errSeasonHasMatches
+		}
+	}
+	if _, ok := s.seasons[id]; !ok {
+		return errNotFound
+	}
+	delete(s.seasons, id)
+	return nil
+}
+
+// ================================================================
+// Partidos
+// ================================================================
+
+// match completa los nombres de los equipos de un partido guardado
+func (s *memStore) match(m memMatch) Match {
+	match := m.Match
+	match.HomeTeam = s.teams[m.HomeTeamID].Name
+	match.AwayTeam = s.teams[m.AwayTeamID].Name
+	return match
+}
+
+// summary construye un partido con sus goles y tarjetas por equipo, sin el detalle de eventos
+func (s *memStore) summary(m memMatch) FullMatchData {
+	match := s.match(m)
+	full := FullMatchData{
+		ID:         match.ID,
+		HomeTeamID: match.HomeTeamID,
+		HomeTeam:   match.HomeTeam,
+		AwayTeamID: match.AwayTeamID,
+		AwayTeam:   match.AwayTeam,
+		MatchDate:  match.MatchDate,
+		ExtraTime:  match.ExtraTime,
+		Status:     match.Status,
+		SeasonID:   match.SeasonID,
+	}
+
+	counters := map[string][2]*int{
+		"goals":        {&full.HomeGoals, &full.AwayGoals},
+		"yellow_cards": {&full.HomeYellowCardsCount, &full.AwayYellowCardsCount},
+		"red_cards":    {&full.HomeRedCardsCount, &full.AwayRedCardsCount},
+	}
+	for table, events := range s.events {
+		for _, e := range events {
+			if e.MatchID != m.ID {
+				continue
+			}
+			switch e.TeamID {
+			case m.HomeTeamID:
+				*counters[table][0]++
+			case m.AwayTeamID:
+				*counters[table][1]++
+			}
+		}
+	}
+	return full
+}
+
+// datePart devuelve la fecha (YYYY-MM-DD) de un texto, igual que LEFT(x, 10) en PostgreSQL
+func datePart(s string) string {
+	if len(s) > 10 {
+		return s[:10]
+	}
+	return s
+}
+
+// Matches devuelve una página de partidos con sus goles y tarjetas y el total que cumple los filtros
+func (s *memStore) Matches(lq matchListQuery) ([]FullMatchData, int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	// Aplicar los filtros
+	var filtered []memMatch
+	for _, m := range s.matches {
+		if !s.inScope(m, lq.matchScope) {
+			continue
+		}
+		if lq.From != "" && datePart(m.MatchDate) < lq.From {
+			continue
+		}
+		if lq.To != "" && datePart(m.MatchDate) > lq.To {
+			continue
+		}
+		if lq.TeamID != 0 && m.HomeTeamID != lq.TeamID && m.AwayTeamID != lq.TeamID {
+			continue
+		}
+		if len(lq.Statuses) > 0 && !containsString(lq.Statuses, m.Status) {
+			continue
+		}
+		filtered = append(filtered, m)
+	}
+
+	// Ordenar por ID o por fecha; el ID desempata los partidos del mismo día
+	desc := strings.HasPrefix(lq.Sort, "-")
+	byDate := strings.TrimPrefix(lq.Sort, "-") == "date"
+	sort.Slice(filtered, func(i, j int) bool {
+		a, b := filtered[i], filtered[j]
+		if desc {
+			a, b = b, a
+		}
+		if byDate && a.MatchDate != b.MatchDate {
+			return a.MatchDate < b.MatchDate
+		}
+		return a.ID < b.ID
+	})
+
+	// Recortar la página pedida
+	total := len(filtered)
+	start := min(lq.offset(), total)
+	end := min(start+lq.Limit, total)
+
+	matches := []FullMatchData{}
+	for _, m := range filtered[start:end] {
+		matches = append(matches, s.summary(m))
+	}
+	return matches, total, nil
+}
+
+// inScope indica si el partido es de la temporada o de alguna temporada de la competición
+func (s *memStore) inScope(m memMatch, sc matchScope) bool {
+	switch {
+	case sc.SeasonID != 0:
+		return m.SeasonID == sc.SeasonID
+	case sc.CompetitionID != 0:
+		season, ok := s.seasons[m.SeasonID]
+		return ok && season.CompetitionID == sc.CompetitionID
+	}
+	return true
+}
+
+// containsString indica si la lista contiene el valor
+func containsString(list []string, value string) bool {
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// Match devuelve los datos básicos de un partido
+func (s *memStore) Match(id int) (Match, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	m, ok := s.matches[id]
+	if !ok {
+		return Match{}, errNotFound
+	}
+	return s.match(m), nil
+}
+
+// MatchSummary devuelve un partido con sus goles y tarjetas por equipo
+func (s *memStore) MatchSummary(id int) (FullMatchData, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	m, ok := s.matches[id]
+	if !ok {
+		return FullMatchData{}, errNotFound
+	}
+	return s.summary(m), nil
+}
+
+// CreateMatch inserta un partido programado y devuelve su ID
+func (s *memStore) CreateMatch(m Match) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.checkMatchRefs(m); err != nil {
+		return 0, err
+	}
+	return s.insertMatch(m), nil
+}
+
+// CreateSeasonFixtures inserta el calendario de una temporada vacía; si algún partido referencia
+// un equipo inexistente no se inserta ninguno, como en la transacción de las bases de datos
+func (s *memStore) CreateSeasonFixtures(seasonID int, ms []Match) ([]int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.seasons[seasonID]; !ok {
+		return nil, errNotFound
+	}
+	for _, m := range s.matches {
+		if m.SeasonID == seasonID {
+			return nil, errSeasonHasMatches
+		}
+	}
+	for _, m := range ms {
+		if err := s.checkMatchRefs(m); err != nil {
+			return nil, err
+		}
+	}
+	ids := make([]int, len(ms))
+	for i, m := range ms {
+		m.SeasonID = seasonID
+		ids[i] = s.insertMatch(m)
+	}
+	return ids, nil
+}
+
+// checkMatchRefs verifica los equipos y la temporada de un partido, como las claves foráneas
+func (s *memStore) checkMatchRefs(m Match) error {
+	if _, ok := s.teams[m.HomeTeamID]; !ok {
+		return errTeamNotFound
+	}
+	if _, ok := s.teams[m.AwayTeamID]; !ok {
+		return errTeamNotFound
+	}
+	if _, ok := s.seasons[m.SeasonID]; m.SeasonID != 0 && !ok {
+		return errSeasonNotFound
+	}
+	return nil
+}
+
+// insertMatch guarda un partido programado con un ID nuevo y lo devuelve
+func (s *memStore) insertMatch(m Match) int {
+	m.ID = s.nextID("matches")
+	m.ExtraTime = "00:00"
+	m.Status = StatusScheduled
+	s.matches[m.ID] = memMatch{Match: m, StatusUpdatedAt: time.Now().UTC().Truncate(time.Second)}
+	return m.ID
+}
+
+// UpdateMatch actualiza los equipos, la fecha y la temporada de un partido
+func (s *memStore) UpdateMatch(m Match) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, ok := s.matches[m.ID]
+	if !ok {
+		return errNotFound
+	}
+	if _, ok := s.seasons[m.SeasonID]; m.SeasonID != 0 && !ok {
+		return errSeasonNotFound
+	}
+	stored.HomeTeamID, stored.AwayTeamID, stored.MatchDate = m.HomeTeamID, m.AwayTeamID, m.MatchDate
+	stored.SeasonID = m.SeasonID
+	s.matches[m.ID] = stored
+	return nil
+}
+
+// DeleteMatch elimina un partido junto con sus eventos
+func (s *memStore) DeleteMatch(id int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.matches[id]; !ok {
+		return errNotFound
+	}
+	for _, events := range s.events {
+		for eid, e := range events {
+			if e.MatchID == id {
+				delete(events, eid)
+			}
+		}
+	}
+	delete(s.matches, id)
+	return nil
+}
+
+// SetExtraTime actualiza el tiempo extra de un partido
+func (s *memStore) SetExtraTime(id int, extraTime string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	m, ok := s.matches[id]
+	if !ok {
+		return errNotFound
+	}
+	m.ExtraTime = extraTime
+	s.matches[id] = m
+	return nil
+}
+
+// MatchStatus obtiene el estado actual de un partido y la fecha de su último cambio
+func (s *memStore) MatchStatus(id int) (string, time.Time, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	m, ok := s.matches[id]
+	if !ok {
+		return "", time.Time{}, errNotFound
+	}
+	return m.Status, m.StatusUpdatedAt, nil
+}
+
+// SetMatchStatus actualiza el estado solo si nadie lo cambió mientras tanto
+func (s *memStore) SetMatchStatus(id int, from, to string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	m, ok := s.matches[id]
+	if !ok || m.Status != from {
+		return errStatusChanged
+	}
+	m.Status = to
+	m.StatusUpdatedAt = time.Now().UTC().Truncate(time.Second)
+	s.matches[id] = m
+	return nil
+}
+
+// FinishedResults obtiene el marcador de cada partido terminado de la competición o temporada
+func (s *memStore) FinishedResults(sc matchScope) ([]matchResult, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	ids := make([]int, 0, len(s.matches))
+	for id, m := range s.matches {
+		if m.Status == StatusFinished && s.inScope(m, sc) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Ints(ids)
+
+	var results []matchResult
+	for _, id := range ids {
+		m := s.summary(s.matches[id])
+		results = append(results, matchResult{HomeTeam: m.HomeTeam, AwayTeam: m.AwayTeam, HomeGoals: m.HomeGoals, AwayGoals: m.AwayGoals})
+	}
+	return results, nil
+}
+
+// ================================================================
+// Eventos
+// ================================================================
+
+// event construye el MatchEvent que se devuelve a partir del evento guardado
+func (s *memStore) event(e memEvent) MatchEvent {
+	return MatchEvent{
+		ID:       e.ID,
+		TeamID:   e.TeamID,
+		Team:     s.teams[e.TeamID].Name,
+		PlayerID: e.PlayerID,
+		Player:   e.Player,
+		Minute:   e.Minute,
+	}
+}
+
+// MatchEvents devuelve los eventos de un partido agrupados por tabla y ordenados por ID
+func (s *memStore) MatchEvents(matchID int) (map[string][]MatchEvent, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	events := map[string][]MatchEvent{}
+	for table, stored := range s.events {
+		for _, e := range stored {
+			if e.MatchID == matchID {
+				events[table] = append(events[table], s.event(e))
+			}
+		}
+		list := events[table]
+		sort.Slice(list, func(i, j int) bool { return list[i].ID < list[j].ID })
+	}
+	return events, nil
+}
+
+// Event obtiene un evento de la tabla indicada, solo si pertenece al partido
+func (s *memStore) Event(table string, matchID, eventID int) (MatchEvent, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	e, ok := s.events[table][eventID]
+	if !ok || e.MatchID != matchID {
+		return MatchEvent{}, errNotFound
+	}
+	return s.event(e), nil
+}
+
+// CreateEvent inserta un evento en la tabla indicada y devuelve su ID
+func (s *memStore) CreateEvent(table string, matchID int, ev resolvedEvent) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.matches[matchID]; !ok {
+		return 0, errNotFound
+	}
+	id := s.nextID(table)
+	s.events[table][id] = memEvent{
+		ID:       id,
+		MatchID:  matchID,
+		TeamID:   ev.TeamID,
+		PlayerID: int(ev.PlayerID.Int64),
+		Player:   ev.Player,
+		Minute:   ev.Minute,
+	}
+	return id, nil
+}
+
+// UpdateEvent corrige un evento del partido
+func (s *memStore) UpdateEvent(table string, matchID, eventID int, ev resolvedEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.events[table][eventID]
+	if !ok || e.MatchID != matchID {
+		return errNotFound
+	}
+	e.TeamID, e.PlayerID, e.Player, e.Minute = ev.TeamID, int(ev.PlayerID.Int64), ev.Player, ev.Minute
+	s.events[table][eventID] = e
+	return nil
+}
+
+// DeleteEvent elimina un evento del partido
+func (s *memStore) DeleteEvent(table string, matchID, eventID int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.events[table][eventID]
+	if !ok || e.MatchID != matchID {
+		return errNotFound
+	}
+	delete(s.events[table], eventID)
+	return nil
+}
+
+// playerStat acumula los eventos de un jugador para las tablas de estadísticas
+type playerStat struct {
+	PlayerID int
+	Player   string
+	TeamID   int
+	Counts   map[string]int // Eventos por tabla
+}
+
+// playerStats agrupa por jugador y equipo los eventos de las tablas indicadas que cumplen los filtros.
+// Igual que en SQL, los jugadores registrados se agrupan por ID y los de texto libre por nombre.
+func (s *memStore) playerStats(f statsFilter, tables ...string) []*playerStat {
+	groups := map[string]*playerStat{}
+	var order []*playerStat
+	for _, table := range tables {
+		for _, e := range s.events[table] {
+			m, ok := s.matches[e.MatchID]
+			if !ok {
+				continue
+			}
+			if !s.inScope(m, f.matchScope) {
+				continue
+			}
+			if f.From != "" && datePart(m.MatchDate) < f.From {
+				continue
+			}
+			if f.To != "" && datePart(m.MatchDate) > f.To {
+				continue
+			}
+			if f.TeamID != 0 && e.TeamID != f.TeamID {
+				continue
+			}
+
+			// El nombre es el de la plantilla si el jugador sigue registrado
+			name := strings.TrimSpace(e.Player)
+			key := strconv.Itoa(e.TeamID) + "|name:" + strings.ToLower(name)
+			if e.PlayerID != 0 {
+				key = strconv.Itoa(e.TeamID) + "|id:" + strconv.Itoa(e.PlayerID)
+				if p, ok := s.players[e.PlayerID]; ok {
+					name = p.Name
+				}
+			}
+
+			g := groups[key]
+			if g == nil {
+				g = &playerStat{PlayerID: e.PlayerID, Player: name, TeamID: e.TeamID, Counts: map[string]int{}}
+				groups[key] = g
+				order = append(order, g)
+			}
+			if name < g.Player {
+				g.Player = name
+			}
+			g.Counts[table]++
+		}
+	}
+	return order
+}
+
+// TopScorers agrupa los goles por jugador y equipo
+func (s *memStore) TopScorers(f statsFilter) ([]ScorerStat, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	stats := s.playerStats(f, "goals")
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Counts["goals"] != stats[j].Counts["goals"] {
+			return stats[i].Counts["goals"] > stats[j].Counts["goals"]
+		}
+		return stats[i].Player < stats[j].Player
+	})
+
+	scorers := []ScorerStat{}
+	for _, g := range stats[:min(f.Limit, len(stats))] {
+		scorers = append(scorers, ScorerStat{
+			Rank:     len(scorers) + 1,
+			PlayerID: g.PlayerID,
+			Player:   g.Player,
+			TeamID:   g.TeamID,
+			Team:     s.teams[g.TeamID].Name,
+			Goals:    g.Counts["goals"],
+		})
+	}
+	return scorers, nil
+}
+
+// Discipline agrupa las tarjetas amarillas y rojas por jugador y equipo
+func (s *memStore) Discipline(f statsFilter) ([]DisciplineStat, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	stats := s.playerStats(f, "yellow_cards", "red_cards")
+	sort.Slice(stats, func(i, j int) bool {
+		a, b := stats[i].Counts, stats[j].Counts
+		if a["red_cards"] != b["red_cards"] {
+			return a["red_cards"] > b["red_cards"]
+		}
+		if a["yellow_cards"] != b["yellow_cards"] {
+			return a["yellow_cards"] > b["yellow_cards"]
+		}
+		return stats[i].Player < stats[j].Player
+	})
+
+	discipline := []DisciplineStat{}
+	for _, g := range stats[:min(f.Limit, len(stats))] {
+		discipline = append(discipline, DisciplineStat{
+			Rank:        len(discipline) + 1,
+			PlayerID:    g.PlayerID,
+			Player:      g.Player,
+			TeamID:      g.TeamID,
+			Team:        s.teams[g.TeamID].Name,
+			YellowCards: g.Counts["yellow_cards"],
+			RedCards:    g.Counts["red_cards"],
+		})
+	}
+	return discipline, nil
+}
+
+// ================================================================
+// Webhooks
+// ================================================================
+
+// webhook devuelve una copia del webhook para que el llamador no modifique la lista de eventos guardada
+func (s *memStore) webhook(h Webhook) Webhook {
+	h.Events = append([]string(nil), h.Events...)
+	return h
+}
+
+// Webhooks devuelve todos los webhooks ordenados por ID
+func (s *memStore) Webhooks() ([]Webhook, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	hooks := []Webhook{}
+	for _, h := range s.webhooks {
+		hooks = append(hooks, s.webhook(h))
+	}
+	sort.Slice(hooks, func(i, j int) bool { return hooks[i].ID < hooks[j].ID })
+	return hooks, nil
+}
+
+// Webhook devuelve un webhook por ID
+func (s *memStore) Webhook(id int) (Webhook, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	h, ok := s.webhooks[id]
+	if !ok {
+		return Webhook{}, errNotFound
+	}
+	return s.webhook(h), nil
+}
+
+// ActiveWebhooks devuelve los webhooks activos que pueden interesarse en el partido
+func (s *memStore) ActiveWebhooks(matchID int) ([]Webhook, error) {
+	hooks, _ := s.Webhooks()
+	active := []Webhook{}
+	for _, h := range hooks {
+		if h.Active && (h.MatchID == 0 || h.MatchID == matchID) {
+			active = append(active, h)
+		}
+	}
+	return active, nil
+}
+
+// CreateWebhook inserta un webhook activo y lo devuelve con su ID y fecha de registro
+func (s *memStore) CreateWebhook(h Webhook) (Webhook, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.teams[h.TeamID]; h.TeamID != 0 && !ok {
+		return Webhook{}, errTeamNotFound
+	}
+	h.ID = s.nextID("webhooks")
+	h.Active = true
+	h.CreatedAt = memNow()
+	h = s.webhook(h)
+	s.webhooks[h.ID] = h
+	return s.webhook(h), nil
+}
+
+// DeleteWebhook elimina un webhook junto con su historial de entregas
+func (s *memStore) DeleteWebhook(id int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.webhooks[id]; !ok {
+		return errNotFound
+	}
+	s.deleteWebhook(id)
+	return nil
+}
+
+// deleteWebhook elimina un webhook y sus entregas; el llamador debe tener el bloqueo
+func (s *memStore) deleteWebhook(id int) {
+	kept := s.deliveries[:0]
+	for _, d := range s.deliveries {
+		if d.WebhookID != id {
+			kept = append(kept, d)
+		}
+	}
+	s.deliveries = kept
+	delete(s.webhooks, id)
+}
+
+// RecordDelivery registra un intento de entrega
+func (s *memStore) RecordDelivery(d WebhookDelivery) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Igual que la clave foránea, no se registran entregas de webhooks eliminados
+	if _, ok := s.webhooks[d.WebhookID]; !ok {
+		return errNotFound
+	}
+	d.ID = s.nextID("webhook_deliveries")
+	d.CreatedAt = memNow()
+	s.deliveries = append(s.deliveries, d)
+	return nil
+}
+
+// Deliveries devuelve los intentos de entrega de un webhook del más reciente al más antiguo
+func (s *memStore) Deliveries(webhookID int, success *bool, limit int) ([]WebhookDelivery, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	// Las entregas se guardan en orden de ID, por eso se recorren desde el final
+	deliveries := []WebhookDelivery{}
+	for i := len(s.deliveries) - 1; i >= 0 && len(deliveries) < limit; i-- {
+		d := s.deliveries[i]
+		if d.WebhookID == webhookID && (success == nil || d.Success == *success) {
+			deliveries = append(deliveries, d)
+		}
+	}
+	return deliveries, nil
+}
+
+// ================================================================
+// Claves de API
+// ================================================================
+
+// APIKeys devuelve todas las claves ordenadas por ID
+func (s *memStore) APIKeys() ([]APIKey, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	keys := []APIKey{}
+	for _, k := range s.apiKeys {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].ID < keys[j].ID })
+	return keys, nil
+}
+
+// APIKeyByHash devuelve la clave activa con ese hash
+func (s *memStore) APIKeyByHash(hash string) (APIKey, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, k := range s.apiKeys {
+		if k.Hash == hash && k.Active {
+			return k, nil
+		}
+	}
+	return APIKey{}, errNotFound
+}
+
+// CreateAPIKey inserta una clave activa
+func (s *memStore) CreateAPIKey(k APIKey) (APIKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Como el índice único de las bases de datos, también entre las claves revocadas
+	for _, other := range s.apiKeys {
+		if strings.EqualFold(other.Name, k.Name) {
+			return APIKey{}, errors.New("ya existe una clave de API con el nombre " + k.Name)
+		}
+	}
+	k.ID = s.nextID("api_keys")
+	k.Active = true
+	k.CreatedAt = memNow()
+	s.apiKeys[k.ID] = k
+	return k, nil
+}
+
+// RevokeAPIKey desactiva una clave activa
+func (s *memStore) RevokeAPIKey(id int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	k, ok := s.apiKeys[id]
+	if !ok || !k.Active {
+		return errNotFound
+	}
+	k.Active = false
+	s.apiKeys[id] = k
+	return nil
+}
+
+// ================================================================
+// Auditoría
+// ================================================================
+
+// RecordAudit agrega una entrada al historial
+func (s *memStore) RecordAudit(e AuditEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e.ID = s.nextID("audit_log")
+	e.CreatedAt = memNow()
+	s.audit = append(s.audit, e)
+	return nil
+}
+
+// AuditEntries devuelve las entradas del historial de la más reciente a la más antigua
+func (s *memStore) AuditEntries(f auditFilter) ([]AuditEntry, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	// Las entradas se guardan en orden de ID, por eso se recorren desde el final
+	entries := []AuditEntry{}
+	for i := len(s.audit) - 1; i >= 0 && len(entries) < f.Limit; i-- {
+		e := s.audit[i]
+		if (f.MatchID == 0 || e.MatchID == f.MatchID) && (f.Actor == "" || e.Actor == f.Actor) && (f.Before == 0 || e.ID < f.Before) {
+			entries = append(entries, e)
+		}
+	}
+	return entries, nil
+}
@@ -0,0 +1,333 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// metricsStore envuelve un Store y mide la duración y los errores de cada operación
+// en laliga_db_operation_duration_seconds y laliga_db_operation_errors_total.
+// Funciona igual con cualquier backend porque mide en la interfaz y no en el driver.
+type metricsStore struct {
+	Store
+}
+
+// instrumentStore devuelve s con sus operaciones medidas
+func instrumentStore(s Store) Store {
+	return metricsStore{s}
+}
+
+// observe registra una operación que empezó en start. Se usa con defer y un error
+// con nombre para leer el resultado final: defer observe("Teams", time.Now(), &err).
+// Los errores esperados (no encontrado, conflictos) no cuentan como fallos.
+func observe(op string, start time.Time, err *error) {
+	dbDuration.WithLabelValues(op).Observe(time.Since(start).Seconds())
+	if e := *err; e != nil && !errors.Is(e, errNotFound) && !errors.Is(e, errTeamHasMatches) && !errors.Is(e, errStatusChanged) &&
+		!errors.Is(e, errCompetitionHasSeasons) && !errors.Is(e, errSeasonHasMatches) {
+		dbErrors.WithLabelValues(op).Inc()
+	}
+}
+
+// Equipos
+
+func (s metricsStore) Teams(sc matchScope) (teams []Team, err error) {
+	defer observe("Teams", time.Now(), &err)
+	return s.Store.Teams(sc)
+}
+
+func (s metricsStore) Team(id int) (t Team, err error) {
+	defer observe("Team", time.Now(), &err)
+	return s.Store.Team(id)
+}
+
+func (s metricsStore) TeamByName(name string) (t Team, err error) {
+	defer observe("TeamByName", time.Now(), &err)
+	return s.Store.TeamByName(name)
+}
+
+func (s metricsStore) CreateTeam(t Team) (id int, err error) {
+	defer observe("CreateTeam", time.Now(), &err)
+	return s.Store.CreateTeam(t)
+}
+
+func (s metricsStore) UpdateTeam(t Team) (err error) {
+	defer observe("UpdateTeam", time.Now(), &err)
+	return s.Store.UpdateTeam(t)
+}
+
+func (s metricsStore) DeleteTeam(id int) (err error) {
+	defer observe("DeleteTeam", time.Now(), &err)
+	return s.Store.DeleteTeam(id)
+}
+
+// Jugadores
+
+func (s metricsStore) Players(teamID int) (players []Player, err error) {
+	defer observe("Players", time.Now(), &err)
+	return s.Store.Players(teamID)
+}
+
+func (s metricsStore) Player(id int) (p Player, err error) {
+	defer observe("Player", time.Now(), &err)
+	return s.Store.Player(id)
+}
+
+func (s metricsStore) PlayerByName(teamID int, name string) (p Player, err error) {
+	defer observe("PlayerByName", time.Now(), &err)
+	return s.Store.PlayerByName(teamID, name)
+}
+
+func (s metricsStore) ShirtNumberTaken(teamID, number, exceptID int) (taken bool, err error) {
+	defer observe("ShirtNumberTaken", time.Now(), &err)
+	return s.Store.ShirtNumberTaken(teamID, number, exceptID)
+}
+
+func (s metricsStore) CreatePlayer(p Player) (id int, err error) {
+	defer observe("CreatePlayer", time.Now(), &err)
+	return s.Store.CreatePlayer(p)
+}
+
+func (s metricsStore) UpdatePlayer(teamID int, p Player) (err error) {
+	defer observe("UpdatePlayer", time.Now(), &err)
+	return s.Store.UpdatePlayer(teamID, p)
+}
+
+func (s metricsStore) DeletePlayer(teamID, id int) (err error) {
+	defer observe("DeletePlayer", time.Now(), &err)
+	return s.Store.DeletePlayer(teamID, id)
+}
+
+// Competiciones y temporadas
+
+func (s metricsStore) Competitions() (competitions []Competition, err error) {
+	defer observe("Competitions", time.Now(), &err)
+	return s.Store.Competitions()
+}
+
+func (s metricsStore) Competition(id int) (c Competition, err error) {
+	defer observe("Competition", time.Now(), &err)
+	return s.Store.Competition(id)
+}
+
+func (s metricsStore) CompetitionByName(name string) (c Competition, err error) {
+	defer observe("CompetitionByName", time.Now(), &err)
+	return s.Store.CompetitionByName(name)
+}
+
+func (s metricsStore) CreateCompetition(c Competition) (id int, err error) {
+	defer observe("CreateCompetition", time.Now(), &err)
+	return s.Store.CreateCompetition(c)
+}
+
+func (s metricsStore) UpdateCompetition(c Competition) (err error) {
+	defer observe("UpdateCompetition", time.Now(), &err)
+	return s.Store.UpdateCompetition(c)
+}
+
+func (s metricsStore) DeleteCompetition(id int) (err error) {
+	defer observe("DeleteCompetition", time.Now(), &err)
+	return s.Store.DeleteCompetition(id)
+}
+
+func (s metricsStore) Seasons(competitionID int) (seasons []Season, err error) {
+	defer observe("Seasons", time.Now(), &err)
+	return s.Store.Seasons(competitionID)
+}
+
+func (s metricsStore) Season(id int) (se Season, err error) {
+	defer observe("Season", time.Now(), &err)
+	return s.Store.Season(id)
+}
+
+func (s metricsStore) CreateSeason(se Season) (id int, err error) {
+	defer observe("CreateSeason", time.Now(), &err)
+	return s.Store.CreateSeason(se)
+}
+
+func (s metricsStore) UpdateSeason(se Season) (err error) {
+	defer observe("UpdateSeason", time.Now(), &err)
+	return s.Store.UpdateSeason(se)
+}
+
+func (s metricsStore) DeleteSeason(id int) (err error) {
+	defer observe("DeleteSeason", time.Now(), &err)
+	return s.Store.DeleteSeason(id)
+}
+
+// Partidos
+
+func (s metricsStore) Matches(lq matchListQuery) (matches []FullMatchData, total int, err error) {
+	defer observe("Matches", time.Now(), &err)
+	return s.Store.Matches(lq)
+}
+
+func (s metricsStore) Match(id int) (m Match, err error) {
+	defer observe("Match", time.Now(), &err)
+	return s.Store.Match(id)
+}
+
+func (s metricsStore) MatchSummary(id int) (m FullMatchData, err error) {
+	defer observe("MatchSummary", time.Now(), &err)
+	return s.Store.MatchSummary(id)
+}
+
+func (s metricsStore) CreateMatch(m Match) (id int, err error) {
+	defer observe("CreateMatch", time.Now(), &err)
+	return s.Store.CreateMatch(m)
+}
+
+func (s metricsStore) CreateSeasonFixtures(seasonID int, ms []Match) (ids []int, err error) {
+	defer observe("CreateSeasonFixtures", time.Now(), &err)
+	return s.Store.CreateSeasonFixtures(seasonID, ms)
+}
+
+func (s metricsStore) UpdateMatch(m Match) (err error) {
+	defer observe("UpdateMatch", time.Now(), &err)
+	return s.Store.UpdateMatch(m)
+}
+
+func (s metricsStore) DeleteMatch(id int) (err error) {
+	defer observe("DeleteMatch", time.Now(), &err)
+	return s.Store.DeleteMatch(id)
+}
+
+func (s metricsStore) SetExtraTime(id int, extraTime string) (err error) {
+	defer observe("SetExtraTime", time.Now(), &err)
+	return s.Store.SetExtraTime(id, extraTime)
+}
+
+func (s metricsStore) MatchStatus(id int) (status string, changed time.Time, err error) {
+	defer observe("MatchStatus", time.Now(), &err)
+	return s.Store.MatchStatus(id)
+}
+
+func (s metricsStore) SetMatchStatus(id int, from, to string) (err error) {
+	defer observe("SetMatchStatus", time.Now(), &err)
+	return s.Store.SetMatchStatus(id, from, to)
+}
+
+func (s metricsStore) FinishedResults(sc matchScope) (results []matchResult, err error) {
+	defer observe("FinishedResults", time.Now(), &err)
+	return s.Store.FinishedResults(sc)
+}
+
+// Eventos
+
+func (s metricsStore) MatchEvents(matchID int) (events map[string][]MatchEvent, err error) {
+	defer observe("MatchEvents", time.Now(), &err)
+	return s.Store.MatchEvents(matchID)
+}
+
+func (s metricsStore) Event(table string, matchID, eventID int) (ev MatchEvent, err error) {
+	defer observe("Event", time.Now(), &err)
+	return s.Store.Event(table, matchID, eventID)
+}
+
+func (s metricsStore) CreateEvent(table string, matchID int, ev resolvedEvent) (id int, err error) {
+	defer observe("CreateEvent", time.Now(), &err)
+	return s.Store.CreateEvent(table, matchID, ev)
+}
+
+func (s metricsStore) UpdateEvent(table string, matchID, eventID int, ev resolvedEvent) (err error) {
+	defer observe("UpdateEvent", time.Now(), &err)
+	return s.Store.UpdateEvent(table, matchID, eventID, ev)
+}
+
+func (s metricsStore) DeleteEvent(table string, matchID, eventID int) (err error) {
+	defer observe("DeleteEvent", time.Now(), &err)
+	return s.Store.DeleteEvent(table, matchID, eventID)
+}
+
+func (s metricsStore) TopScorers(f statsFilter) (stats []ScorerStat, err error) {
+	defer observe("TopScorers", time.Now(), &err)
+	return s.Store.TopScorers(f)
+}
+
+func (s metricsStore) Discipline(f statsFilter) (stats []DisciplineStat, err error) {
+	defer observe("Discipline", time.Now(), &err)
+	return s.Store.Discipline(f)
+}
+
+// Webhooks
+
+func (s metricsStore) Webhooks() (hooks []Webhook, err error) {
+	defer observe("Webhooks", time.Now(), &err)
+	return s.Store.Webhooks()
+}
+
+func (s metricsStore) Webhook(id int) (h Webhook, err error) {
+	defer observe("Webhook", time.Now(), &err)
+	return s.Store.Webhook(id)
+}
+
+func (s metricsStore) ActiveWebhooks(matchID int) (hooks []Webhook, err error) {
+	defer observe("ActiveWebhooks", time.Now(), &err)
+	return s.Store.ActiveWebhooks(matchID)
+}
+
+func (s metricsStore) CreateWebhook(h Webhook) (created Webhook, err error) {
+	defer observe("CreateWebhook", time.Now(), &err)
+	return s.Store.CreateWebhook(h)
+}
+
+func (s metricsStore) DeleteWebhook(id int) (err error) {
+	defer observe("DeleteWebhook", time.Now(), &err)
+	return s.Store.DeleteWebhook(id)
+}
+
+func (s metricsStore) RecordDelivery(d WebhookDelivery) (err error) {
+	defer observe("RecordDelivery", time.Now(), &err)
+	return s.Store.RecordDelivery(d)
+}
+
+func (s metricsStore) Deliveries(webhookID int, success *bool, limit int) (deliveries []WebhookDelivery, err error) {
+	defer observe("Deliveries", time.Now(), &err)
+	return s.Store.Deliveries(webhookID, success, limit)
+}
+
+// Claves de API
+
+func (s metricsStore) APIKeys() (keys []APIKey, err error) {
+	defer observe("APIKeys", time.Now(), &err)
+	return s.Store.APIKeys()
+}
+
+func (s metricsStore) APIKeyByHash(hash string) (k APIKey, err error) {
+	defer observe("APIKeyByHash", time.Now(), &err)
+	return s.Store.APIKeyByHash(hash)
+}
+
+func (s metricsStore) CreateAPIKey(k APIKey) (created APIKey, err error) {
+	defer observe("CreateAPIKey", time.Now(), &err)
+	return s.Store.CreateAPIKey(k)
+}
+
+func (s metricsStore) RevokeAPIKey(id int) (err error) {
+	defer observe("RevokeAPIKey", time.Now(), &err)
+	return s.Store.RevokeAPIKey(id)
+}
+
+// Auditoría
+
+func (s metricsStore) RecordAudit(e AuditEntry) (err error) {
+	defer observe("RecordAudit", time.Now(), &err)
+	return s.Store.RecordAudit(e)
+}
+
+func (s metricsStore) AuditEntries(f auditFilter) (entries []AuditEntry, err error) {
+	defer observe("AuditEntries", time.Now(), &err)
+	return s.Store.AuditEntries(f)
+}
+
+// Salud
+
+func (s metricsStore) Ping(ctx context.Context) (err error) {
+	defer observe("Ping", time.Now(), &err)
+	return s.Store.Ping(ctx)
+}
+
+func (s metricsStore) PendingMigrations(ctx context.Context) (pending int, err error) {
+	defer observe("PendingMigrations", time.Now(), &err)
+	return s.Store.PendingMigrations(ctx)
+}
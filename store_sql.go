@@ -0,0 +1,1158 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// sqlDialect contiene las diferencias de SQL entre SQLite y PostgreSQL.
+// Las consultas se escriben con ? como marcador de parámetros y funciones comunes a ambos;
+// lo que no es común se pide al dialecto.
+type sqlDialect struct {
+	name           string // Nombre del dialecto y del directorio de sus migraciones
+	driver         string // Driver de database/sql
+	numberedParams bool   // PostgreSQL usa $1, $2, ... en lugar de ?
+	now            string // Fecha y hora actual en UTC con el formato "YYYY-MM-DD HH:MM:SS"
+	dateFormat     string // Expresión que toma la fecha (YYYY-MM-DD) de un texto
+	tableExists    string // Consulta que indica si existe la tabla pasada como parámetro
+}
+
+// Dialectos soportados
+var (
+	sqliteDialect = sqlDialect{
+		name:        storeSQLite,
+		driver:      "sqlite3",
+		now:         "datetime('now')",
+		dateFormat:  "date(%s)",
+		tableExists: "SELECT EXISTS(SELECT 1 FROM sqlite_master WHERE type = 'table' AND name = ?)",
+	}
+	postgresDialect = sqlDialect{
+		name:           storePostgres,
+		driver:         "postgres",
+		numberedParams: true,
+		now:            "to_char(now() AT TIME ZONE 'UTC', 'YYYY-MM-DD HH24:MI:SS')",
+		dateFormat:     "LEFT(%s, 10)",
+		tableExists:    "SELECT EXISTS(SELECT 1 FROM information_schema.tables WHERE table_schema = current_schema() AND table_name = ?)",
+	}
+)
+
+// rebind reemplaza los marcadores ? por los del dialecto
+func (d sqlDialect) rebind(query string) string {
+	if !d.numberedParams {
+		return query
+	}
+	var b strings.Builder
+	n := 0
+	for _, c := range query {
+		if c == '?' {
+			n++
+			b.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+		b.WriteRune(c)
+	}
+	return b.String()
+}
+
+// date devuelve la expresión que compara expr como fecha YYYY-MM-DD
+func (d sqlDialect) date(expr string) string {
+	return fmt.Sprintf(d.dateFormat, expr)
+}
+
+// sqlStore implementa Store sobre database/sql. Lo comparten SQLite y PostgreSQL.
+type sqlStore struct {
+	db *sql.DB
+	d  sqlDialect
+}
+
+// q adapta una consulta escrita con ? al dialecto del almacenamiento
+func (s *sqlStore) q(query string) string {
+	return s.d.rebind(query)
+}
+
+// Ping verifica que la base de datos responda
+func (s *sqlStore) Ping(ctx context.Context) error {
+	return s.db.PingContext(ctx)
+}
+
+// Close cierra la conexión con la base de datos
+func (s *sqlStore) Close() error {
+	return s.db.Close()
+}
+
+// notFound traduce sql.ErrNoRows al error común errNotFound
+func notFound(err error) error {
+	if err == sql.ErrNoRows {
+		return errNotFound
+	}
+	return err
+}
+
+// affected devuelve errNotFound si la sentencia no modificó ninguna fila
+func affected(res sql.Result, err error) error {
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return errNotFound
+	}
+	return nil
+}
+
+// eventTables son las tablas de eventos de un partido
+var eventTables = []string{"goals", "yellow_cards", "red_cards"}
+
+// ================================================================
+// Equipos
+// ================================================================
+
+// teamColumns son las columnas que se seleccionan para construir un Team
+const teamColumns = "id, name, short_name, city, stadium, founded_year"
+
+// scanTeam escanea una fila con las columnas de teamColumns en un Team
+func scanTeam(row interface{ Scan(...any) error }) (Team, error) {
+	var t Team
+	err := row.Scan(&t.ID, &t.Name, &t.ShortName, &t.City, &t.Stadium, &t.FoundedYear)
+	return t, err
+}
+
+// Teams devuelve los equipos ordenados por nombre sin distinguir mayúsculas.
+// Se usa LOWER porque PostgreSQL no tiene la intercalación NOCASE de SQLite.
+func (s *sqlStore) Teams(sc matchScope) ([]Team, error) {
+	query := "SELECT " + teamColumns + " FROM teams"
+	var args []any
+	if scope, scopeArgs := scopeWhere(sc); scope != "" {
+		// Equipos que jugaron de local o de visitante en algún partido de la competición o temporada
+		query += " WHERE id IN (SELECT m.home_team_id FROM matches m WHERE 1=1" + scope +
+			" UNION SELECT m.away_team_id FROM matches m WHERE 1=1" + scope + ")"
+		args = append(scopeArgs, scopeArgs...)
+	}
+	rows, err := s.db.Query(s.q(query+" ORDER BY LOWER(name)"), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	teams := []Team{}
+	for rows.Next() {
+		t, err := scanTeam(rows)
+		if err != nil {
+			return nil, err
+		}
+		teams = append(teams, t)
+	}
+	return teams, rows.Err()
+}
+
+// Team devuelve un equipo por ID
+func (s *sqlStore) Team(id int) (Team, error) {
+	t, err := scanTeam(s.db.QueryRow(s.q("SELECT "+teamColumns+" FROM teams WHERE id = ?"), id))
+	return t, notFound(err)
+}
+
+// TeamByName busca un equipo por nombre o nombre corto sin distinguir mayúsculas
+func (s *sqlStore) TeamByName(name string) (Team, error) {
+	t, err := scanTeam(s.db.QueryRow(s.q(`SELECT `+teamColumns+` FROM teams
+		WHERE LOWER(name) = LOWER(?) OR LOWER(short_name) = LOWER(?)
+		ORDER BY LOWER(name) = LOWER(?) DESC LIMIT 1`), name, name, name))
+	return t, notFound(err)
+}
+
+// CreateTeam inserta un equipo y devuelve su ID
+func (s *sqlStore) CreateTeam(t Team) (int, error) {
+	var id int
+	err := s.db.QueryRow(s.q(`INSERT INTO teams (name, short_name, city, stadium, founded_year) VALUES (?, ?, ?, ?, ?) RETURNING id`),
+		t.Name, t.ShortName, t.City, t.Stadium, t.FoundedYear).Scan(&id)
+	return id, err
+}
+
+// UpdateTeam actualiza los datos de un equipo
+func (s *sqlStore) UpdateTeam(t Team) error {
+	return affected(s.db.Exec(s.q(`UPDATE teams SET name=?, short_name=?, city=?, stadium=?, founded_year=? WHERE id=?`),
+		t.Name, t.ShortName, t.City, t.Stadium, t.FoundedYear, t.ID))
+}
+
+// DeleteTeam elimina un equipo sin partidos junto con su plantilla
+func (s *sqlStore) DeleteTeam(id int) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// No permitir eliminar equipos que ya disputaron o tienen partidos programados
+	var inUse bool
+	if err := tx.QueryRow(s.q("SELECT EXISTS(SELECT 1 FROM matches WHERE home_team_id = ? OR away_team_id = ?)"), id, id).Scan(&inUse); err != nil {
+		return err
+	}
+	if inUse {
+		return errTeamHasMatches
+	}
+
+	// Los jugadores transferidos pueden tener eventos con otros equipos; esos eventos
+	// quedan como texto libre para que las claves foráneas no impidan eliminarlos
+	if err := s.unlinkPlayerEvents(tx, "team_id = ?", id); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(s.q("DELETE FROM players WHERE team_id = ?"), id); err != nil {
+		return err
+	}
+
+	if err := affected(tx.Exec(s.q("DELETE FROM teams WHERE id = ?"), id)); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// ================================================================
+// Jugadores
+// ================================================================
+
+// playerColumns son las columnas que se seleccionan para construir un Player
+const playerColumns = "id, team_id, name, shirt_number, position, nationality"
+
+// scanPlayer escanea una fila con las columnas de playerColumns en un Player
+func scanPlayer(row interface{ Scan(...any) error }) (Player, error) {
+	var p Player
+	err := row.Scan(&p.ID, &p.TeamID, &p.Name, &p.ShirtNumber, &p.Position, &p.Nationality)
+	return p, err
+}
+
+// Players devuelve la plantilla de un equipo
+func (s *sqlStore) Players(teamID int) ([]Player, error) {
+	rows, err := s.db.Query(s.q("SELECT "+playerColumns+" FROM players WHERE team_id = ? ORDER BY shirt_number, name"), teamID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	players := []Player{}
+	for rows.Next() {
+		p, err := scanPlayer(rows)
+		if err != nil {
+			return nil, err
+		}
+		players = append(players, p)
+	}
+	return players, rows.Err()
+}
+
+// Player devuelve un jugador por ID
+func (s *sqlStore) Player(id int) (Player, error) {
+	p, err := scanPlayer(s.db.QueryRow(s.q("SELECT "+playerColumns+" FROM players WHERE id = ?"), id))
+	return p, notFound(err)
+}
+
+// PlayerByName busca un jugador de la plantilla por nombre
+func (s *sqlStore) PlayerByName(teamID int, name string) (Player, error) {
+	p, err := scanPlayer(s.db.QueryRow(s.q("SELECT "+playerColumns+" FROM players WHERE team_id = ? AND LOWER(name) = LOWER(?) ORDER BY id LIMIT 1"),
+		teamID, name))
+	return p, notFound(err)
+}
+
+// ShirtNumberTaken indica si otro jugador del equipo ya usa el dorsal
+func (s *sqlStore) ShirtNumberTaken(teamID, number, exceptID int) (bool, error) {
+	var taken bool
+	err := s.db.QueryRow(s.q("SELECT EXISTS(SELECT 1 FROM players WHERE team_id = ? AND shirt_number = ? AND id <> ?)"),
+		teamID, number, exceptID).Scan(&taken)
+	return taken, err
+}
+
+// CreatePlayer inserta un jugador y devuelve su ID
+func (s *sqlStore) CreatePlayer(p Player) (int, error) {
+	var id int
+	err := s.db.QueryRow(s.q(`INSERT INTO players (team_id, name, shirt_number, position, nationality) VALUES (?, ?, ?, ?, ?) RETURNING id`),
+		p.TeamID, p.Name, p.ShirtNumber, p.Position, p.Nationality).Scan(&id)
+	return id, err
+}
+
+// UpdatePlayer actualiza un jugador del equipo teamID
+func (s *sqlStore) UpdatePlayer(teamID int, p Player) error {
+	return affected(s.db.Exec(s.q(`UPDATE players SET team_id=?, name=?, shirt_number=?, position=?, nationality=? WHERE id=? AND team_id=?`),
+		p.TeamID, p.Name, p.ShirtNumber, p.Position, p.Nationality, p.ID, teamID))
+}
+
+// DeletePlayer elimina un jugador del equipo dejando sus eventos como texto libre
+func (s *sqlStore) DeletePlayer(teamID, id int) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Verificar que el jugador pertenezca al equipo antes de tocar sus eventos
+	var exists bool
+	if err := tx.QueryRow(s.q("SELECT EXISTS(SELECT 1 FROM players WHERE id = ? AND team_id = ?)"), id, teamID).Scan(&exists); err != nil {
+		return err
+	}
+	if !exists {
+		return errNotFound
+	}
+
+	// Desenlazar los eventos del jugador; el nombre queda guardado en cada evento.
+	// Se hace antes de eliminarlo para que las claves foráneas no lo impidan.
+	if err := s.unlinkPlayerEvents(tx, "id = ?", id); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(s.q("DELETE FROM players WHERE id = ? AND team_id = ?"), id, teamID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// unlinkPlayerEvents deja como texto libre los eventos de los jugadores que cumplen la condición
+func (s *sqlStore) unlinkPlayerEvents(tx *sql.Tx, where string, args ...any) error {
+	for _, table := range eventTables {
+		_, err := tx.Exec(s.q("UPDATE "+table+" SET player_id = NULL WHERE player_id IN (SELECT id FROM players WHERE "+where+")"), args...)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ================================================================
+// Competiciones y temporadas
+// ================================================================
+
+// competitionColumns son las columnas que se seleccionan para construir una Competition
+const competitionColumns = "id, name, country, type"
+
+// scanCompetition escanea una fila con las columnas de competitionColumns en una Competition
+func scanCompetition(row interface{ Scan(...any) error }) (Competition, error) {
+	var c Competition
+	err := row.Scan(&c.ID, &c.Name, &c.Country, &c.Type)
+	return c, err
+}
+
+// Competitions devuelve todas las competiciones ordenadas por nombre sin distinguir mayúsculas
+func (s *sqlStore) Competitions() ([]Competition, error) {
+	rows, err := s.db.Query("SELECT " + competitionColumns + " FROM competitions ORDER BY LOWER(name)")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	competitions := []Competition{}
+	for rows.Next() {
+		c, err := scanCompetition(rows)
+		if err != nil {
+			return nil, err
+		}
+		competitions = append(competitions, c)
+	}
+	return competitions, rows.Err()
+}
+
+// Competition devuelve una competición por ID
+func (s *sqlStore) Competition(id int) (Competition, error) {
+	c, err := scanCompetition(s.db.QueryRow(s.q("SELECT "+competitionColumns+" FROM competitions WHERE id = ?"), id))
+	return c, notFound(err)
+}
+
+// CompetitionByName busca una competición por nombre sin distinguir mayúsculas
+func (s *sqlStore) CompetitionByName(name string) (Competition, error) {
+	c, err := scanCompetition(s.db.QueryRow(s.q("SELECT "+competitionColumns+" FROM competitions WHERE LOWER(name) = LOWER(?)"), name))
+	return c, notFound(err)
+}
+
+// CreateCompetition inserta una competición y devuelve su ID
+func (s *sqlStore) CreateCompetition(c Competition) (int, error) {
+	var id int
+	err := s.db.QueryRow(s.q(`INSERT INTO competitions (name, country, type) VALUES (?, ?, ?) RETURNING id`),
+		c.Name, c.Country, c.Type).Scan(&id)
+	return id, err
+}
+
+// UpdateCompetition actualiza los datos de una competición
+func (s *sqlStore) UpdateCompetition(c Competition) error {
+	return affected(s.db.Exec(s.q(`UPDATE competitions SET name=?, country=?, type=? WHERE id=?`),
+		c.Name, c.Country, c.Type, c.ID))
+}
+
+// DeleteCompetition elimina una competición sin temporadas
+func (s *sqlStore) DeleteCompetition(id int) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var inUse bool
+	if err := tx.QueryRow(s.q("SELECT EXISTS(SELECT 1 FROM seasons WHERE competition_id = ?)"), id).Scan(&inUse); err != nil {
+		return err
+	}
+	if inUse {
+		return errCompetitionHasSeasons
+	}
+
+	if err := affected(tx.Exec(s.q("DELETE FROM competitions WHERE id = ?"), id)); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// seasonSelect es la consulta base para obtener temporadas junto con el nombre de su competición
+const seasonSelect = `SELECT s.id, s.competition_id, c.name, s.name, s.start_date, s.end_date
+	FROM seasons s
+	JOIN competitions c ON c.id = s.competition_id`
+
+// scanSeason escanea una fila de seasonSelect en una Season
+func scanSeason(row interface{ Scan(...any) error }) (Season, error) {
+	var se Season
+	err := row.Scan(&se.ID, &se.CompetitionID, &se.Competition, &se.Name, &se.StartDate, &se.EndDate)
+	return se, err
+}
+
+// Seasons devuelve las temporadas de una competición ordenadas por fecha de inicio
+func (s *sqlStore) Seasons(competitionID int) ([]Season, error) {
+	rows, err := s.db.Query(s.q(seasonSelect+" WHERE s.competition_id = ? ORDER BY s.start_date, s.id"), competitionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	seasons := []Season{}
+	for rows.Next() {
+		se, err := scanSeason(rows)
+		if err != nil {
+			return nil, err
+		}
+		seasons = append(seasons, se)
+	}
+	return seasons, rows.Err()
+}
+
+// Season devuelve una temporada por ID con el nombre de su competición
+func (s *sqlStore) Season(id int) (Season, error) {
+	se, err := scanSeason(s.db.QueryRow(s.q(seasonSelect+" WHERE s.id = ?"), id))
+	return se, notFound(err)
+}
+
+// CreateSeason inserta una temporada y devuelve su ID
+func (s *sqlStore) CreateSeason(se Season) (int, error) {
+	var id int
+	err := s.db.QueryRow(s.q(`INSERT INTO seasons (competition_id, name, start_date, end_date) VALUES (?, ?, ?, ?) RETURNING id`),
+		se.CompetitionID, se.Name, se.StartDate, se.EndDate).Scan(&id)
+	return id, err
+}
+
+// UpdateSeason actualiza el nombre y las fechas de una temporada; la competición no cambia
+func (s *sqlStore) UpdateSeason(se Season) error {
+	return affected(s.db.Exec(s.q(`UPDATE seasons SET name=?, start_date=?, end_date=? WHERE id=?`),
+		se.Name, se.StartDate, se.EndDate, se.ID))
+}
+
+// DeleteSeason elimina una temporada sin partidos
+func (s *sqlStore) DeleteSeason(id int) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var inUse bool
+	if err := tx.QueryRow(s.q("SELECT EXISTS(SELECT 1 FROM matches WHERE season_id = ?)"), id).Scan(&inUse); err != nil {
+		return err
+	}
+	if inUse {
+		return errSeasonHasMatches
+	}
+
+	if err := affected(tx.Exec(s.q("DELETE FROM seasons WHERE id = ?"), id)); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// ================================================================
+// Partidos
+// ================================================================
+
+// matchSelect es la consulta base para obtener partidos junto con los nombres de sus equipos
+const matchSelect = `SELECT m.id, m.home_team_id, ht.name, m.away_team_id, at.name, m.match_date, m.extra_time, m.status,
+	COALESCE(m.season_id, 0)
+	FROM matches m
+	JOIN teams ht ON ht.id = m.home_team_id
+	JOIN teams at ON at.id = m.away_team_id`
+
+// matchSummarySelect obtiene partidos junto con sus goles y tarjetas por equipo en una sola consulta.
+// Recibe con fmt.Sprintf la consulta que elige los IDs de los partidos (la CTE page), de modo que los
+// eventos de las tres tablas se agrupan una única vez y solo para esos partidos, en lugar de contar por cada fila.
+// El filtro se repite en cada tabla de eventos para que se usen sus índices por partido.
+const matchSummarySelect = `WITH page(id) AS (%s)
+	SELECT m.id, m.home_team_id, ht.name, m.away_team_id, at.name, m.match_date, m.extra_time, m.status,
+	COALESCE(m.season_id, 0),
+	COALESCE(c.home_goals, 0), COALESCE(c.away_goals, 0),
+	COALESCE(c.home_yellow, 0), COALESCE(c.away_yellow, 0),
+	COALESCE(c.home_red, 0), COALESCE(c.away_red, 0)
+	FROM matches m
+	JOIN teams ht ON ht.id = m.home_team_id
+	JOIN teams at ON at.id = m.away_team_id
+	LEFT JOIN (
+		SELECT e.match_id,
+			SUM(CASE WHEN e.kind = 'goal' AND e.team_id = em.home_team_id THEN 1 ELSE 0 END) AS home_goals,
+			SUM(CASE WHEN e.kind = 'goal' AND e.team_id = em.away_team_id THEN 1 ELSE 0 END) AS away_goals,
+			SUM(CASE WHEN e.kind = 'yellow' AND e.team_id = em.home_team_id THEN 1 ELSE 0 END) AS home_yellow,
+			SUM(CASE WHEN e.kind = 'yellow' AND e.team_id = em.away_team_id THEN 1 ELSE 0 END) AS away_yellow,
+			SUM(CASE WHEN e.kind = 'red' AND e.team_id = em.home_team_id THEN 1 ELSE 0 END) AS home_red,
+			SUM(CASE WHEN e.kind = 'red' AND e.team_id = em.away_team_id THEN 1 ELSE 0 END) AS away_red
+		FROM (
+			SELECT match_id, team_id, 'goal' AS kind FROM goals WHERE match_id IN (SELECT id FROM page)
+			UNION ALL SELECT match_id, team_id, 'yellow' FROM yellow_cards WHERE match_id IN (SELECT id FROM page)
+			UNION ALL SELECT match_id, team_id, 'red' FROM red_cards WHERE match_id IN (SELECT id FROM page)
+		) e
+		JOIN matches em ON em.id = e.match_id
+		GROUP BY e.match_id
+	) c ON c.match_id = m.id
+	WHERE m.id IN (SELECT id FROM page)`
+
+// scanMatch escanea una fila de matchSelect en un Match
+func scanMatch(row interface{ Scan(...any) error }) (Match, error) {
+	var m Match
+	err := row.Scan(&m.ID, &m.HomeTeamID, &m.HomeTeam, &m.AwayTeamID, &m.AwayTeam, &m.MatchDate, &m.ExtraTime, &m.Status, &m.SeasonID)
+	return m, err
+}
+
+// scanMatchSummary escanea una fila de matchSummarySelect en un FullMatchData
+func scanMatchSummary(row interface{ Scan(...any) error }) (FullMatchData, error) {
+	var m FullMatchData
+	err := row.Scan(&m.ID, &m.HomeTeamID, &m.HomeTeam, &m.AwayTeamID, &m.AwayTeam, &m.MatchDate, &m.ExtraTime, &m.Status, &m.SeasonID,
+		&m.HomeGoals, &m.AwayGoals,
+		&m.HomeYellowCardsCount, &m.AwayYellowCardsCount,
+		&m.HomeRedCardsCount, &m.AwayRedCardsCount)
+	return m, err
+}
+
+// scopeWhere devuelve la condición SQL, que empieza con AND, y los argumentos para limitar
+// los partidos a una temporada o a las temporadas de una competición.
+// Se asume que la tabla de partidos tiene el alias m.
+func scopeWhere(sc matchScope) (string, []any) {
+	switch {
+	case sc.SeasonID != 0:
+		return " AND m.season_id = ?", []any{sc.SeasonID}
+	case sc.CompetitionID != 0:
+		return " AND m.season_id IN (SELECT id FROM seasons WHERE competition_id = ?)", []any{sc.CompetitionID}
+	}
+	return "", nil
+}
+
+// matchListWhere construye la condición SQL y los argumentos para los filtros del listado.
+// Se asume que la tabla de partidos tiene el alias m.
+func (s *sqlStore) matchListWhere(lq matchListQuery) (string, []any) {
+	scope, args := scopeWhere(lq.matchScope)
+	clause := "WHERE 1=1" + scope
+	if lq.From != "" {
+		clause += " AND " + s.d.date("m.match_date") + " >= " + s.d.date("?")
+		args = append(args, lq.From)
+	}
+	if lq.To != "" {
+		clause += " AND " + s.d.date("m.match_date") + " <= " + s.d.date("?")
+		args = append(args, lq.To)
+	}
+	if lq.TeamID != 0 {
+		clause += " AND (m.home_team_id = ? OR m.away_team_id = ?)"
+		args = append(args, lq.TeamID, lq.TeamID)
+	}
+	if len(lq.Statuses) > 0 {
+		clause += " AND m.status IN (?" + strings.Repeat(", ?", len(lq.Statuses)-1) + ")"
+		for _, st := range lq.Statuses {
+			args = append(args, st)
+		}
+	}
+	return clause, args
+}
+
+// Matches devuelve una página de partidos con sus goles y tarjetas y el total que cumple los filtros
+func (s *sqlStore) Matches(lq matchListQuery) ([]FullMatchData, int, error) {
+	where, args := s.matchListWhere(lq)
+
+	// Contar el total de partidos que cumplen los filtros
+	var total int
+	if err := s.db.QueryRow(s.q("SELECT COUNT(*) FROM matches m "+where), args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	// Obtener los partidos de la página con sus goles y tarjetas
+	page := "SELECT m.id FROM matches m " + where + " ORDER BY " + lq.orderBy() + " LIMIT ? OFFSET ?"
+	rows, err := s.db.Query(s.q(fmt.Sprintf(matchSummarySelect, page)+" ORDER BY "+lq.orderBy()), append(args, lq.Limit, lq.offset())...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	matches := []FullMatchData{}
+	for rows.Next() {
+		m, err := scanMatchSummary(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		matches = append(matches, m)
+	}
+	return matches, total, rows.Err()
+}
+
+// Match devuelve los datos básicos de un partido
+func (s *sqlStore) Match(id int) (Match, error) {
+	m, err := scanMatch(s.db.QueryRow(s.q(matchSelect+" WHERE m.id = ?"), id))
+	return m, notFound(err)
+}
+
+// MatchSummary devuelve un partido con sus goles y tarjetas por equipo
+func (s *sqlStore) MatchSummary(id int) (FullMatchData, error) {
+	m, err := scanMatchSummary(s.db.QueryRow(s.q(fmt.Sprintf(matchSummarySelect, "SELECT CAST(? AS INTEGER)")), id))
+	return m, notFound(err)
+}
+
+// seasonArg devuelve la temporada de un partido para guardarla, o NULL si no tiene
+func seasonArg(m Match) any {
+	if m.SeasonID == 0 {
+		return nil
+	}
+	return m.SeasonID
+}
+
+// CreateMatch inserta un partido; el tiempo extra y el estado toman sus valores por defecto
+func (s *sqlStore) CreateMatch(m Match) (int, error) {
+	var id int
+	err := s.db.QueryRow(s.q(`INSERT INTO matches (home_team_id, away_team_id, match_date, season_id) VALUES (?, ?, ?, ?) RETURNING id`),
+		m.HomeTeamID, m.AwayTeamID, m.MatchDate, seasonArg(m)).Scan(&id)
+	return id, err
+}
+
+// CreateSeasonFixtures inserta el calendario de una temporada vacía en una sola transacción;
+// si algún partido falla no se inserta ninguno
+func (s *sqlStore) CreateSeasonFixtures(seasonID int, ms []Match) ([]int, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// Tomar el bloqueo de escritura antes de contar los partidos, para que dos calendarios
+	// simultáneos no vean ambos la temporada vacía: PostgreSQL bloquea la fila de la temporada
+	// y SQLite la base entera, y la otra transacción espera hasta el commit
+	if err := affected(tx.Exec(s.q("UPDATE seasons SET name = name WHERE id = ?"), seasonID)); err != nil {
+		return nil, err
+	}
+	var inUse bool
+	if err := tx.QueryRow(s.q("SELECT EXISTS(SELECT 1 FROM matches WHERE season_id = ?)"), seasonID).Scan(&inUse); err != nil {
+		return nil, err
+	}
+	if inUse {
+		return nil, errSeasonHasMatches
+	}
+
+	stmt, err := tx.Prepare(s.q(`INSERT INTO matches (home_team_id, away_team_id, match_date, season_id) VALUES (?, ?, ?, ?) RETURNING id`))
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+
+	ids := make([]int, len(ms))
+	for i, m := range ms {
+		if err := stmt.QueryRow(m.HomeTeamID, m.AwayTeamID, m.MatchDate, seasonID).Scan(&ids[i]); err != nil {
+			return nil, err
+		}
+	}
+	return ids, tx.Commit()
+}
+
+// UpdateMatch actualiza los equipos, la fecha y la temporada de un partido
+func (s *sqlStore) UpdateMatch(m Match) error {
+	return affected(s.db.Exec(s.q(`UPDATE matches SET home_team_id=?, away_team_id=?, match_date=?, season_id=? WHERE id=?`),
+		m.HomeTeamID, m.AwayTeamID, m.MatchDate, seasonArg(m), m.ID))
+}
+
+// DeleteMatch elimina un partido y sus eventos en una sola transacción
+func (s *sqlStore) DeleteMatch(id int) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Las claves foráneas ya eliminan los eventos en cascada; se borran también de forma
+	// explícita para no dejar huérfanos en bases que todavía no aplicaron la migración
+	for _, table := range eventTables {
+		if _, err := tx.Exec(s.q("DELETE FROM "+table+" WHERE match_id = ?"), id); err != nil {
+			return err
+		}
+	}
+
+	if err := affected(tx.Exec(s.q("DELETE FROM matches WHERE id=?"), id)); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// SetExtraTime actualiza el tiempo extra de un partido
+func (s *sqlStore) SetExtraTime(id int, extraTime string) error {
+	return affected(s.db.Exec(s.q("UPDATE matches SET extra_time=? WHERE id=?"), extraTime, id))
+}
+
+// MatchStatus obtiene el estado actual de un partido y la fecha de su último cambio
+func (s *sqlStore) MatchStatus(id int) (string, time.Time, error) {
+	var status, updatedAt string
+	err := s.db.QueryRow(s.q("SELECT status, status_updated_at FROM matches WHERE id = ?"), id).Scan(&status, &updatedAt)
+	if err != nil {
+		return "", time.Time{}, notFound(err)
+	}
+
+	// Ambos dialectos guardan la fecha como "YYYY-MM-DD HH:MM:SS" en UTC
+	t, _ := time.Parse(time.DateTime, updatedAt)
+	return status, t, nil
+}
+
+// SetMatchStatus actualiza el estado solo si nadie lo cambió mientras tanto
+func (s *sqlStore) SetMatchStatus(id int, from, to string) error {
+	err := affected(s.db.Exec(s.q("UPDATE matches SET status=?, status_updated_at="+s.d.now+" WHERE id=? AND status=?"), to, id, from))
+	if err == errNotFound {
+		return errStatusChanged
+	}
+	return err
+}
+
+// FinishedResults obtiene el marcador de cada partido terminado de la competición o temporada
+func (s *sqlStore) FinishedResults(sc matchScope) ([]matchResult, error) {
+	// Contar los goles de cada equipo con subconsultas
+	// para resolver todos los marcadores en una sola consulta
+	scope, args := scopeWhere(sc)
+	rows, err := s.db.Query(s.q(`
+		SELECT ht.name, at.name,
+		       (SELECT COUNT(*) FROM goals g WHERE g.match_id = m.id AND g.team_id = m.home_team_id),
+		       (SELECT COUNT(*) FROM goals g WHERE g.match_id = m.id AND g.team_id = m.away_team_id)
+		FROM matches m
+		JOIN teams ht ON ht.id = m.home_team_id
+		JOIN teams at ON at.id = m.away_team_id
+		WHERE m.status = 'finished'`+scope), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []matchResult
+	for rows.Next() {
+		var res matchResult
+		if err := rows.Scan(&res.HomeTeam, &res.AwayTeam, &res.HomeGoals, &res.AwayGoals); err != nil {
+			return nil, err
+		}
+		results = append(results, res)
+	}
+	return results, rows.Err()
+}
+
+// ================================================================
+// Eventos
+// ================================================================
+
+// MatchEvents obtiene en una sola consulta los goles, tarjetas amarillas y rojas de un partido
+func (s *sqlStore) MatchEvents(matchID int) (map[string][]MatchEvent, error) {
+	rows, err := s.db.Query(s.q(`SELECT e.kind, e.id, e.team_id, t.name, COALESCE(e.player_id, 0), e.player, e.minute
+		FROM (
+			SELECT 'goals' AS kind, id, match_id, team_id, player_id, player, minute FROM goals
+			UNION ALL SELECT 'yellow_cards', id, match_id, team_id, player_id, player, minute FROM yellow_cards
+			UNION ALL SELECT 'red_cards', id, match_id, team_id, player_id, player, minute FROM red_cards
+		) e
+		JOIN teams t ON t.id = e.team_id
+		WHERE e.match_id = ?
+		ORDER BY e.id`), matchID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := map[string][]MatchEvent{}
+	for rows.Next() {
+		var table string
+		var e MatchEvent
+		if err := rows.Scan(&table, &e.ID, &e.TeamID, &e.Team, &e.PlayerID, &e.Player, &e.Minute); err != nil {
+			return nil, err
+		}
+		events[table] = append(events[table], e)
+	}
+	return events, rows.Err()
+}
+
+// Event obtiene un evento de la tabla indicada, solo si pertenece al partido
+func (s *sqlStore) Event(table string, matchID, eventID int) (MatchEvent, error) {
+	var e MatchEvent
+	err := s.db.QueryRow(s.q("SELECT e.id, e.team_id, t.name, COALESCE(e.player_id, 0), e.player, e.minute FROM "+table+
+		" e JOIN teams t ON t.id = e.team_id WHERE e.id = ? AND e.match_id = ?"), eventID, matchID).
+		Scan(&e.ID, &e.TeamID, &e.Team, &e.PlayerID, &e.Player, &e.Minute)
+	return e, notFound(err)
+}
+
+// CreateEvent inserta un evento en la tabla indicada y devuelve su ID
+func (s *sqlStore) CreateEvent(table string, matchID int, ev resolvedEvent) (int, error) {
+	var id int
+	err := s.db.QueryRow(s.q("INSERT INTO "+table+" (match_id, team_id, player_id, player, minute) VALUES (?, ?, ?, ?, ?) RETURNING id"),
+		matchID, ev.TeamID, ev.PlayerID, ev.Player, ev.Minute).Scan(&id)
+	return id, err
+}
+
+// UpdateEvent corrige un evento del partido
+func (s *sqlStore) UpdateEvent(table string, matchID, eventID int, ev resolvedEvent) error {
+	return affected(s.db.Exec(s.q("UPDATE "+table+" SET team_id=?, player_id=?, player=?, minute=? WHERE id=? AND match_id=?"),
+		ev.TeamID, ev.PlayerID, ev.Player, ev.Minute, eventID, matchID))
+}
+
+// DeleteEvent elimina un evento del partido
+func (s *sqlStore) DeleteEvent(table string, matchID, eventID int) error {
+	return affected(s.db.Exec(s.q("DELETE FROM "+table+" WHERE id=? AND match_id=?"), eventID, matchID))
+}
+
+// statsWhere construye la condición SQL y los argumentos para los filtros de estadísticas.
+// Se asume que la tabla de eventos tiene el alias e y la de partidos el alias m.
+func (s *sqlStore) statsWhere(f statsFilter) (string, []any) {
+	scope, args := scopeWhere(f.matchScope)
+	clause := "WHERE 1=1" + scope
+	if f.From != "" {
+		clause += " AND " + s.d.date("m.match_date") + " >= " + s.d.date("?")
+		args = append(args, f.From)
+	}
+	if f.To != "" {
+		clause += " AND " + s.d.date("m.match_date") + " <= " + s.d.date("?")
+		args = append(args, f.To)
+	}
+	if f.TeamID != 0 {
+		clause += " AND e.team_id = ?"
+		args = append(args, f.TeamID)
+	}
+	return clause, args
+}
+
+// playerKey es la expresión que identifica a un jugador al agrupar eventos.
+// Los jugadores registrados se agrupan por ID y los de texto libre por nombre,
+// sin distinguir mayúsculas ni espacios sobrantes.
+const playerKey = "COALESCE('id:' || e.player_id, 'name:' || LOWER(TRIM(e.player)))"
+
+// TopScorers agrupa los goles por jugador y equipo
+func (s *sqlStore) TopScorers(f statsFilter) ([]ScorerStat, error) {
+	where, args := s.statsWhere(f)
+	rows, err := s.db.Query(s.q(`
+		SELECT MAX(COALESCE(e.player_id, 0)), MIN(COALESCE(p.name, TRIM(e.player))), e.team_id, MIN(t.name), COUNT(*) AS goals
+		FROM goals e
+		JOIN matches m ON m.id = e.match_id
+		JOIN teams t ON t.id = e.team_id
+		LEFT JOIN players p ON p.id = e.player_id
+		`+where+`
+		GROUP BY e.team_id, `+playerKey+`
+		ORDER BY goals DESC, 2
+		LIMIT ?`), append(args, f.Limit)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	scorers := []ScorerStat{}
+	for rows.Next() {
+		var sc ScorerStat
+		if err := rows.Scan(&sc.PlayerID, &sc.Player, &sc.TeamID, &sc.Team, &sc.Goals); err != nil {
+			return nil, err
+		}
+		sc.Rank = len(scorers) + 1
+		scorers = append(scorers, sc)
+	}
+	return scorers, rows.Err()
+}
+
+// Discipline agrupa las tarjetas amarillas y rojas por jugador y equipo
+func (s *sqlStore) Discipline(f statsFilter) ([]DisciplineStat, error) {
+	// Unir ambas tablas de tarjetas marcando cuál es amarilla y cuál es roja
+	where, args := s.statsWhere(f)
+	rows, err := s.db.Query(s.q(`
+		SELECT MAX(COALESCE(e.player_id, 0)), MIN(COALESCE(p.name, TRIM(e.player))), e.team_id, MIN(t.name),
+		       SUM(e.yellow) AS yellows, SUM(e.red) AS reds
+		FROM (
+			SELECT match_id, team_id, player_id, player, 1 AS yellow, 0 AS red FROM yellow_cards
+			UNION ALL
+			SELECT match_id, team_id, player_id, player, 0 AS yellow, 1 AS red FROM red_cards
+		) e
+		JOIN matches m ON m.id = e.match_id
+		JOIN teams t ON t.id = e.team_id
+		LEFT JOIN players p ON p.id = e.player_id
+		`+where+`
+		GROUP BY e.team_id, `+playerKey+`
+		ORDER BY reds DESC, yellows DESC, 2
+		LIMIT ?`), append(args, f.Limit)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	discipline := []DisciplineStat{}
+	for rows.Next() {
+		var d DisciplineStat
+		if err := rows.Scan(&d.PlayerID, &d.Player, &d.TeamID, &d.Team, &d.YellowCards, &d.RedCards); err != nil {
+			return nil, err
+		}
+		d.Rank = len(discipline) + 1
+		discipline = append(discipline, d)
+	}
+	return discipline, rows.Err()
+}
+
+// ================================================================
+// Webhooks
+// ================================================================
+
+// webhookColumns son las columnas que se seleccionan para construir un Webhook
+const webhookColumns = "id, url, events, COALESCE(match_id, 0), COALESCE(team_id, 0), secret, active, created_at"
+
+// scanWebhook escanea una fila con las columnas de webhookColumns en un Webhook
+func scanWebhook(row interface{ Scan(...any) error }) (Webhook, error) {
+	var h Webhook
+	var events string
+	err := row.Scan(&h.ID, &h.URL, &events, &h.MatchID, &h.TeamID, &h.Secret, &h.Active, &h.CreatedAt)
+	if events != "" {
+		h.Events = strings.Split(events, ",")
+	}
+	return h, err
+}
+
+// queryWebhooks ejecuta una consulta de webhooks y escanea todas sus filas
+func (s *sqlStore) queryWebhooks(query string, args ...any) ([]Webhook, error) {
+	rows, err := s.db.Query(s.q(query), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	hooks := []Webhook{}
+	for rows.Next() {
+		h, err := scanWebhook(rows)
+		if err != nil {
+			return nil, err
+		}
+		hooks = append(hooks, h)
+	}
+	return hooks, rows.Err()
+}
+
+// Webhooks devuelve todos los webhooks
+func (s *sqlStore) Webhooks() ([]Webhook, error) {
+	return s.queryWebhooks("SELECT " + webhookColumns + " FROM webhooks ORDER BY id")
+}
+
+// Webhook devuelve un webhook por ID
+func (s *sqlStore) Webhook(id int) (Webhook, error) {
+	h, err := scanWebhook(s.db.QueryRow(s.q("SELECT "+webhookColumns+" FROM webhooks WHERE id = ?"), id))
+	return h, notFound(err)
+}
+
+// ActiveWebhooks devuelve los webhooks activos que pueden interesarse en el partido
+func (s *sqlStore) ActiveWebhooks(matchID int) ([]Webhook, error) {
+	return s.queryWebhooks("SELECT "+webhookColumns+" FROM webhooks WHERE active = ? AND (match_id IS NULL OR match_id = ?)", true, matchID)
+}
+
+// CreateWebhook inserta un webhook; los filtros en 0 se guardan como NULL
+func (s *sqlStore) CreateWebhook(h Webhook) (Webhook, error) {
+	var matchID, teamID any
+	if h.MatchID != 0 {
+		matchID = h.MatchID
+	}
+	if h.TeamID != 0 {
+		teamID = h.TeamID
+	}
+
+	var id int
+	err := s.db.QueryRow(s.q(`INSERT INTO webhooks (url, events, match_id, team_id, secret) VALUES (?, ?, ?, ?, ?) RETURNING id`),
+		h.URL, strings.Join(h.Events, ","), matchID, teamID, h.Secret).Scan(&id)
+	if err != nil {
+		return Webhook{}, err
+	}
+	return s.Webhook(id)
+}
+
+// DeleteWebhook elimina un webhook junto con su historial de entregas
+func (s *sqlStore) DeleteWebhook(id int) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(s.q("DELETE FROM webhook_deliveries WHERE webhook_id = ?"), id); err != nil {
+		return err
+	}
+	if err := affected(tx.Exec(s.q("DELETE FROM webhooks WHERE id = ?"), id)); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// RecordDelivery registra un intento de entrega
+func (s *sqlStore) RecordDelivery(d WebhookDelivery) error {
+	_, err := s.db.Exec(s.q(`INSERT INTO webhook_deliveries
+		(webhook_id, delivery_id, event_id, event_type, match_id, attempt, status_code, success, error, duration_ms)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`),
+		d.WebhookID, d.DeliveryID, d.EventID, d.EventType, d.MatchID, d.Attempt,
+		d.StatusCode, d.Success, d.Error, d.DurationMs)
+	return err
+}
+
+// Deliveries devuelve los intentos de entrega de un webhook
+func (s *sqlStore) Deliveries(webhookID int, success *bool, limit int) ([]WebhookDelivery, error) {
+	query := `SELECT id, webhook_id, delivery_id, event_id, event_type, match_id, attempt,
+		status_code, success, error, duration_ms, created_at
+		FROM webhook_deliveries WHERE webhook_id = ?`
+	args := []any{webhookID}
+	if success != nil {
+		query += " AND success = ?"
+		args = append(args, *success)
+	}
+	query += " ORDER BY id DESC LIMIT ?"
+	args = append(args, limit)
+
+	rows, err := s.db.Query(s.q(query), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	deliveries := []WebhookDelivery{}
+	for rows.Next() {
+		var d WebhookDelivery
+		err := rows.Scan(&d.ID, &d.WebhookID, &d.DeliveryID, &d.EventID, &d.EventType, &d.MatchID, &d.Attempt,
+			&d.StatusCode, &d.Success, &d.Error, &d.DurationMs, &d.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, d)
+	}
+	return deliveries, rows.Err()
+}
+
+// ================================================================
+// Claves de API
+// ================================================================
+
+// apiKeyColumns son las columnas que se seleccionan para construir una APIKey
+const apiKeyColumns = "id, name, role, prefix, key_hash, active, created_at"
+
+// scanAPIKey escanea una fila con las columnas de apiKeyColumns en una APIKey
+func scanAPIKey(row interface{ Scan(...any) error }) (APIKey, error) {
+	var k APIKey
+	err := row.Scan(&k.ID, &k.Name, &k.Role, &k.Prefix, &k.Hash, &k.Active, &k.CreatedAt)
+	return k, err
+}
+
+// APIKeys devuelve todas las claves ordenadas por ID
+func (s *sqlStore) APIKeys() ([]APIKey, error) {
+	rows, err := s.db.Query("SELECT " + apiKeyColumns + " FROM api_keys ORDER BY id")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := []APIKey{}
+	for rows.Next() {
+		k, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, k)
+	}
+	return keys, rows.Err()
+}
+
+// APIKeyByHash devuelve la clave activa con ese hash
+func (s *sqlStore) APIKeyByHash(hash string) (APIKey, error) {
+	k, err := scanAPIKey(s.db.QueryRow(s.q("SELECT "+apiKeyColumns+" FROM api_keys WHERE key_hash = ? AND active = ?"), hash, true))
+	return k, notFound(err)
+}
+
+// CreateAPIKey inserta una clave activa
+func (s *sqlStore) CreateAPIKey(k APIKey) (APIKey, error) {
+	var id int
+	err := s.db.QueryRow(s.q("INSERT INTO api_keys (name, role, prefix, key_hash) VALUES (?, ?, ?, ?) RETURNING id"),
+		k.Name, k.Role, k.Prefix, k.Hash).Scan(&id)
+	if err != nil {
+		return APIKey{}, err
+	}
+	k, err = scanAPIKey(s.db.QueryRow(s.q("SELECT "+apiKeyColumns+" FROM api_keys WHERE id = ?"), id))
+	return k, err
+}
+
+// RevokeAPIKey desactiva una clave. La fila se conserva para que el listado muestre las revocadas.
+func (s *sqlStore) RevokeAPIKey(id int) error {
+	return affected(s.db.Exec(s.q("UPDATE api_keys SET active = ? WHERE id = ? AND active = ?"), false, id, true))
+}
+
+// ================================================================
+// Auditoría
+// ================================================================
+
+// RecordAudit agrega una entrada al historial; los estados vacíos se guardan como NULL
+func (s *sqlStore) RecordAudit(e AuditEntry) error {
+	var matchID, before, after any
+	if e.MatchID != 0 {
+		matchID = e.MatchID
+	}
+	if e.Before != nil {
+		before = string(e.Before)
+	}
+	if e.After != nil {
+		after = string(e.After)
+	}
+	_, err := s.db.Exec(s.q(`INSERT INTO audit_log
+		(actor, actor_role, action, method, route, path, match_id, before_json, after_json, request_id, remote_addr)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`),
+		e.Actor, e.ActorRole, e.Action, e.Method, e.Route, e.Path, matchID, before, after, e.RequestID, e.RemoteAddr)
+	return err
+}
+
+// AuditEntries devuelve las entradas del historial de la más reciente a la más antigua
+func (s *sqlStore) AuditEntries(f auditFilter) ([]AuditEntry, error) {
+	query := `SELECT id, actor, actor_role, action, method, route, path, COALESCE(match_id, 0),
+		before_json, after_json, request_id, remote_addr, created_at
+		FROM audit_log WHERE 1 = 1`
+	var args []any
+	if f.MatchID != 0 {
+		query += " AND match_id = ?"
+		args = append(args, f.MatchID)
+	}
+	if f.Actor != "" {
+		query += " AND actor = ?"
+		args = append(args, f.Actor)
+	}
+	if f.Before != 0 {
+		query += " AND id < ?"
+		args = append(args, f.Before)
+	}
+	query += " ORDER BY id DESC LIMIT ?"
+	args = append(args, f.Limit)
+
+	rows, err := s.db.Query(s.q(query), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []AuditEntry{}
+	for rows.Next() {
+		var e AuditEntry
+		var before, after sql.NullString
+		err := rows.Scan(&e.ID, &e.Actor, &e.ActorRole, &e.Action, &e.Method, &e.Route, &e.Path, &e.MatchID,
+			&before, &after, &e.RequestID, &e.RemoteAddr, &e.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		if before.Valid {
+			e.Before = json.RawMessage(before.String)
+		}
+		if after.Valid {
+			e.After = json.RawMessage(after.String)
+		}
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"database/sql"
+	"strings"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// openDB abre la base de datos SQLite con las claves foráneas activadas.
+// SQLite las desactiva por defecto en cada conexión, por eso se piden en la cadena
+// de conexión y no con PRAGMA, que solo afectaría a una conexión del pool.
+// Si la cadena ya tiene parámetros (file:x.db?cache=shared) se agrega a ellos.
+func openDB(path string) (*sql.DB, error) {
+	sep := "?"
+	if strings.Contains(path, "?") {
+		sep = "&"
+	}
+	return sql.Open(sqliteDialect.driver, path+sep+"_foreign_keys=on")
+}
+
+// newSQLiteStore abre el almacenamiento sobre el archivo SQLite indicado.
+// No aplica las migraciones; de eso se encarga openStore o el comando migrate.
+func newSQLiteStore(path string) (*sqlStore, error) {
+	db, err := openDB(path)
+	if err != nil {
+		return nil, err
+	}
+	return &sqlStore{db: db, d: sqliteDialect}, nil
+}
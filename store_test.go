@@ -0,0 +1,387 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+// postgresTestDSN es la variable de entorno con la cadena de conexión de un PostgreSQL de pruebas.
+// Sin ella las pruebas de conformidad solo corren sobre SQLite y memoria.
+const postgresTestDSN = "LALIGA_TEST_POSTGRES_DSN"
+
+// storeBackends son los almacenamientos sobre los que corren las pruebas de conformidad.
+// Cada uno abre un almacenamiento vacío y con el esquema al día.
+var storeBackends = []struct {
+	name string
+	open func(t *testing.T) Store
+}{
+	{storeMemory, func(t *testing.T) Store { return newMemoryStore() }},
+	{storeSQLite, func(t *testing.T) Store {
+		return storeOrFatal(t, storeSQLite, filepath.Join(t.TempDir(), "laliga.db"))
+	}},
+	{storePostgres, openTestPostgres},
+}
+
+// openTestPostgres abre el almacenamiento sobre un esquema nuevo del PostgreSQL de pruebas,
+// para que cada prueba empiece vacía, y lo elimina al terminar
+func openTestPostgres(t *testing.T) Store {
+	dsn := os.Getenv(postgresTestDSN)
+	if dsn == "" {
+		t.Skip(postgresTestDSN + " no está definida")
+	}
+
+	admin, err := sql.Open(postgresDialect.driver, dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { admin.Close() })
+
+	schema := fmt.Sprintf("laliga_test_%d", time.Now().UnixNano())
+	if _, err := admin.Exec("CREATE SCHEMA " + schema); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { admin.Exec("DROP SCHEMA " + schema + " CASCADE") })
+
+	// lib/pq envía los parámetros que no conoce como parámetros de la sesión
+	switch {
+	case strings.HasPrefix(dsn, "postgres://") || strings.HasPrefix(dsn, "postgresql://"):
+		sep := "?"
+		if strings.Contains(dsn, "?") {
+			sep = "&"
+		}
+		dsn += sep + "search_path=" + schema
+	default:
+		dsn += " search_path=" + schema
+	}
+	return storeOrFatal(t, storePostgres, dsn)
+}
+
+// storeOrFatal abre un almacenamiento y lo cierra al terminar la prueba
+func storeOrFatal(t *testing.T, kind, dsn string) Store {
+	s, err := openStore(kind, dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+// forEachStore corre la prueba sobre cada almacenamiento
+func forEachStore(t *testing.T, test func(t *testing.T, s Store)) {
+	for _, b := range storeBackends {
+		t.Run(b.name, func(t *testing.T) {
+			s := b.open(t)
+			test(t, s)
+		})
+	}
+}
+
+// mustCreate devuelve una función que recibe el resultado de una operación Create y devuelve
+// el ID, o falla la prueba si hubo un error: mustCreate(t)(s.CreateTeam(team))
+func mustCreate(t *testing.T) func(int, error) int {
+	return func(id int, err error) int {
+		t.Helper()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return id
+	}
+}
+
+// createTestTeams crea equipos con los nombres indicados y devuelve sus IDs
+func createTestTeams(t *testing.T, s Store, names ...string) []int {
+	t.Helper()
+	ids := make([]int, len(names))
+	for i, name := range names {
+		ids[i] = mustCreate(t)(s.CreateTeam(Team{Name: name, ShortName: strings.ToUpper(name[:3]), City: "Ciudad", Stadium: "Estadio", FoundedYear: 1900}))
+	}
+	return ids
+}
+
+// createTestMatch crea un partido programado en la temporada indicada (0 si no tiene)
+func createTestMatch(t *testing.T, s Store, home, away int, date string, seasonID int) int {
+	t.Helper()
+	return mustCreate(t)(s.CreateMatch(Match{HomeTeamID: home, AwayTeamID: away, MatchDate: date, SeasonID: seasonID}))
+}
+
+// finishTestMatch lleva un partido programado hasta terminado
+func finishTestMatch(t *testing.T, s Store, id int) {
+	t.Helper()
+	for _, step := range [][2]string{{StatusScheduled, StatusLive}, {StatusLive, StatusFinished}} {
+		if err := s.SetMatchStatus(id, step[0], step[1]); err != nil {
+			t.Fatalf("%s → %s: %v", step[0], step[1], err)
+		}
+	}
+}
+
+// addTestGoals registra n goles del equipo en el partido
+func addTestGoals(t *testing.T, s Store, matchID, teamID, n int) {
+	t.Helper()
+	for i := 0; i < n; i++ {
+		mustCreate(t)(s.CreateEvent("goals", matchID, resolvedEvent{TeamID: teamID, Player: "Jugador", Minute: fmt.Sprintf("%02d:00", 10+i)}))
+	}
+}
+
+func TestStoreDeleteMatchCascade(t *testing.T) {
+	forEachStore(t, func(t *testing.T, s Store) {
+		teams := createTestTeams(t, s, "Local", "Visitante")
+		matchID := createTestMatch(t, s, teams[0], teams[1], "2025-01-10", 0)
+		otherID := createTestMatch(t, s, teams[1], teams[0], "2025-01-17", 0)
+
+		// Un evento de cada tipo en el partido a eliminar y un gol en el otro
+		events := map[string]int{}
+		for _, table := range eventTables {
+			events[table] = mustCreate(t)(s.CreateEvent(table, matchID, resolvedEvent{TeamID: teams[0], Player: "Jugador", Minute: "10:00"}))
+		}
+		otherGoal := mustCreate(t)(s.CreateEvent("goals", otherID, resolvedEvent{TeamID: teams[1], Player: "Jugador", Minute: "20:00"}))
+
+		if err := s.DeleteMatch(matchID); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := s.Match(matchID); err != errNotFound {
+			t.Errorf("Match después de eliminarlo: %v, se esperaba errNotFound", err)
+		}
+		for table, id := range events {
+			if _, err := s.Event(table, matchID, id); err != errNotFound {
+				t.Errorf("%s %d sigue existiendo después de eliminar el partido: %v", table, id, err)
+			}
+		}
+		if got, err := s.MatchEvents(matchID); err != nil || len(got) != 0 {
+			t.Errorf("MatchEvents después de eliminar el partido = %v, %v", got, err)
+		}
+
+		// Los eventos de los demás partidos no se tocan
+		if _, err := s.Event("goals", otherID, otherGoal); err != nil {
+			t.Errorf("el gol del otro partido: %v", err)
+		}
+		if err := s.DeleteMatch(matchID); err != errNotFound {
+			t.Errorf("eliminar dos veces: %v, se esperaba errNotFound", err)
+		}
+
+		// Las claves foráneas tienen que estar activas: no se aceptan eventos de un partido eliminado
+		if _, err := s.CreateEvent("goals", matchID, resolvedEvent{TeamID: teams[0], Player: "Jugador", Minute: "30:00"}); err == nil {
+			t.Error("se registró un gol en un partido eliminado")
+		}
+	})
+}
+
+func TestStoreDeleteTeamCascade(t *testing.T) {
+	forEachStore(t, func(t *testing.T, s Store) {
+		teams := createTestTeams(t, s, "Local", "Visitante", "Sin partidos")
+		createTestMatch(t, s, teams[0], teams[1], "2025-01-10", 0)
+		playerID := mustCreate(t)(s.CreatePlayer(Player{TeamID: teams[2], Name: "Jugador", ShirtNumber: 9, Position: "FW"}))
+
+		if err := s.DeleteTeam(teams[0]); err != errTeamHasMatches {
+			t.Errorf("eliminar un equipo con partidos: %v, se esperaba errTeamHasMatches", err)
+		}
+		if err := s.DeleteTeam(teams[2]); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := s.Player(playerID); err != errNotFound {
+			t.Errorf("el jugador sigue existiendo después de eliminar su equipo: %v", err)
+		}
+	})
+}
+
+func TestStoreSetMatchStatus(t *testing.T) {
+	forEachStore(t, func(t *testing.T, s Store) {
+		teams := createTestTeams(t, s, "Local", "Visitante")
+		id := createTestMatch(t, s, teams[0], teams[1], "2025-01-10", 0)
+
+		tests := []struct {
+			from, to string
+			want     error
+			status   string // Estado después del cambio
+		}{
+			{StatusScheduled, StatusLive, nil, StatusLive},
+			{StatusScheduled, StatusCancelled, errStatusChanged, StatusLive}, // Otro ya lo pasó a live
+			{StatusLive, StatusHalfTime, nil, StatusHalfTime},
+			{StatusLive, StatusFinished, errStatusChanged, StatusHalfTime},
+			{StatusHalfTime, StatusLive, nil, StatusLive},
+			{StatusLive, StatusFinished, nil, StatusFinished},
+		}
+		for _, tt := range tests {
+			if err := s.SetMatchStatus(id, tt.from, tt.to); err != tt.want {
+				t.Errorf("SetMatchStatus(%s → %s) = %v, se esperaba %v", tt.from, tt.to, err, tt.want)
+			}
+			status, updatedAt, err := s.MatchStatus(id)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if status != tt.status {
+				t.Errorf("después de %s → %s el estado es %s, se esperaba %s", tt.from, tt.to, status, tt.status)
+			}
+			if updatedAt.IsZero() {
+				t.Errorf("después de %s → %s la fecha del cambio está vacía", tt.from, tt.to)
+			}
+		}
+
+		if err := s.SetMatchStatus(id+100, StatusScheduled, StatusLive); err != errStatusChanged {
+			t.Errorf("SetMatchStatus de un partido inexistente = %v, se esperaba errStatusChanged", err)
+		}
+	})
+}
+
+func TestStoreMatchesPaging(t *testing.T) {
+	forEachStore(t, func(t *testing.T, s Store) {
+		teams := createTestTeams(t, s, "Uno", "Dos", "Tres")
+		var ids []int
+		for i := 0; i < 7; i++ {
+			home, away := teams[i%3], teams[(i+1)%3]
+			ids = append(ids, createTestMatch(t, s, home, away, fmt.Sprintf("2025-01-%02d", 20-i), 0))
+		}
+		finishTestMatch(t, s, ids[0])
+		finishTestMatch(t, s, ids[3])
+
+		tests := []struct {
+			name  string
+			lq    matchListQuery
+			want  []int
+			total int
+		}{
+			{"primera página", matchListQuery{Sort: "id", Page: 1, Limit: 3}, ids[0:3], 7},
+			{"página del medio", matchListQuery{Sort: "id", Page: 2, Limit: 3}, ids[3:6], 7},
+			{"última página incompleta", matchListQuery{Sort: "id", Page: 3, Limit: 3}, ids[6:7], 7},
+			{"después de la última", matchListQuery{Sort: "id", Page: 4, Limit: 3}, nil, 7},
+			{"orden inverso", matchListQuery{Sort: "-id", Page: 1, Limit: 2}, []int{ids[6], ids[5]}, 7},
+			{"por fecha", matchListQuery{Sort: "date", Page: 1, Limit: 2}, []int{ids[6], ids[5]}, 7},
+			{"por equipo", matchListQuery{TeamID: teams[0], Sort: "id", Page: 1, Limit: 2}, []int{ids[0], ids[2]}, 5},
+			{"por estado", matchListQuery{Statuses: []string{StatusFinished}, Sort: "id", Page: 1, Limit: 10}, []int{ids[0], ids[3]}, 2},
+			{"por fechas", matchListQuery{From: "2025-01-15", To: "2025-01-18", Sort: "id", Page: 2, Limit: 2}, ids[4:6], 4},
+		}
+		for _, tt := range tests {
+			matches, total, err := s.Matches(tt.lq)
+			if err != nil {
+				t.Fatalf("%s: %v", tt.name, err)
+			}
+			var got []int
+			for _, m := range matches {
+				got = append(got, m.ID)
+			}
+			if !reflect.DeepEqual(got, tt.want) || total != tt.total {
+				t.Errorf("%s: partidos %v de %d, se esperaban %v de %d", tt.name, got, total, tt.want, tt.total)
+			}
+		}
+	})
+}
+
+func TestStoreFinishedResultsScope(t *testing.T) {
+	forEachStore(t, func(t *testing.T, s Store) {
+		teams := createTestTeams(t, s, "Uno", "Dos")
+		league := mustCreate(t)(s.CreateCompetition(Competition{Name: "Liga", Country: "España", Type: competitionLeague}))
+		cup := mustCreate(t)(s.CreateCompetition(Competition{Name: "Copa", Country: "España", Type: competitionCup}))
+		season1 := mustCreate(t)(s.CreateSeason(Season{CompetitionID: league, Name: "2024/25", StartDate: "2024-07-01", EndDate: "2025-06-30"}))
+		season2 := mustCreate(t)(s.CreateSeason(Season{CompetitionID: league, Name: "2025/26", StartDate: "2025-07-01", EndDate: "2026-06-30"}))
+		cupSeason := mustCreate(t)(s.CreateSeason(Season{CompetitionID: cup, Name: "2024/25", StartDate: "2024-07-01", EndDate: "2025-06-30"}))
+
+		// Un partido terminado por temporada con un marcador distinto, uno sin temporada
+		// y uno programado que no cuenta
+		played := []struct {
+			season     int
+			home, away int
+		}{{season1, 1, 0}, {season2, 2, 1}, {cupSeason, 0, 3}, {0, 4, 4}}
+		for i, p := range played {
+			id := createTestMatch(t, s, teams[0], teams[1], fmt.Sprintf("2025-0%d-01", i+1), p.season)
+			addTestGoals(t, s, id, teams[0], p.home)
+			addTestGoals(t, s, id, teams[1], p.away)
+			finishTestMatch(t, s, id)
+		}
+		pending := createTestMatch(t, s, teams[0], teams[1], "2025-05-01", season1)
+		addTestGoals(t, s, pending, teams[0], 5)
+
+		result := func(home, away int) matchResult {
+			return matchResult{HomeTeam: "Uno", AwayTeam: "Dos", HomeGoals: home, AwayGoals: away}
+		}
+		tests := []struct {
+			name string
+			sc   matchScope
+			want []matchResult
+		}{
+			{"todas las competiciones", matchScope{}, []matchResult{result(0, 3), result(1, 0), result(2, 1), result(4, 4)}},
+			{"competición", matchScope{CompetitionID: league}, []matchResult{result(1, 0), result(2, 1)}},
+			{"temporada", matchScope{SeasonID: season2}, []matchResult{result(2, 1)}},
+			{"copa", matchScope{CompetitionID: cup}, []matchResult{result(0, 3)}},
+			{"la temporada tiene prioridad", matchScope{CompetitionID: cup, SeasonID: season1}, []matchResult{result(1, 0)}},
+		}
+		for _, tt := range tests {
+			got, err := s.FinishedResults(tt.sc)
+			if err != nil {
+				t.Fatalf("%s: %v", tt.name, err)
+			}
+			// El orden de los resultados no está definido
+			sort.Slice(got, func(i, j int) bool { return got[i].HomeGoals < got[j].HomeGoals })
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("%s: %v, se esperaba %v", tt.name, got, tt.want)
+			}
+		}
+	})
+}
+
+func TestStoreCreateSeasonFixtures(t *testing.T) {
+	forEachStore(t, func(t *testing.T, s Store) {
+		names := make([]string, 20)
+		for i := range names {
+			names[i] = fmt.Sprintf("Equipo %02d", i+1)
+		}
+		teams := createTestTeams(t, s, names...)
+		league := mustCreate(t)(s.CreateCompetition(Competition{Name: "Liga", Country: "España", Type: competitionLeague}))
+		season := mustCreate(t)(s.CreateSeason(Season{CompetitionID: league, Name: "2025/26", StartDate: "2025-07-01", EndDate: "2026-06-30"}))
+
+		// Un calendario completo de 380 partidos, para que las transacciones simultáneas se superpongan.
+		// Los partidos no indican la temporada: la asigna CreateSeasonFixtures.
+		var fixtures []Match
+		start := time.Date(2025, 8, 17, 0, 0, 0, 0, time.UTC)
+		for r, round := range roundRobin(len(teams)) {
+			for _, p := range round {
+				fixtures = append(fixtures, Match{HomeTeamID: teams[p[0]], AwayTeamID: teams[p[1]], MatchDate: start.AddDate(0, 0, 7*r).Format(time.DateOnly)})
+			}
+		}
+
+		if _, err := s.CreateSeasonFixtures(season+100, fixtures); err != errNotFound {
+			t.Errorf("temporada inexistente: %v, se esperaba errNotFound", err)
+		}
+
+		// Varias solicitudes a la vez: solo una crea el calendario, las demás ven la temporada con partidos
+		const callers = 16
+		errs := make(chan error, callers)
+		for i := 0; i < callers; i++ {
+			go func() {
+				_, err := s.CreateSeasonFixtures(season, fixtures)
+				errs <- err
+			}()
+		}
+		created := 0
+		for i := 0; i < callers; i++ {
+			switch err := <-errs; err {
+			case nil:
+				created++
+			case errSeasonHasMatches:
+			default:
+				t.Errorf("calendario simultáneo: %v", err)
+			}
+		}
+		if created != 1 {
+			t.Errorf("se crearon %d calendarios, se esperaba 1", created)
+		}
+
+		matches, total, err := s.Matches(matchListQuery{matchScope: matchScope{SeasonID: season}, Sort: "id", Page: 1, Limit: 10})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if total != len(fixtures) {
+			t.Fatalf("la temporada tiene %d partidos, se esperaban %d", total, len(fixtures))
+		}
+		for i, m := range matches {
+			if m.HomeTeamID != fixtures[i].HomeTeamID || m.MatchDate != fixtures[i].MatchDate || m.Status != StatusScheduled {
+				t.Errorf("partido %d: %+v, se esperaba %+v programado", i, m, fixtures[i])
+			}
+		}
+	})
+}
@@ -0,0 +1,259 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// Team representa un club de fútbol
+// @description Modelo que contiene la información de un equipo
+// @property id, name, shortName, city, stadium, foundedYear
+// @example { "id": 1, "name": "Real Madrid", "shortName": "RMA", "city": "Madrid", "stadium": "Santiago Bernabéu", "foundedYear": 1902 }
+type Team struct {
+	ID          int    `json:"id"`
+	Name        string `json:"name"`
+	ShortName   string `json:"shortName"`
+	City        string `json:"city"`
+	Stadium     string `json:"stadium"`
+	FoundedYear int    `json:"foundedYear"`
+}
+
+// errTeamNotFound indica que la referencia a un equipo no corresponde a ningún registro
+var errTeamNotFound = errors.New("equipo no encontrado")
+
+// resolveTeam obtiene el ID y el nombre oficial de un equipo.
+// Si id es distinto de cero se busca por ID; de lo contrario se busca por nombre
+// o nombre corto sin distinguir mayúsculas, para que "real madrid" y "RMA"
+// apunten al mismo club.
+func resolveTeam(id int, name string) (int, string, error) {
+	var t Team
+	var err error
+	if id != 0 {
+		t, err = store.Team(id)
+	} else {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			return 0, "", errTeamNotFound
+		}
+		t, err = store.TeamByName(name)
+	}
+
+	if err == errNotFound {
+		return 0, "", errTeamNotFound
+	} else if err != nil {
+		return 0, "", err
+	}
+	return t.ID, t.Name, nil
+}
+
+// validateTeam verifica los campos obligatorios de un equipo
+// y devuelve el detalle de los campos que no son válidos
+func validateTeam(t *Team) []FieldError {
+	var errs []FieldError
+	t.Name = strings.TrimSpace(t.Name)
+	t.ShortName = strings.TrimSpace(t.ShortName)
+	if t.Name == "" {
+		errs = append(errs, FieldError{"name", fieldRequired, "El nombre del equipo es obligatorio"})
+	}
+	if t.FoundedYear < 0 {
+		errs = append(errs, FieldError{"foundedYear", fieldOutOfRange, "El año de fundación no es válido"})
+	}
+	return errs
+}
+
+// @Summary Obtener todos los equipos
+// @Description Retorna una lista con todos los equipos registrados. Con competition o seasonId
+// @Description retorna solo los equipos que tienen partidos en esa competición o temporada.
+// @Tags teams
+// @Accept json
+// @Produce json
+// @Param competition query string false "ID o nombre de la competición"
+// @Param seasonId query int false "ID de la temporada"
+// @Success 200 {array} Team
+// @Failure 400 {object} Problem
+// @Failure 401 {object} Problem
+// @Failure 429 {object} Problem
+// @Failure 500 {object} Problem
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/teams [get]
+func getTeams(w http.ResponseWriter, r *http.Request) {
+	sc, errs, err := parseMatchScope(r)
+	if err == errSeasonNotFound {
+		writeSeasonNotFound(w, r)
+		return
+	} else if err != nil {
+		dbError(w, r, err)
+		return
+	} else if errs != nil {
+		writeValidation(w, r, errs...)
+		return
+	}
+
+	// El almacenamiento devuelve [] en lugar de null si no hay equipos
+	teams, err := store.Teams(sc)
+	if err != nil {
+		dbError(w, r, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(teams)
+}
+
+// @Summary Obtener equipo por ID
+// @Description Retorna los datos de un equipo específico
+// @Tags teams
+// @Accept json
+// @Produce json
+// @Param id path int true "ID del equipo"
+// @Success 200 {object} Team
+// @Failure 401 {object} Problem
+// @Failure 404 {object} Problem
+// @Failure 429 {object} Problem
+// @Failure 500 {object} Problem
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/teams/{id} [get]
+func getTeam(w http.ResponseWriter, r *http.Request) {
+	t, err := store.Team(pathID(r, "id"))
+	if err == errNotFound {
+		writeError(w, r, http.StatusNotFound, codeTeamNotFound, "Equipo no encontrado")
+		return
+	} else if err != nil {
+		dbError(w, r, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(t)
+}
+
+// @Summary Crear un nuevo equipo
+// @Description Crea un nuevo equipo. El nombre debe ser único
+// @Tags teams
+// @Accept json
+// @Produce json
+// @Param team body Team true "Datos del equipo"
+// @Success 200 {object} Team
+// @Failure 400 {object} Problem
+// @Failure 401 {object} Problem
+// @Failure 403 {object} Problem
+// @Failure 409 {object} Problem
+// @Failure 429 {object} Problem
+// @Failure 500 {object} Problem
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/teams [post]
+func createTeam(w http.ResponseWriter, r *http.Request) {
+	var t Team
+	if err := json.NewDecoder(r.Body).Decode(&t); err != nil {
+		writeInvalidJSON(w, r)
+		return
+	}
+
+	if errs := validateTeam(&t); errs != nil {
+		writeValidation(w, r, errs...)
+		return
+	}
+
+	// Evitar duplicados que solo difieran en mayúsculas
+	if _, _, err := resolveTeam(0, t.Name); err == nil {
+		writeError(w, r, http.StatusConflict, codeTeamNameTaken, "Ya existe un equipo con ese nombre")
+		return
+	}
+
+	id, err := store.CreateTeam(t)
+	if err != nil {
+		dbError(w, r, err)
+		return
+	}
+
+	t.ID = id
+	json.NewEncoder(w).Encode(t)
+}
+
+// @Summary Actualizar equipo
+// @Description Modifica los datos de un equipo existente por ID
+// @Tags teams
+// @Accept json
+// @Produce json
+// @Param id path int true "ID del equipo"
+// @Param team body Team true "Datos actualizados"
+// @Success 200 {object} Team
+// @Failure 400 {object} Problem
+// @Failure 401 {object} Problem
+// @Failure 403 {object} Problem
+// @Failure 404 {object} Problem
+// @Failure 409 {object} Problem
+// @Failure 429 {object} Problem
+// @Failure 500 {object} Problem
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/teams/{id} [put]
+func updateTeam(w http.ResponseWriter, r *http.Request) {
+	id := pathID(r, "id")
+
+	var t Team
+	if err := json.NewDecoder(r.Body).Decode(&t); err != nil {
+		writeInvalidJSON(w, r)
+		return
+	}
+
+	if errs := validateTeam(&t); errs != nil {
+		writeValidation(w, r, errs...)
+		return
+	}
+
+	// El nuevo nombre no puede pertenecer a otro equipo
+	if otherID, _, err := resolveTeam(0, t.Name); err == nil && otherID != id {
+		writeError(w, r, http.StatusConflict, codeTeamNameTaken, "Ya existe un equipo con ese nombre")
+		return
+	}
+
+	t.ID = id
+	err := store.UpdateTeam(t)
+	if err == errNotFound {
+		writeError(w, r, http.StatusNotFound, codeTeamNotFound, "Equipo no encontrado")
+		return
+	} else if err != nil {
+		dbError(w, r, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(t)
+}
+
+// @Summary Eliminar equipo
+// @Description Elimina un equipo que no tenga partidos asociados, junto con su plantilla
+// @Tags teams
+// @Accept json
+// @Produce json
+// @Param id path int true "ID del equipo"
+// @Success 204 {string} string "Sin contenido"
+// @Failure 401 {object} Problem
+// @Failure 403 {object} Problem
+// @Failure 404 {object} Problem
+// @Failure 409 {object} Problem
+// @Failure 429 {object} Problem
+// @Failure 500 {object} Problem
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/teams/{id} [delete]
+func deleteTeam(w http.ResponseWriter, r *http.Request) {
+	// Eliminar la plantilla junto con el equipo; no se permite eliminar
+	// equipos que ya disputaron o tienen partidos programados
+	err := store.DeleteTeam(pathID(r, "id"))
+	if err == errTeamHasMatches {
+		writeError(w, r, http.StatusConflict, codeTeamHasMatches, "El equipo tiene partidos asociados")
+		return
+	} else if err == errNotFound {
+		writeError(w, r, http.StatusNotFound, codeTeamNotFound, "Equipo no encontrado")
+		return
+	} else if err != nil {
+		dbError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// halfLength es la duración de un tiempo en segundos. Los eventos a partir del
+// minuto 45:00 se consideran del segundo tiempo, ya que el minuto no distingue
+// el descuento del primer tiempo del inicio del segundo.
+const halfLength = 45 * 60
+
+// timelineOrder define el orden de los eventos que ocurren en el mismo minuto
+var timelineOrder = map[string]int{LiveGoal: 0, LiveYellowCard: 1, LiveRedCard: 2}
+
+// TimelineEntry representa un evento dentro de la cronología de un partido
+// @description Modelo que contiene un evento de la cronología con el marcador acumulado
+// @property type, eventId, teamId, team, side, playerId, player, minute, half, homeScore, awayScore
+type TimelineEntry struct {
+	Type      string `json:"type" enums:"goal,yellow_card,red_card"`
+	EventID   int    `json:"eventId"`
+	TeamID    int    `json:"teamId"`
+	Team      string `json:"team"`
+	Side      string `json:"side" enums:"home,away"`
+	PlayerID  int    `json:"playerId,omitempty"`
+	Player    string `json:"player"`
+	Minute    string `json:"minute"`
+	Half      int    `json:"half" enums:"1,2"`
+	HomeScore int    `json:"homeScore"`
+	AwayScore int    `json:"awayScore"`
+}
+
+// MatchTimeline representa la cronología completa de un partido
+// @description Modelo que contiene los datos del partido y todos sus eventos en orden cronológico
+// @property matchId, homeTeamId, homeTeam, awayTeamId, awayTeam, status, extraTime, homeScore, awayScore, events
+type MatchTimeline struct {
+	MatchID    int             `json:"matchId"`
+	HomeTeamID int             `json:"homeTeamId"`
+	HomeTeam   string          `json:"homeTeam"`
+	AwayTeamID int             `json:"awayTeamId"`
+	AwayTeam   string          `json:"awayTeam"`
+	Status     string          `json:"status"`
+	ExtraTime  string          `json:"extraTime"`
+	HomeScore  int             `json:"homeScore"`
+	AwayScore  int             `json:"awayScore"`
+	Events     []TimelineEntry `json:"events"`
+}
+
+// minuteSeconds convierte un minuto en formato MM:SS a segundos
+func minuteSeconds(minute string) int {
+	mm, ss, _ := strings.Cut(minute, ":")
+	m, _ := strconv.Atoi(mm)
+	s, _ := strconv.Atoi(ss)
+	return m*60 + s
+}
+
+// buildTimeline une los goles y tarjetas de un partido en una sola lista ordenada por minuto
+// y calcula el marcador después de cada evento
+func buildTimeline(m FullMatchData) MatchTimeline {
+	t := MatchTimeline{
+		MatchID:    m.ID,
+		HomeTeamID: m.HomeTeamID,
+		HomeTeam:   m.HomeTeam,
+		AwayTeamID: m.AwayTeamID,
+		AwayTeam:   m.AwayTeam,
+		Status:     m.Status,
+		ExtraTime:  m.ExtraTime,
+		Events:     []TimelineEntry{},
+	}
+
+	// Agregar los eventos de cada tipo
+	for _, group := range []struct {
+		kind   string
+		events []MatchEvent
+	}{
+		{LiveGoal, m.Goals},
+		{LiveYellowCard, m.YellowCards},
+		{LiveRedCard, m.RedCards},
+	} {
+		for _, e := range group.events {
+			side := "home"
+			if e.TeamID == m.AwayTeamID {
+				side = "away"
+			}
+			half := 1
+			if minuteSeconds(e.Minute) >= halfLength {
+				half = 2
+			}
+			t.Events = append(t.Events, TimelineEntry{
+				Type:     group.kind,
+				EventID:  e.ID,
+				TeamID:   e.TeamID,
+				Team:     e.Team,
+				Side:     side,
+				PlayerID: e.PlayerID,
+				Player:   e.Player,
+				Minute:   e.Minute,
+				Half:     half,
+			})
+		}
+	}
+
+	// Ordenar por minuto; en el mismo minuto van primero los goles y luego por orden de registro
+	sort.SliceStable(t.Events, func(i, j int) bool {
+		a, b := t.Events[i], t.Events[j]
+		if sa, sb := minuteSeconds(a.Minute), minuteSeconds(b.Minute); sa != sb {
+			return sa < sb
+		}
+		if a.Type != b.Type {
+			return timelineOrder[a.Type] < timelineOrder[b.Type]
+		}
+		return a.EventID < b.EventID
+	})
+
+	// Calcular el marcador acumulado después de cada evento
+	for i := range t.Events {
+		if t.Events[i].Type == LiveGoal {
+			if t.Events[i].Side == "home" {
+				t.HomeScore++
+			} else {
+				t.AwayScore++
+			}
+		}
+		t.Events[i].HomeScore = t.HomeScore
+		t.Events[i].AwayScore = t.AwayScore
+	}
+
+	return t
+}
+
+// @Summary Cronología del partido
+// @Description Retorna todos los goles y tarjetas de un partido en una sola lista ordenada por minuto,
+// @Description con el marcador después de cada evento y el tiempo (1 o 2) en que ocurrió.
+// @Description Los eventos desde el minuto 45:00 se consideran del segundo tiempo.
+// @Tags matches
+// @Accept json
+// @Produce json
+// @Param id path int true "ID del partido"
+// @Success 200 {object} MatchTimeline
+// @Failure 401 {object} Problem
+// @Failure 404 {object} Problem
+// @Failure 429 {object} Problem
+// @Failure 500 {object} Problem
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/matches/{id}/timeline [get]
+func getMatchTimeline(w http.ResponseWriter, r *http.Request) {
+	match, err := store.Match(pathID(r, "id"))
+	if err == errNotFound {
+		writeError(w, r, http.StatusNotFound, codeMatchNotFound, "Partido no encontrado")
+		return
+	} else if err != nil {
+		dbError(w, r, err)
+		return
+	}
+
+	m := FullMatchData{
+		ID:         match.ID,
+		HomeTeamID: match.HomeTeamID,
+		HomeTeam:   match.HomeTeam,
+		AwayTeamID: match.AwayTeamID,
+		AwayTeam:   match.AwayTeam,
+		MatchDate:  match.MatchDate,
+		ExtraTime:  match.ExtraTime,
+		Status:     match.Status,
+	}
+	if err := fetchMatchEvents(&m); err != nil {
+		dbError(w, r, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(buildTimeline(m))
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestMinuteSeconds(t *testing.T) {
+	for minute, want := range map[string]int{"0:00": 0, "9:30": 570, "45:00": 2700, "93:10": 5590} {
+		if got := minuteSeconds(minute); got != want {
+			t.Errorf("minuteSeconds(%s) = %d, se esperaba %d", minute, got, want)
+		}
+	}
+}
+
+func TestBuildTimeline(t *testing.T) {
+	const home, away = 1, 2
+	m := FullMatchData{
+		ID: 7, HomeTeamID: home, HomeTeam: "Athletic", AwayTeamID: away, AwayTeam: "Betis", Status: StatusFinished,
+		// Los IDs siguen el orden de registro, que no es el cronológico
+		Goals: []MatchEvent{
+			{ID: 1, TeamID: away, Player: "Isco", Minute: "10:00"},
+			{ID: 2, TeamID: home, Player: "Williams", Minute: "45:00"},
+			{ID: 3, TeamID: home, Player: "Sancet", Minute: "9:30"},
+			{ID: 4, TeamID: home, Player: "Guruzeta", Minute: "93:10"},
+		},
+		YellowCards: []MatchEvent{
+			{ID: 1, TeamID: away, Player: "Bartra", Minute: "45:00"},
+			{ID: 2, TeamID: home, Player: "Vivian", Minute: "44:59"},
+		},
+		RedCards: []MatchEvent{
+			{ID: 1, TeamID: away, Player: "Bartra", Minute: "45:00"},
+		},
+	}
+
+	got := buildTimeline(m)
+
+	// Mismo minuto: gol, amarilla y roja en ese orden. 9:30 va antes que 10:00 aunque como texto sea mayor.
+	want := []TimelineEntry{
+		{Type: LiveGoal, EventID: 3, TeamID: home, Side: "home", Player: "Sancet", Minute: "9:30", Half: 1, HomeScore: 1},
+		{Type: LiveGoal, EventID: 1, TeamID: away, Side: "away", Player: "Isco", Minute: "10:00", Half: 1, HomeScore: 1, AwayScore: 1},
+		{Type: LiveYellowCard, EventID: 2, TeamID: home, Side: "home", Player: "Vivian", Minute: "44:59", Half: 1, HomeScore: 1, AwayScore: 1},
+		{Type: LiveGoal, EventID: 2, TeamID: home, Side: "home", Player: "Williams", Minute: "45:00", Half: 2, HomeScore: 2, AwayScore: 1},
+		{Type: LiveYellowCard, EventID: 1, TeamID: away, Side: "away", Player: "Bartra", Minute: "45:00", Half: 2, HomeScore: 2, AwayScore: 1},
+		{Type: LiveRedCard, EventID: 1, TeamID: away, Side: "away", Player: "Bartra", Minute: "45:00", Half: 2, HomeScore: 2, AwayScore: 1},
+		{Type: LiveGoal, EventID: 4, TeamID: home, Side: "home", Player: "Guruzeta", Minute: "93:10", Half: 2, HomeScore: 3, AwayScore: 1},
+	}
+	if !reflect.DeepEqual(got.Events, want) {
+		t.Errorf("eventos =\n%+v\nse esperaba\n%+v", got.Events, want)
+	}
+	if got.HomeScore != 3 || got.AwayScore != 1 || got.MatchID != 7 || got.Status != StatusFinished {
+		t.Errorf("cronología %d %d-%d %s, se esperaba 7 3-1 finished", got.MatchID, got.HomeScore, got.AwayScore, got.Status)
+	}
+
+	// Un partido sin eventos devuelve una lista vacía y no null
+	if empty := buildTimeline(FullMatchData{ID: 8}); empty.Events == nil || len(empty.Events) != 0 {
+		t.Errorf("sin eventos: %+v", empty.Events)
+	}
+}
+
+func TestGetMatchTimeline(t *testing.T) {
+	s := useMemoryStore(t)
+	teams := createTestTeams(t, s, "Athletic", "Betis")
+	matchID := createTestMatch(t, s, teams[0], teams[1], "2025-01-10", 0)
+	addTestGoals(t, s, matchID, teams[1], 1)
+	addTestGoals(t, s, matchID, teams[0], 2)
+
+	var got MatchTimeline
+	req := httptest.NewRequest(http.MethodGet, "/api/matches/"+strconv.Itoa(matchID)+"/timeline", nil)
+	decodeResponse(t, serveRoute("/api/matches/{id}/timeline", getMatchTimeline, req), http.StatusOK, &got)
+
+	// addTestGoals registra los goles desde el minuto 10:00: los dos equipos marcan en el 10:00
+	// y va primero el gol registrado antes, el del visitante
+	scores := [][2]int{}
+	for _, e := range got.Events {
+		scores = append(scores, [2]int{e.HomeScore, e.AwayScore})
+	}
+	if want := [][2]int{{0, 1}, {1, 1}, {2, 1}}; !reflect.DeepEqual(scores, want) || got.HomeTeam != "Athletic" || got.AwayTeam != "Betis" {
+		t.Errorf("cronología %s-%s con marcadores %v, se esperaba Athletic-Betis con %v", got.HomeTeam, got.AwayTeam, scores, want)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/matches/99/timeline", nil)
+	decodeProblem(t, serveRoute("/api/matches/{id}/timeline", getMatchTimeline, req), http.StatusNotFound, codeMatchNotFound)
+}
@@ -0,0 +1,543 @@
+package main
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Parámetros de entrega de los webhooks
+const (
+	webhookWorkers     = 4                // Entregas simultáneas
+	webhookQueueSize   = 1024             // Entregas pendientes en memoria
+	webhookMaxAttempts = 5                // Intentos por entrega antes de darla por fallida
+	webhookBaseBackoff = 2 * time.Second  // Espera antes del primer reintento; se duplica en cada intento
+	webhookTimeout     = 10 * time.Second // Tiempo máximo de respuesta del receptor
+)
+
+// defaultWebhookEvents son los eventos que recibe un webhook si no se indica ninguno
+var defaultWebhookEvents = []string{LiveGoal, LiveYellowCard, LiveRedCard, LiveExtraTime}
+
+// validWebhookEvents son los tipos de evento a los que se puede suscribir un webhook
+var validWebhookEvents = map[string]bool{
+	LiveGoal:          true,
+	LiveYellowCard:    true,
+	LiveRedCard:       true,
+	LiveExtraTime:     true,
+	LiveStatusChanged: true,
+	LiveMatchUpdated:  true,
+	LiveMatchDeleted:  true,
+	LiveEventUpdated:  true,
+	LiveEventDeleted:  true,
+}
+
+// Webhook representa un receptor externo que se notifica cuando ocurren eventos
+// @description Modelo que contiene la configuración de un webhook
+// @property id, url, events, matchId, teamId, secret, active, createdAt
+// @example { "id": 1, "url": "https://partner.example/hooks/laliga", "events": ["goal"], "teamId": 1, "active": true }
+type Webhook struct {
+	ID        int      `json:"id"`
+	URL       string   `json:"url"`
+	Events    []string `json:"events"`
+	MatchID   int      `json:"matchId,omitempty"`
+	TeamID    int      `json:"teamId,omitempty"`
+	Secret    string   `json:"secret,omitempty"`
+	Active    bool     `json:"active"`
+	CreatedAt string   `json:"createdAt"`
+}
+
+// WebhookDelivery representa un intento de entrega de un evento a un webhook
+// @description Modelo que contiene el resultado de un intento de entrega
+// @property id, webhookId, deliveryId, eventId, eventType, matchId, attempt, statusCode, success, error, durationMs, createdAt
+type WebhookDelivery struct {
+	ID         int    `json:"id"`
+	WebhookID  int    `json:"webhookId"`
+	DeliveryID string `json:"deliveryId"`
+	EventID    uint64 `json:"eventId"`
+	EventType  string `json:"eventType"`
+	MatchID    int    `json:"matchId"`
+	Attempt    int    `json:"attempt"`
+	StatusCode int    `json:"statusCode"`
+	Success    bool   `json:"success"`
+	Error      string `json:"error"`
+	DurationMs int64  `json:"durationMs"`
+	CreatedAt  string `json:"createdAt"`
+}
+
+// webhookJob es una entrega pendiente de un evento a un webhook
+type webhookJob struct {
+	hook    Webhook
+	event   LiveEvent
+	body    []byte
+	attempt int
+}
+
+// webhookDispatcher escucha los eventos del broker y los entrega a los webhooks registrados
+type webhookDispatcher struct {
+	jobs   chan webhookJob
+	client *http.Client
+	stop   chan struct{}
+}
+
+// dispatcher es la instancia global que entrega los webhooks
+var dispatcher = &webhookDispatcher{
+	jobs:   make(chan webhookJob, webhookQueueSize),
+	client: &http.Client{Timeout: webhookTimeout},
+	stop:   make(chan struct{}),
+}
+
+// sign calcula la firma HMAC-SHA256 del cuerpo con el secreto del webhook
+func sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// newWebhookSecret genera un secreto aleatorio para firmar las entregas
+func newWebhookSecret() string {
+	b := make([]byte, 24)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// start lanza los workers de entrega y la suscripción al broker
+func (d *webhookDispatcher) start() {
+	for i := 0; i < webhookWorkers; i++ {
+		go d.worker()
+	}
+	go d.listen()
+}
+
+// Close detiene la suscripción al broker y los workers.
+// Las entregas que quedaron en cola se descartan.
+func (d *webhookDispatcher) Close() {
+	close(d.stop)
+}
+
+// listen recibe los eventos del broker. Si el broker lo desconecta por atraso,
+// se vuelve a suscribir desde el último evento procesado para no perder ninguno.
+func (d *webhookDispatcher) listen() {
+	var lastID uint64
+	for {
+		sub, backlog, _ := broker.Subscribe(0, lastID)
+		for _, ev := range backlog {
+			d.dispatch(ev)
+			lastID = ev.ID
+		}
+
+	receive:
+		for {
+			select {
+			case <-d.stop:
+				broker.Unsubscribe(sub)
+				return
+			case ev, ok := <-sub.ch:
+				if !ok {
+					break receive
+				}
+				d.dispatch(ev)
+				lastID = ev.ID
+			}
+		}
+
+		// Esperar un momento antes de volver a suscribirse
+		select {
+		case <-d.stop:
+			return
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+// dispatch encola una entrega por cada webhook activo interesado en el evento
+func (d *webhookDispatcher) dispatch(ev LiveEvent) {
+	hooks, err := matchingWebhooks(ev)
+	if err != nil {
+		slog.Error("webhooks: error al buscar webhooks", "event_id", ev.ID, "error", err)
+		return
+	}
+	if len(hooks) == 0 {
+		return
+	}
+
+	body, err := json.Marshal(ev)
+	if err != nil {
+		return
+	}
+
+	for _, h := range hooks {
+		select {
+		case d.jobs <- webhookJob{hook: h, event: ev, body: body, attempt: 1}:
+		case <-d.stop:
+			return
+		}
+	}
+}
+
+// matchingWebhooks devuelve los webhooks activos suscritos al tipo de evento
+// cuyo filtro de partido o equipo coincide con el partido del evento
+func matchingWebhooks(ev LiveEvent) ([]Webhook, error) {
+	active, err := store.ActiveWebhooks(ev.MatchID)
+	if err != nil {
+		return nil, err
+	}
+
+	var hooks []Webhook
+	for _, h := range active {
+		for _, t := range h.Events {
+			if t == ev.Type {
+				hooks = append(hooks, h)
+				break
+			}
+		}
+	}
+
+	// Aplicar el filtro de equipo con los equipos del partido
+	var home, away int
+	teamsLoaded := false
+	filtered := hooks[:0]
+	for _, h := range hooks {
+		if h.TeamID == 0 {
+			filtered = append(filtered, h)
+			continue
+		}
+		if !teamsLoaded {
+			home, away = eventTeams(ev)
+			teamsLoaded = true
+		}
+		if h.TeamID == home || h.TeamID == away {
+			filtered = append(filtered, h)
+		}
+	}
+	return filtered, nil
+}
+
+// eventTeams devuelve los equipos del partido del evento. match_deleted los trae en data
+// porque el partido ya no existe; si no se encuentran devuelve 0, 0.
+func eventTeams(ev LiveEvent) (home, away int) {
+	if data, ok := ev.Data.(map[string]int); ok && ev.Type == LiveMatchDeleted {
+		return data["homeTeamId"], data["awayTeamId"]
+	}
+	if m, err := store.Match(ev.MatchID); err == nil {
+		return m.HomeTeamID, m.AwayTeamID
+	}
+	return 0, 0
+}
+
+// worker entrega los trabajos de la cola y programa los reintentos
+func (d *webhookDispatcher) worker() {
+	for {
+		select {
+		case <-d.stop:
+			return
+		case job := <-d.jobs:
+			// Si el webhook se eliminó mientras la entrega esperaba en la cola o su reintento,
+			// ya no hay a quién entregarla ni dónde registrarla
+			if _, err := store.Webhook(job.hook.ID); err == errNotFound {
+				slog.Debug("webhooks: entrega descartada de un webhook eliminado", "webhook_id", job.hook.ID, "event_id", job.event.ID, "attempt", job.attempt)
+				continue
+			}
+			if d.deliver(job) || job.attempt >= webhookMaxAttempts {
+				continue
+			}
+
+			job.attempt++
+			time.AfterFunc(retryBackoff(job.attempt), func() {
+				select {
+				case d.jobs <- job:
+				case <-d.stop:
+				}
+			})
+		}
+	}
+}
+
+// retryBackoff devuelve la espera antes del intento attempt (desde el segundo).
+// Crece exponencialmente: 2s, 4s, 8s, 16s...
+func retryBackoff(attempt int) time.Duration {
+	return webhookBaseBackoff << (attempt - 2)
+}
+
+// deliver envía el evento al webhook, registra el intento y devuelve si fue exitoso
+func (d *webhookDispatcher) deliver(job webhookJob) bool {
+	deliveryID := fmt.Sprintf("%d-%d", job.event.ID, job.hook.ID)
+	start := time.Now()
+
+	var statusCode int
+	var errMsg string
+
+	req, err := http.NewRequest(http.MethodPost, job.hook.URL, bytes.NewReader(job.body))
+	if err == nil {
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("User-Agent", "LaLigaTracker-Webhooks/1.0")
+		req.Header.Set("X-LaLiga-Event", job.event.Type)
+		req.Header.Set("X-LaLiga-Delivery", deliveryID)
+		req.Header.Set("X-LaLiga-Attempt", strconv.Itoa(job.attempt))
+		req.Header.Set("X-LaLiga-Signature", sign(job.hook.Secret, job.body))
+
+		var resp *http.Response
+		resp, err = d.client.Do(req)
+		if err == nil {
+			statusCode = resp.StatusCode
+			resp.Body.Close()
+			if statusCode < 200 || statusCode > 299 {
+				errMsg = "respuesta " + resp.Status
+			}
+		}
+	}
+	if err != nil {
+		errMsg = err.Error()
+	}
+
+	success := errMsg == ""
+	if success {
+		webhookDeliveries.WithLabelValues("success").Inc()
+	} else {
+		webhookDeliveries.WithLabelValues("failure").Inc()
+		slog.Debug("webhooks: entrega fallida", "webhook_id", job.hook.ID, "delivery_id", deliveryID, "attempt", job.attempt, "error", errMsg)
+	}
+	dbErr := store.RecordDelivery(WebhookDelivery{
+		WebhookID:  job.hook.ID,
+		DeliveryID: deliveryID,
+		EventID:    job.event.ID,
+		EventType:  job.event.Type,
+		MatchID:    job.event.MatchID,
+		Attempt:    job.attempt,
+		StatusCode: statusCode,
+		Success:    success,
+		Error:      errMsg,
+		DurationMs: time.Since(start).Milliseconds(),
+	})
+	if dbErr != nil {
+		slog.Error("webhooks: error al registrar la entrega", "delivery_id", deliveryID, "error", dbErr)
+	}
+	return success
+}
+
+// @Summary Obtener webhooks
+// @Description Retorna todos los webhooks registrados (sin el secreto)
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Success 200 {array} Webhook
+// @Failure 401 {object} Problem
+// @Failure 403 {object} Problem
+// @Failure 429 {object} Problem
+// @Failure 500 {object} Problem
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/webhooks [get]
+func getWebhooks(w http.ResponseWriter, r *http.Request) {
+	hooks, err := store.Webhooks()
+	if err != nil {
+		dbError(w, r, err)
+		return
+	}
+
+	// El secreto solo se devuelve al registrar el webhook
+	for i := range hooks {
+		hooks[i].Secret = ""
+	}
+
+	json.NewEncoder(w).Encode(hooks)
+}
+
+// @Summary Obtener webhook por ID
+// @Description Retorna la configuración de un webhook (sin el secreto)
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param id path int true "ID del webhook"
+// @Success 200 {object} Webhook
+// @Failure 401 {object} Problem
+// @Failure 403 {object} Problem
+// @Failure 404 {object} Problem
+// @Failure 429 {object} Problem
+// @Failure 500 {object} Problem
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/webhooks/{id} [get]
+func getWebhook(w http.ResponseWriter, r *http.Request) {
+	h, err := store.Webhook(pathID(r, "id"))
+	if err == errNotFound {
+		writeError(w, r, http.StatusNotFound, codeWebhookNotFound, "Webhook no encontrado")
+		return
+	} else if err != nil {
+		dbError(w, r, err)
+		return
+	}
+
+	h.Secret = ""
+	json.NewEncoder(w).Encode(h)
+}
+
+// @Summary Registrar webhook
+// @Description Registra una URL que recibirá por POST los eventos indicados, firmados con HMAC-SHA256
+// @Description en el encabezado X-LaLiga-Signature. Si no se envía un secreto, se genera uno y se devuelve solo en esta respuesta.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param webhook body Webhook true "Datos del webhook"
+// @Success 200 {object} Webhook
+// @Failure 400 {object} Problem
+// @Failure 401 {object} Problem
+// @Failure 403 {object} Problem
+// @Failure 429 {object} Problem
+// @Failure 500 {object} Problem
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/webhooks [post]
+func createWebhook(w http.ResponseWriter, r *http.Request) {
+	var h Webhook
+	if err := json.NewDecoder(r.Body).Decode(&h); err != nil {
+		writeInvalidJSON(w, r)
+		return
+	}
+
+	// La URL debe ser absoluta y usar http o https
+	var errs []FieldError
+	u, err := url.Parse(h.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		errs = append(errs, FieldError{"url", fieldInvalidFormat, "La URL debe ser http(s) y absoluta"})
+	}
+
+	if len(h.Events) == 0 {
+		h.Events = defaultWebhookEvents
+	}
+	for _, t := range h.Events {
+		if !validWebhookEvents[t] {
+			errs = append(errs, FieldError{"events", fieldInvalid, "Tipo de evento inválido: " + t})
+		}
+	}
+
+	// Validar los filtros opcionales
+	if h.MatchID != 0 {
+		if _, err := store.Match(h.MatchID); err == errNotFound {
+			errs = append(errs, FieldError{"matchId", fieldNotFound, "Partido no encontrado"})
+		} else if err != nil {
+			dbError(w, r, err)
+			return
+		}
+	}
+	if h.TeamID != 0 {
+		if ok, err := teamExists(h.TeamID); err != nil {
+			dbError(w, r, err)
+			return
+		} else if !ok {
+			errs = append(errs, FieldError{"teamId", fieldNotFound, "Equipo no encontrado"})
+		}
+	}
+	if errs != nil {
+		writeValidation(w, r, errs...)
+		return
+	}
+
+	if h.Secret == "" {
+		h.Secret = newWebhookSecret()
+	}
+
+	created, err := store.CreateWebhook(h)
+	if err != nil {
+		dbError(w, r, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(created)
+}
+
+// @Summary Eliminar webhook
+// @Description Elimina un webhook junto con su historial de entregas
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param id path int true "ID del webhook"
+// @Success 204 {string} string "Sin contenido"
+// @Failure 401 {object} Problem
+// @Failure 403 {object} Problem
+// @Failure 404 {object} Problem
+// @Failure 429 {object} Problem
+// @Failure 500 {object} Problem
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/webhooks/{id} [delete]
+func deleteWebhook(w http.ResponseWriter, r *http.Request) {
+	// Eliminar el webhook junto con su historial de entregas
+	err := store.DeleteWebhook(pathID(r, "id"))
+	if err == errNotFound {
+		writeError(w, r, http.StatusNotFound, codeWebhookNotFound, "Webhook no encontrado")
+		return
+	} else if err != nil {
+		dbError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary Historial de entregas de un webhook
+// @Description Retorna los intentos de entrega de un webhook, del más reciente al más antiguo
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param id path int true "ID del webhook"
+// @Param success query bool false "Filtrar por entregas exitosas (true) o fallidas (false)"
+// @Param limit query int false "Cantidad máxima de intentos (1-500, por defecto 100)"
+// @Success 200 {array} WebhookDelivery
+// @Failure 400 {object} Problem
+// @Failure 401 {object} Problem
+// @Failure 403 {object} Problem
+// @Failure 404 {object} Problem
+// @Failure 429 {object} Problem
+// @Failure 500 {object} Problem
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api/webhooks/{id}/deliveries [get]
+func getWebhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	id := pathID(r, "id")
+
+	if _, err := store.Webhook(id); err == errNotFound {
+		writeError(w, r, http.StatusNotFound, codeWebhookNotFound, "Webhook no encontrado")
+		return
+	} else if err != nil {
+		dbError(w, r, err)
+		return
+	}
+
+	// Filtrar opcionalmente por entregas exitosas o fallidas
+	var success *bool
+	if s := r.URL.Query().Get("success"); s != "" {
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			writeValidation(w, r, FieldError{"success", fieldInvalid, "El parámetro success debe ser true o false"})
+			return
+		}
+		success = &b
+	}
+
+	limit := 100
+	if l := r.URL.Query().Get("limit"); l != "" {
+		n, err := strconv.Atoi(l)
+		if err != nil || n < 1 || n > 500 {
+			writeValidation(w, r, FieldError{"limit", fieldOutOfRange, "El límite debe estar entre 1 y 500"})
+			return
+		}
+		limit = n
+	}
+
+	deliveries, err := store.Deliveries(id, success, limit)
+	if err != nil {
+		dbError(w, r, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(deliveries)
+}
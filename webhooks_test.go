@@ -0,0 +1,201 @@
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSign(t *testing.T) {
+	// Vector conocido de HMAC-SHA256
+	got := sign("key", []byte("The quick brown fox jumps over the lazy dog"))
+	if want := "sha256=f7bc83f430538424b13298e6aa6fb143ef4d59a14946175997479dbc2d1a3cd8"; got != want {
+		t.Errorf("sign = %s, se esperaba %s", got, want)
+	}
+	if sign("otra", []byte("The quick brown fox jumps over the lazy dog")) == got {
+		t.Error("la firma no depende del secreto")
+	}
+}
+
+func TestRetryBackoff(t *testing.T) {
+	want := map[int]time.Duration{2: 2 * time.Second, 3: 4 * time.Second, 4: 8 * time.Second, 5: 16 * time.Second}
+	for attempt := 2; attempt <= webhookMaxAttempts; attempt++ {
+		if got := retryBackoff(attempt); got != want[attempt] {
+			t.Errorf("retryBackoff(%d) = %v, se esperaba %v", attempt, got, want[attempt])
+		}
+	}
+}
+
+// createTestWebhook registra un webhook en el almacenamiento y devuelve su ID
+func createTestWebhook(t *testing.T, s Store, h Webhook) int {
+	t.Helper()
+	h.Secret = "secreto"
+	created, err := s.CreateWebhook(h)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return created.ID
+}
+
+func TestMatchingWebhooks(t *testing.T) {
+	s := useMemoryStore(t)
+	teams := createTestTeams(t, s, "Athletic", "Betis", "Celta")
+	matchID := createTestMatch(t, s, teams[0], teams[1], "2025-01-10", 0)
+	otherID := createTestMatch(t, s, teams[1], teams[2], "2025-01-17", 0)
+
+	all := createTestWebhook(t, s, Webhook{URL: "http://receptor/todos", Events: []string{LiveGoal, LiveMatchDeleted}})
+	home := createTestWebhook(t, s, Webhook{URL: "http://receptor/local", Events: []string{LiveGoal, LiveMatchDeleted}, TeamID: teams[0]})
+	createTestWebhook(t, s, Webhook{URL: "http://receptor/ajeno", Events: []string{LiveGoal, LiveMatchDeleted}, TeamID: teams[2]})
+	createTestWebhook(t, s, Webhook{URL: "http://receptor/otro", Events: []string{LiveGoal}, MatchID: otherID})
+	createTestWebhook(t, s, Webhook{URL: "http://receptor/tarjetas", Events: []string{LiveYellowCard}})
+
+	ids := func(ev LiveEvent) []int {
+		t.Helper()
+		hooks, err := matchingWebhooks(ev)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids := []int{}
+		for _, h := range hooks {
+			ids = append(ids, h.ID)
+		}
+		return ids
+	}
+
+	if got := ids(LiveEvent{Type: LiveGoal, MatchID: matchID}); !reflect.DeepEqual(got, []int{all, home}) {
+		t.Errorf("gol: webhooks %v, se esperaban %v", got, []int{all, home})
+	}
+
+	// El partido eliminado ya no está en el almacenamiento: los equipos vienen en el evento
+	if err := s.DeleteMatch(matchID); err != nil {
+		t.Fatal(err)
+	}
+	deleted := LiveEvent{Type: LiveMatchDeleted, MatchID: matchID, Data: map[string]int{"id": matchID, "homeTeamId": teams[0], "awayTeamId": teams[1]}}
+	if got := ids(deleted); !reflect.DeepEqual(got, []int{all, home}) {
+		t.Errorf("partido eliminado: webhooks %v, se esperaban %v", got, []int{all, home})
+	}
+}
+
+// webhookReceiver es un receptor de prueba que guarda las entregas y responde con status
+type webhookReceiver struct {
+	mu       sync.Mutex
+	status   int
+	requests []*http.Request
+	bodies   [][]byte
+	received chan struct{}
+}
+
+func newWebhookReceiver(t *testing.T, status int) (*webhookReceiver, *httptest.Server) {
+	rcv := &webhookReceiver{status: status, received: make(chan struct{}, 16)}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		rcv.mu.Lock()
+		rcv.requests = append(rcv.requests, r)
+		rcv.bodies = append(rcv.bodies, body)
+		status := rcv.status
+		rcv.mu.Unlock()
+		w.WriteHeader(status)
+		rcv.received <- struct{}{}
+	}))
+	t.Cleanup(srv.Close)
+	return rcv, srv
+}
+
+// newTestDispatcher crea un dispatcher que no está escuchando al broker
+func newTestDispatcher() *webhookDispatcher {
+	return &webhookDispatcher{
+		jobs:   make(chan webhookJob, webhookQueueSize),
+		client: &http.Client{Timeout: time.Second},
+		stop:   make(chan struct{}),
+	}
+}
+
+func TestWebhookDeliver(t *testing.T) {
+	s := useMemoryStore(t)
+	rcv, srv := newWebhookReceiver(t, http.StatusOK)
+	hookID := createTestWebhook(t, s, Webhook{URL: srv.URL, Events: []string{LiveGoal}})
+	hook, _ := s.Webhook(hookID)
+	d := newTestDispatcher()
+
+	body := []byte(`{"id":7,"type":"goal","matchId":3}`)
+	job := webhookJob{hook: hook, event: LiveEvent{ID: 7, Type: LiveGoal, MatchID: 3}, body: body, attempt: 2}
+	if !d.deliver(job) {
+		t.Fatal("la entrega falló")
+	}
+
+	// La firma se verifica como lo haría el receptor
+	rcv.mu.Lock()
+	mac := hmac.New(sha256.New, []byte("secreto"))
+	mac.Write(rcv.bodies[0])
+	req := rcv.requests[0]
+	rcv.status = http.StatusInternalServerError
+	rcv.mu.Unlock()
+	if got, want := req.Header.Get("X-LaLiga-Signature"), "sha256="+hex.EncodeToString(mac.Sum(nil)); got != want {
+		t.Errorf("firma %s, se esperaba %s", got, want)
+	}
+	headers := map[string]string{"X-LaLiga-Event": LiveGoal, "X-LaLiga-Delivery": "7-" + strconv.Itoa(hookID), "X-LaLiga-Attempt": "2", "Content-Type": "application/json"}
+	for name, want := range headers {
+		if got := req.Header.Get(name); got != want {
+			t.Errorf("%s = %q, se esperaba %q", name, got, want)
+		}
+	}
+
+	// Una respuesta de error cuenta como intento fallido
+	if d.deliver(job) {
+		t.Error("una respuesta 500 se registró como entrega exitosa")
+	}
+
+	deliveries, err := s.Deliveries(hookID, nil, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deliveries) != 2 || deliveries[0].Success || deliveries[0].StatusCode != 500 || !deliveries[1].Success || deliveries[1].Attempt != 2 {
+		t.Errorf("entregas registradas = %+v", deliveries)
+	}
+}
+
+func TestWebhookWorkerDropsDeletedWebhook(t *testing.T) {
+	s := useMemoryStore(t)
+	rcv, srv := newWebhookReceiver(t, http.StatusOK)
+	deletedID := createTestWebhook(t, s, Webhook{URL: srv.URL + "/eliminado", Events: []string{LiveGoal}})
+	keptID := createTestWebhook(t, s, Webhook{URL: srv.URL + "/activo", Events: []string{LiveGoal}})
+	deleted, _ := s.Webhook(deletedID)
+	kept, _ := s.Webhook(keptID)
+	if err := s.DeleteWebhook(deletedID); err != nil {
+		t.Fatal(err)
+	}
+
+	// Un solo worker procesa la cola en orden: cuando llega la segunda entrega la primera ya se descartó
+	d := newTestDispatcher()
+	d.jobs <- webhookJob{hook: deleted, event: LiveEvent{ID: 1, Type: LiveGoal}, body: []byte(`{}`), attempt: 3}
+	d.jobs <- webhookJob{hook: kept, event: LiveEvent{ID: 1, Type: LiveGoal}, body: []byte(`{}`), attempt: 1}
+	done := make(chan struct{})
+	go func() {
+		d.worker()
+		close(done)
+	}()
+	// Detener el worker antes de restaurar el almacenamiento que usa para registrar la entrega
+	t.Cleanup(func() {
+		d.Close()
+		<-done
+	})
+
+	select {
+	case <-rcv.received:
+	case <-time.After(5 * time.Second):
+		t.Fatal("el webhook activo no recibió la entrega")
+	}
+	rcv.mu.Lock()
+	defer rcv.mu.Unlock()
+	if len(rcv.requests) != 1 || rcv.requests[0].URL.Path != "/activo" {
+		t.Errorf("entregas recibidas: %d, se esperaba solo la del webhook activo", len(rcv.requests))
+	}
+}
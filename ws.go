@@ -0,0 +1,399 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// Parámetros de las conexiones WebSocket
+const (
+	wsWriteWait        = 10 * time.Second // Tiempo máximo para escribir un mensaje
+	wsPongWait         = 60 * time.Second // Tiempo máximo sin recibir pong del cliente
+	wsPingPeriod       = 50 * time.Second // Intervalo de ping; debe ser menor que wsPongWait
+	wsMaxMessageSize   = 4096             // Tamaño máximo de los mensajes del cliente
+	wsSendBuffer       = 256              // Mensajes pendientes por cliente antes de desconectarlo
+	wsSnapshotInterval = 30 * time.Second // Intervalo de los marcadores periódicos
+)
+
+// WSClientMessage representa un mensaje enviado por el cliente WebSocket
+// @description Modelo para suscribirse o desuscribirse de partidos por WebSocket
+// @property action, matchIds
+type WSClientMessage struct {
+	Action   string `json:"action" enums:"subscribe,unsubscribe"`
+	MatchIDs []int  `json:"matchIds"`
+}
+
+// WSServerMessage representa un mensaje enviado por el servidor WebSocket.
+// Los eventos en vivo usan los mismos tipos que el stream SSE; además existen
+// los tipos snapshot, subscribed, unsubscribed y error.
+// @description Modelo de los mensajes que envía el servidor por WebSocket
+// @property type, id, matchId, matchIds, time, data, message
+type WSServerMessage struct {
+	Type     string    `json:"type"`
+	ID       uint64    `json:"id,omitempty"`
+	MatchID  int       `json:"matchId,omitempty"`
+	MatchIDs []int     `json:"matchIds,omitempty"`
+	Time     time.Time `json:"time"`
+	Data     any       `json:"data,omitempty"`
+	Message  string    `json:"message,omitempty"`
+}
+
+// ScoreSnapshot representa el marcador actual de un partido
+// @description Modelo con el marcador y las tarjetas de un partido en un momento dado
+// @property matchId, homeTeam, awayTeam, homeGoals, awayGoals, status, extraTime
+type ScoreSnapshot struct {
+	MatchID              int    `json:"matchId"`
+	HomeTeam             string `json:"homeTeam"`
+	AwayTeam             string `json:"awayTeam"`
+	HomeGoals            int    `json:"homeGoals"`
+	AwayGoals            int    `json:"awayGoals"`
+	HomeYellowCardsCount int    `json:"homeYellowCardsCount"`
+	AwayYellowCardsCount int    `json:"awayYellowCardsCount"`
+	HomeRedCardsCount    int    `json:"homeRedCardsCount"`
+	AwayRedCardsCount    int    `json:"awayRedCardsCount"`
+	Status               string `json:"status"`
+	ExtraTime            string `json:"extraTime"`
+}
+
+// wsClient es una conexión WebSocket con sus partidos suscritos
+type wsClient struct {
+	conn    *websocket.Conn
+	send    chan []byte
+	mu      sync.Mutex
+	matches map[int]bool
+	done    chan struct{}
+	once    sync.Once
+}
+
+// wsHub lleva el registro de las conexiones WebSocket y envía los marcadores periódicos
+type wsHub struct {
+	mu      sync.RWMutex
+	clients map[*wsClient]struct{}
+	stop    chan struct{}
+}
+
+// hub es la instancia global de las conexiones WebSocket
+var hub = &wsHub{clients: make(map[*wsClient]struct{}), stop: make(chan struct{})}
+
+// upgrader convierte las solicitudes HTTP en conexiones WebSocket
+var upgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+	// Se permiten los mismos orígenes que en enableCORS. Los clientes que no son
+	// navegadores no envían Origin y se aceptan siempre.
+	CheckOrigin: func(r *http.Request) bool {
+		origin := r.Header.Get("Origin")
+		return origin == "" || allowedOrigin(origin) != ""
+	},
+	// Los errores del handshake se responden con el mismo formato que el resto de la API
+	Error: func(w http.ResponseWriter, r *http.Request, status int, reason error) {
+		code := codeWebSocketHandshake
+		if status == http.StatusForbidden {
+			code = codeOriginNotAllowed
+		}
+		writeError(w, r, status, code, "No se pudo abrir el WebSocket: "+reason.Error())
+	},
+}
+
+// @Summary Feed en vivo por WebSocket
+// @Description Abre una conexión WebSocket. El cliente envía {"action":"subscribe","matchIds":[1,2]}
+// @Description y recibe goles, tarjetas, tiempo extra y cambios de estado de esos partidos,
+// @Description además de un marcador (type "snapshot") al suscribirse y cada 30 segundos.
+// @Tags stream
+// @Param message body WSClientMessage false "Mensaje de suscripción (se envía por el WebSocket)"
+// @Param access_token query string false "Token JWT, para los clientes que no pueden enviar Authorization"
+// @Success 101 {object} WSServerMessage
+// @Failure 400 {object} Problem
+// @Failure 401 {object} Problem
+// @Failure 403 {object} Problem
+// @Failure 429 {object} Problem
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /ws [get]
+func serveWS(w http.ResponseWriter, r *http.Request) {
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// Upgrade ya respondió al cliente con el error
+		return
+	}
+
+	c := &wsClient{
+		conn:    conn,
+		send:    make(chan []byte, wsSendBuffer),
+		matches: make(map[int]bool),
+		done:    make(chan struct{}),
+	}
+	hub.add(c)
+	liveConnections.WithLabelValues("ws").Inc()
+
+	// Suscribirse a todos los eventos y filtrar según los partidos del cliente
+	sub, _, _ := broker.Subscribe(0, 0)
+
+	go c.writePump()
+	go c.forward(sub)
+	c.readPump()
+
+	// readPump terminó: el cliente se desconectó
+	broker.Unsubscribe(sub)
+	hub.remove(c)
+	liveConnections.WithLabelValues("ws").Dec()
+	c.close()
+}
+
+// add registra una conexión en el hub
+func (h *wsHub) add(c *wsClient) {
+	h.mu.Lock()
+	h.clients[c] = struct{}{}
+	h.mu.Unlock()
+}
+
+// remove elimina una conexión del hub
+func (h *wsHub) remove(c *wsClient) {
+	h.mu.Lock()
+	delete(h.clients, c)
+	h.mu.Unlock()
+}
+
+// run envía periódicamente el marcador de cada partido con suscriptores.
+// Cada marcador se calcula una sola vez por intervalo, sin importar cuántos
+// clientes estén suscritos a ese partido.
+func (h *wsHub) run(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-h.stop:
+			return
+		case <-ticker.C:
+			h.broadcastSnapshots()
+		}
+	}
+}
+
+// broadcastSnapshots calcula y envía los marcadores de los partidos con suscriptores
+func (h *wsHub) broadcastSnapshots() {
+	// Agrupar a los clientes por partido
+	h.mu.RLock()
+	byMatch := make(map[int][]*wsClient)
+	for c := range h.clients {
+		for _, id := range c.subscribed() {
+			byMatch[id] = append(byMatch[id], c)
+		}
+	}
+	h.mu.RUnlock()
+
+	for id, clients := range byMatch {
+		msg, err := snapshotMessage(id)
+		if err != nil {
+			continue
+		}
+		for _, c := range clients {
+			c.enqueue(msg)
+		}
+	}
+}
+
+// Close cierra todas las conexiones WebSocket y detiene los marcadores periódicos
+func (h *wsHub) Close() {
+	close(h.stop)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for c := range h.clients {
+		c.conn.WriteControl(websocket.CloseMessage,
+			websocket.FormatCloseMessage(websocket.CloseGoingAway, "servidor apagándose"),
+			time.Now().Add(wsWriteWait))
+		c.close()
+	}
+}
+
+// fetchScoreSnapshot obtiene el marcador de un partido con la misma agregación que getMatch
+func fetchScoreSnapshot(id int) (ScoreSnapshot, error) {
+	m, err := store.MatchSummary(id)
+	if err != nil {
+		return ScoreSnapshot{}, err
+	}
+
+	return ScoreSnapshot{
+		MatchID:              m.ID,
+		HomeTeam:             m.HomeTeam,
+		AwayTeam:             m.AwayTeam,
+		HomeGoals:            m.HomeGoals,
+		AwayGoals:            m.AwayGoals,
+		HomeYellowCardsCount: m.HomeYellowCardsCount,
+		AwayYellowCardsCount: m.AwayYellowCardsCount,
+		HomeRedCardsCount:    m.HomeRedCardsCount,
+		AwayRedCardsCount:    m.AwayRedCardsCount,
+		Status:               m.Status,
+		ExtraTime:            m.ExtraTime,
+	}, nil
+}
+
+// snapshotMessage construye el mensaje serializado con el marcador de un partido
+func snapshotMessage(id int) ([]byte, error) {
+	snap, err := fetchScoreSnapshot(id)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(WSServerMessage{Type: "snapshot", MatchID: id, Time: time.Now().UTC(), Data: snap})
+}
+
+// subscribed devuelve los IDs de los partidos a los que está suscrito el cliente
+func (c *wsClient) subscribed() []int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	ids := make([]int, 0, len(c.matches))
+	for id := range c.matches {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// isSubscribed indica si el cliente está suscrito al partido
+func (c *wsClient) isSubscribed(id int) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.matches[id]
+}
+
+// enqueue agrega un mensaje a la cola del cliente sin bloquear.
+// Si la cola está llena, el cliente está atrasado y se desconecta.
+func (c *wsClient) enqueue(msg []byte) {
+	select {
+	case <-c.done:
+	case c.send <- msg:
+	default:
+		c.close()
+	}
+}
+
+// sendJSON serializa y encola un mensaje para el cliente
+func (c *wsClient) sendJSON(msg WSServerMessage) {
+	msg.Time = time.Now().UTC()
+	if data, err := json.Marshal(msg); err == nil {
+		c.enqueue(data)
+	}
+}
+
+// close cierra la conexión una sola vez
+func (c *wsClient) close() {
+	c.once.Do(func() {
+		close(c.done)
+		c.conn.Close()
+	})
+}
+
+// forward reenvía al cliente los eventos del broker de los partidos a los que está suscrito
+func (c *wsClient) forward(sub *subscriber) {
+	for {
+		select {
+		case <-c.done:
+			return
+		case ev, ok := <-sub.ch:
+			// El broker cerró el canal (cliente atrasado o servidor apagándose)
+			if !ok {
+				c.close()
+				return
+			}
+			if !c.isSubscribed(ev.MatchID) {
+				continue
+			}
+			c.sendJSON(WSServerMessage{Type: ev.Type, ID: ev.ID, MatchID: ev.MatchID, Data: ev.Data})
+		}
+	}
+}
+
+// readPump procesa los mensajes de suscripción del cliente hasta que se desconecte
+func (c *wsClient) readPump() {
+	c.conn.SetReadLimit(wsMaxMessageSize)
+	c.conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	c.conn.SetPongHandler(func(string) error {
+		return c.conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	})
+
+	for {
+		var msg WSClientMessage
+		if err := c.conn.ReadJSON(&msg); err != nil {
+			// Un JSON inválido no cierra la conexión; cualquier otro error sí
+			switch err.(type) {
+			case *json.SyntaxError, *json.UnmarshalTypeError:
+				c.sendJSON(WSServerMessage{Type: "error", Message: "JSON inválido"})
+				continue
+			}
+			return
+		}
+
+		switch msg.Action {
+		case "subscribe":
+			c.subscribe(msg.MatchIDs)
+		case "unsubscribe":
+			c.mu.Lock()
+			for _, id := range msg.MatchIDs {
+				delete(c.matches, id)
+			}
+			c.mu.Unlock()
+			c.sendJSON(WSServerMessage{Type: "unsubscribed", MatchIDs: msg.MatchIDs})
+		default:
+			c.sendJSON(WSServerMessage{Type: "error", Message: "Acción inválida. Usa subscribe o unsubscribe"})
+		}
+	}
+}
+
+// subscribe agrega los partidos existentes a la suscripción del cliente
+// y le envía el marcador actual de cada uno
+func (c *wsClient) subscribe(ids []int) {
+	var accepted []int
+	var snapshots [][]byte
+	for _, id := range ids {
+		msg, err := snapshotMessage(id)
+		if err != nil {
+			c.sendJSON(WSServerMessage{Type: "error", MatchID: id, Message: "Partido no encontrado"})
+			continue
+		}
+
+		c.mu.Lock()
+		c.matches[id] = true
+		c.mu.Unlock()
+
+		accepted = append(accepted, id)
+		snapshots = append(snapshots, msg)
+	}
+
+	// Confirmar la suscripción antes de enviar los marcadores
+	if len(accepted) > 0 {
+		c.sendJSON(WSServerMessage{Type: "subscribed", MatchIDs: accepted})
+	}
+	for _, msg := range snapshots {
+		c.enqueue(msg)
+	}
+}
+
+// writePump escribe en la conexión los mensajes encolados y envía pings periódicos
+func (c *wsClient) writePump() {
+	ticker := time.NewTicker(wsPingPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.done:
+			return
+		case msg := <-c.send:
+			c.conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+			if err := c.conn.WriteMessage(websocket.TextMessage, msg); err != nil {
+				c.close()
+				return
+			}
+		case <-ticker.C:
+			c.conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+			if err := c.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				c.close()
+				return
+			}
+		}
+	}
+}